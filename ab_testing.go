@@ -0,0 +1,333 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// https://developers.sparkpost.com/api/ab-testing/
+var abTestPathFormat = "/api/v%d/ab-test"
+
+// Values for ABTest.TestMode.
+const (
+	// ABTestBayesian sends the rest of the audience the winning variant once the test completes.
+	ABTestBayesian = "bayesian"
+	// ABTestLearning sends variants to the whole audience, and only reports a winner.
+	ABTestLearning = "learning"
+)
+
+// Values for ABTest.AudienceSelection.
+const (
+	ABTestByPercent    = "percent"
+	ABTestBySampleSize = "sample_size"
+)
+
+// Values for ABTest.Metric, which decides the winner.
+const (
+	ABTestMetricOpens  = "count_unique_confirmed_opened"
+	ABTestMetricClicks = "count_unique_clicked"
+)
+
+// ABTest compares the engagement of messages sent using variants of a stored template.
+// Transmissions take part by using the test's ID as their Content.TemplateID.
+type ABTest struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Status, Version and WinningTemplateID are set by SparkPost.
+	Status            string `json:"status,omitempty"`
+	Version           int    `json:"version,omitempty"`
+	WinningTemplateID string `json:"winning_template_id,omitempty"`
+
+	TestMode          string `json:"test_mode,omitempty"`
+	Metric            string `json:"metric,omitempty"`
+	AudienceSelection string `json:"audience_selection,omitempty"`
+	// TotalSampleSize is used with ABTestBySampleSize: the number of messages sent using
+	// the variants (and default template) before a winner is picked.
+	TotalSampleSize int `json:"total_sample_size,omitempty"`
+	// ConfidenceLevel is the certainty (0-1) needed to pick a winner in ABTestBayesian mode.
+	ConfidenceLevel float64 `json:"confidence_level,omitempty"`
+	// EngagementTimeout is how many hours engagement is counted for after each message is sent.
+	EngagementTimeout int `json:"engagement_timeout,omitempty"`
+
+	DefaultTemplate *ABTemplate  `json:"default_template,omitempty"`
+	Variants        []ABTemplate `json:"variants,omitempty"`
+
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ABTemplate is one of the templates compared by an ABTest, and the share of the audience it's sent to.
+// Either Percent or SampleSize is set, depending on ABTest.AudienceSelection.
+type ABTemplate struct {
+	TemplateID string  `json:"template_id"`
+	Percent    float64 `json:"percent,omitempty"`
+	SampleSize int     `json:"sample_size,omitempty"`
+
+	// Results, reported once messages have been sent.
+	CountAccepted              int     `json:"count_accepted,omitempty"`
+	CountUniqueConfirmedOpened int     `json:"count_unique_confirmed_opened,omitempty"`
+	CountUniqueClicked         int     `json:"count_unique_clicked,omitempty"`
+	EngagementRate             float64 `json:"engagement_rate,omitempty"`
+}
+
+func (t *ABTest) String() string {
+	s := fmt.Sprintf("%s %q (%s, version %d): %d variants", t.ID, t.Name, t.Status, t.Version, len(t.Variants))
+	if t.WinningTemplateID != "" {
+		s += ", won by " + t.WinningTemplateID
+	}
+	return s
+}
+
+// writable returns a copy of the ABTest without the fields SparkPost sets itself.
+func (t *ABTest) writable() ABTest {
+	tmp := *t
+	tmp.Status = ""
+	tmp.Version = 0
+	tmp.WinningTemplateID = ""
+	tmp.CreatedAt = nil
+	tmp.UpdatedAt = nil
+	if t.DefaultTemplate != nil {
+		def := t.DefaultTemplate.writable()
+		tmp.DefaultTemplate = &def
+	}
+	tmp.Variants = make([]ABTemplate, len(t.Variants))
+	for i := range t.Variants {
+		tmp.Variants[i] = t.Variants[i].writable()
+	}
+	return tmp
+}
+
+func (t ABTemplate) writable() ABTemplate {
+	return ABTemplate{TemplateID: t.TemplateID, Percent: t.Percent, SampleSize: t.SampleSize}
+}
+
+// ABTestCreate accepts a populated ABTest and creates it, returning its ID.
+func (c *Client) ABTestCreate(t *ABTest) (id string, res *Response, err error) {
+	if t == nil {
+		err = fmt.Errorf("Create called with nil ABTest")
+		return
+	} else if t.Name == "" || t.DefaultTemplate == nil || len(t.Variants) == 0 {
+		err = fmt.Errorf("ABTest requires a non-empty Name, DefaultTemplate and Variants")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(t.writable())
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(abTestPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var ok bool
+		id, ok = res.Results["id"].(string)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to ABTest creation")
+		}
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("ABTest", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// ABTests returns the latest version of every ABTest.
+func (c *Client) ABTests() ([]ABTest, *Response, error) {
+	path := fmt.Sprintf(abTestPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		tlist := map[string][]ABTest{}
+		if err = json.Unmarshal(body, &tlist); err != nil {
+			return nil, res, err
+		} else if list, ok := tlist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to ABTest list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("ABTest", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// ABTest retrieves the latest version of the ABTest with the specified id,
+// including the results for each template and the winner, once there is one.
+func (c *Client) ABTest(id string) (*ABTest, *Response, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank id")
+	}
+
+	path := fmt.Sprintf(abTestPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*ABTest{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if t, ok := tmp["results"]; ok && t != nil {
+			return t, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to ABTest retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("ABTest", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// ABTestUpdate replaces the settings of the ABTest with the ID of the one provided.
+// Only tests which haven't started running yet may be updated.
+func (c *Client) ABTestUpdate(t *ABTest) (res *Response, err error) {
+	if t == nil {
+		err = fmt.Errorf("Update called with nil ABTest")
+		return
+	} else if t.ID == "" {
+		err = fmt.Errorf("Update called with blank id")
+		return
+	}
+
+	tmp := t.writable()
+	tmp.ID = ""
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(abTestPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, t.ID)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("ABTest", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// ABTestCancel stops the running ABTest with the specified id. Messages already sent are unaffected.
+func (c *Client) ABTestCancel(id string) (res *Response, err error) {
+	if id == "" {
+		err = fmt.Errorf("Cancel called with blank id")
+		return
+	}
+
+	path := fmt.Sprintf(abTestPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s/cancel", c.Config.BaseUrl, path, id)
+	res, err = c.HttpPost(url, nil)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("ABTest", "cancel")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
@@ -0,0 +1,108 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestABTests(t *testing.T) {
+	var calls []string
+	bodies := map[string]map[string]interface{}{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		calls = append(calls, call)
+		if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+			var m map[string]interface{}
+			json.Unmarshal(body, &m)
+			bodies[call] = m
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "POST /api/v1/ab-test":
+			w.Write([]byte(`{"results":{"id":"password-reset","status":"scheduled","version":1}}`))
+		case "GET /api/v1/ab-test":
+			w.Write([]byte(`{"results":[{"id":"password-reset","name":"Password reset","status":"running","version":1,"test_mode":"bayesian"}]}`))
+		case "GET /api/v1/ab-test/password-reset":
+			w.Write([]byte(`{"results":{"id":"password-reset","name":"Password reset","status":"completed","version":1,
+				"test_mode":"bayesian","metric":"count_unique_clicked","audience_selection":"sample_size",
+				"start_time":"2017-06-01T08:00:00Z","winning_template_id":"reset-short",
+				"default_template":{"template_id":"reset","sample_size":100,"count_accepted":100,"count_unique_clicked":10,"engagement_rate":0.1},
+				"variants":[{"template_id":"reset-short","sample_size":100,"count_accepted":100,"count_unique_clicked":25,"engagement_rate":0.25}]}}`))
+		case "GET /api/v1/ab-test/missing":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, _, err := client.ABTestCreate(&sp.ABTest{Name: "No variants"}); err == nil {
+		t.Error("expected an error without variants")
+	}
+
+	test := &sp.ABTest{
+		ID:                "password-reset",
+		Name:              "Password reset",
+		TestMode:          sp.ABTestBayesian,
+		Metric:            sp.ABTestMetricClicks,
+		AudienceSelection: sp.ABTestBySampleSize,
+		DefaultTemplate:   &sp.ABTemplate{TemplateID: "reset", SampleSize: 100},
+		Variants:          []sp.ABTemplate{{TemplateID: "reset-short", SampleSize: 100, CountAccepted: 5}},
+		Status:            "draft",
+	}
+	id, _, err := client.ABTestCreate(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "password-reset" {
+		t.Errorf("unexpected id %q", id)
+	}
+	created := bodies["POST /api/v1/ab-test"]
+	variants, _ := created["variants"].([]interface{})
+	if created["status"] != nil || created["audience_selection"] != "sample_size" || len(variants) != 1 {
+		t.Errorf("unexpected request body %v", created)
+	} else if v := variants[0].(map[string]interface{}); v["count_accepted"] != nil || v["sample_size"] != float64(100) {
+		t.Errorf("unexpected variant %v", v)
+	}
+
+	list, _, err := client.ABTests()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Status != "running" {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	got, _, err := client.ABTest("password-reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.WinningTemplateID != "reset-short" || got.StartTime == nil || got.StartTime.Hour() != 8 ||
+		got.DefaultTemplate.CountUniqueClicked != 10 || got.Variants[0].EngagementRate != 0.25 {
+		t.Errorf("unexpected test %+v", got)
+	}
+	if _, _, err = client.ABTest("missing"); err == nil {
+		t.Error("expected an error for a missing test")
+	}
+
+	test.EngagementTimeout = 24
+	if _, err = client.ABTestUpdate(test); err != nil {
+		t.Fatal(err)
+	}
+	updated := bodies["PUT /api/v1/ab-test/password-reset"]
+	if updated["id"] != nil || updated["engagement_timeout"] != float64(24) {
+		t.Errorf("unexpected update body %v", updated)
+	}
+
+	if _, err = client.ABTestCancel("password-reset"); err != nil {
+		t.Fatal(err)
+	}
+	if last := calls[len(calls)-1]; last != "POST /api/v1/ab-test/password-reset/cancel" {
+		t.Errorf("unexpected call %s", last)
+	}
+}
@@ -0,0 +1,68 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/#/reference/account
+var accountPathFormat = "/api/v%d/account"
+
+// Account is the JSON structure returned from the SparkPost Account API.
+type Account struct {
+	CustomerID   int    `json:"customer_id"`
+	CompanyName  string `json:"company_name,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	Status       string `json:"status,omitempty"`
+	StatusReason string `json:"status_reason,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Updated      string `json:"updated,omitempty"`
+
+	Subscription struct {
+		Code       string `json:"code,omitempty"`
+		Name       string `json:"name,omitempty"`
+		PlanVolume int    `json:"plan_volume,omitempty"`
+	} `json:"subscription,omitempty"`
+}
+
+// Account retrieves information about the account the configured API key belongs to.
+func (c *Client) Account() (*Account, *Response, error) {
+	path := fmt.Sprintf(accountPathFormat, c.Config.ApiVersion)
+	u := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*Account{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if acct, ok := tmp["results"]; ok && acct != nil {
+			return acct, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to Account retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Account", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
@@ -0,0 +1,314 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// https://developers.sparkpost.com/api/alerts/
+var alertsPathFormat = "/api/v%d/alerts"
+
+// Metrics an AlertRule may watch.
+const (
+	AlertMetricHealthScore     = "health_score"
+	AlertMetricBlockBounceRate = "block_bounce_rate"
+	AlertMetricHardBounceRate  = "hard_bounce_rate"
+	AlertMetricSoftBounceRate  = "soft_bounce_rate"
+	AlertMetricMonthlySending  = "monthly_sending_limit"
+)
+
+// AlertRule is an alert configured in SparkPost (distinct from the Alerts raised by SLAMonitor).
+// It watches a metric, notifying its channels when the threshold is crossed.
+type AlertRule struct {
+	ID     int    `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Metric string `json:"metric,omitempty"`
+	// Threshold decides when the alert fires.
+	Threshold AlertThreshold `json:"threshold_evaluator"`
+	Channels  AlertChannels  `json:"channels"`
+	// Filters limit the alert to, for example, particular sending IPs or mailbox providers.
+	Filters []AlertFilter `json:"filters,omitempty"`
+	// Subaccounts limits the alert to particular subaccounts; use -1 for the primary account.
+	Subaccounts   []int `json:"subaccounts,omitempty"`
+	AnySubaccount bool  `json:"any_subaccount,omitempty"`
+	Muted         bool  `json:"muted"`
+
+	// LastTriggered is only set on retrieval.
+	LastTriggered *time.Time `json:"last_triggered,omitempty"`
+}
+
+func (a *AlertRule) String() string {
+	return fmt.Sprintf("%d %q: %s %s %g", a.ID, a.Name, a.Metric, a.Threshold.Operator, a.Threshold.Value)
+}
+
+// AlertThreshold compares a metric with Value. Operator is "gt" or "lt", and Source is
+// "raw", or for health scores also "delta" or "week_over_week".
+type AlertThreshold struct {
+	Source   string  `json:"source,omitempty"`
+	Operator string  `json:"operator,omitempty"`
+	Value    float64 `json:"value"`
+}
+
+// AlertChannels are where notifications of a fired alert go.
+type AlertChannels struct {
+	Emails  []string     `json:"emails,omitempty"`
+	Slack   *AlertTarget `json:"slack,omitempty"`
+	Webhook *AlertTarget `json:"webhook,omitempty"`
+}
+
+// AlertTarget is the URL of a Slack or webhook channel.
+type AlertTarget struct {
+	Target string `json:"target"`
+}
+
+// AlertFilter limits an alert to the listed values of FilterType, for example "sending_ip".
+type AlertFilter struct {
+	FilterType   string   `json:"filter_type"`
+	FilterValues []string `json:"filter_values"`
+}
+
+// AlertIncident is one occasion an AlertRule fired.
+type AlertIncident struct {
+	ID      int    `json:"id"`
+	AlertID int    `json:"alert_id"`
+	Status  string `json:"status"`
+	// FirstFired, LastFired and ResolvedAt are nil until they've happened.
+	FirstFired *time.Time `json:"first_fired,omitempty"`
+	LastFired  *time.Time `json:"last_fired,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	// FirstValue and LastValue are the values of the metric at those times.
+	FirstValue float64 `json:"first_fired_value"`
+	LastValue  float64 `json:"last_fired_value"`
+}
+
+func (a *AlertRule) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("AlertRule requires a non-empty Name")
+	} else if a.Metric == "" {
+		return fmt.Errorf("AlertRule requires a non-empty Metric")
+	}
+	return nil
+}
+
+// AlertRuleCreate accepts a populated AlertRule and creates it, returning its ID.
+func (c *Client) AlertRuleCreate(a *AlertRule) (id int, res *Response, err error) {
+	if a == nil {
+		err = fmt.Errorf("Create called with nil AlertRule")
+		return
+	}
+	if err = a.validate(); err != nil {
+		return
+	}
+
+	tmp := *a
+	tmp.LastTriggered = nil
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(alertsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		// numbers are decoded as float64
+		f, ok := res.Results["id"].(float64)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to AlertRule creation")
+		}
+		id = int(f)
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("AlertRule", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// AlertRules returns all AlertRules.
+func (c *Client) AlertRules() ([]AlertRule, *Response, error) {
+	path := fmt.Sprintf(alertsPathFormat, c.Config.ApiVersion)
+	var alerts []AlertRule
+	res, err := c.alertsGet(fmt.Sprintf("%s%s", c.Config.BaseUrl, path), "list", &alerts)
+	return alerts, res, err
+}
+
+// AlertRule retrieves the AlertRule with the specified id.
+func (c *Client) AlertRule(id int) (*AlertRule, *Response, error) {
+	if id <= 0 {
+		return nil, nil, fmt.Errorf("Retrieve called with invalid id")
+	}
+	path := fmt.Sprintf(alertsPathFormat, c.Config.ApiVersion)
+	var alert *AlertRule
+	res, err := c.alertsGet(fmt.Sprintf("%s%s/%d", c.Config.BaseUrl, path, id), "retrieve", &alert)
+	if err == nil && alert == nil {
+		err = fmt.Errorf("Unexpected response to AlertRule retrieve")
+	}
+	return alert, res, err
+}
+
+// AlertIncidents returns the incidents of the AlertRule with the specified id, most recent first.
+func (c *Client) AlertIncidents(id int) ([]AlertIncident, *Response, error) {
+	if id <= 0 {
+		return nil, nil, fmt.Errorf("Incidents called with invalid id")
+	}
+	path := fmt.Sprintf(alertsPathFormat, c.Config.ApiVersion)
+	var incidents []AlertIncident
+	res, err := c.alertsGet(fmt.Sprintf("%s%s/%d/incidents", c.Config.BaseUrl, path, id), "retrieve", &incidents)
+	return incidents, res, err
+}
+
+// alertsGet decodes the results of a GET request into v.
+func (c *Client) alertsGet(url, verb string, v interface{}) (*Response, error) {
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return res, err
+		}
+		tmp := map[string]json.RawMessage{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return res, err
+		} else if results, ok := tmp["results"]; ok {
+			return res, json.Unmarshal(results, v)
+		}
+		return res, fmt.Errorf("Unexpected response to AlertRule %s", verb)
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("AlertRule", verb)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// AlertRuleUpdate replaces the AlertRule with the ID of the one provided.
+func (c *Client) AlertRuleUpdate(a *AlertRule) (res *Response, err error) {
+	if a == nil {
+		err = fmt.Errorf("Update called with nil AlertRule")
+		return
+	} else if a.ID <= 0 {
+		err = fmt.Errorf("Update called with invalid id")
+		return
+	}
+	if err = a.validate(); err != nil {
+		return
+	}
+
+	tmp := *a
+	tmp.ID = 0
+	tmp.LastTriggered = nil
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(alertsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%d", c.Config.BaseUrl, path, a.ID)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("AlertRule", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// AlertRuleDelete removes the AlertRule with the specified id.
+func (c *Client) AlertRuleDelete(id int) (res *Response, err error) {
+	if id <= 0 {
+		err = fmt.Errorf("Delete called with invalid id")
+		return
+	}
+
+	path := fmt.Sprintf(alertsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%d", c.Config.BaseUrl, path, id)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("AlertRule", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
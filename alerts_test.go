@@ -0,0 +1,111 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestAlerts(t *testing.T) {
+	var calls []string
+	bodies := map[string]map[string]interface{}{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		calls = append(calls, call)
+		if r.Method == "POST" || r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+			var m map[string]interface{}
+			json.Unmarshal(body, &m)
+			bodies[call] = m
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(204)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "POST /api/v1/alerts":
+			w.Write([]byte(`{"results":{"id":12}}`))
+		case "GET /api/v1/alerts":
+			w.Write([]byte(`{"results":[{"id":12,"name":"Bounces","metric":"hard_bounce_rate","threshold_evaluator":{"source":"raw","operator":"gt","value":5},"channels":{"emails":["ops@example.com"]},"muted":false,"last_triggered":"2019-03-01T10:00:00.000Z"}]}`))
+		case "GET /api/v1/alerts/12":
+			w.Write([]byte(`{"results":{"id":12,"name":"Bounces","metric":"hard_bounce_rate","threshold_evaluator":{"operator":"gt","value":5},"channels":{"slack":{"target":"https://hooks.slack.com/x"}},"filters":[{"filter_type":"sending_ip","filter_values":["1.2.3.4"]}],"muted":true}}`))
+		case "GET /api/v1/alerts/12/incidents":
+			w.Write([]byte(`{"results":[{"id":3,"alert_id":12,"status":"resolved","first_fired":"2019-03-01T10:00:00.000Z","resolved_at":"2019-03-01T12:00:00.000Z","first_fired_value":6.5,"last_fired_value":7}]}`))
+		case "GET /api/v1/alerts/99":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, _, err := client.AlertRuleCreate(&sp.AlertRule{Name: "No metric"}); err == nil {
+		t.Error("expected an error without a metric")
+	}
+
+	alert := &sp.AlertRule{
+		Name:      "Bounces",
+		Metric:    sp.AlertMetricHardBounceRate,
+		Threshold: sp.AlertThreshold{Source: "raw", Operator: "gt", Value: 5},
+		Channels:  sp.AlertChannels{Emails: []string{"ops@example.com"}},
+	}
+	id, _, err := client.AlertRuleCreate(alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 12 {
+		t.Errorf("unexpected id %d", id)
+	}
+	created := bodies["POST /api/v1/alerts"]
+	if created["metric"] != "hard_bounce_rate" || created["threshold_evaluator"].(map[string]interface{})["value"] != 5.0 {
+		t.Errorf("unexpected request body %v", created)
+	}
+
+	alerts, _, err := client.AlertRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].LastTriggered == nil || alerts[0].Channels.Emails[0] != "ops@example.com" {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+
+	got, _, err := client.AlertRule(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Muted || got.Channels.Slack == nil || len(got.Filters) != 1 || got.Filters[0].FilterValues[0] != "1.2.3.4" {
+		t.Errorf("unexpected alert %+v", got)
+	}
+	if _, _, err = client.AlertRule(99); err == nil {
+		t.Error("expected an error for a missing alert")
+	}
+
+	incidents, _, err := client.AlertIncidents(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 1 || incidents[0].Status != "resolved" || incidents[0].LastFired != nil || incidents[0].FirstValue != 6.5 {
+		t.Errorf("unexpected incidents %+v", incidents)
+	}
+
+	got.Muted = false
+	if _, err = client.AlertRuleUpdate(got); err != nil {
+		t.Fatal(err)
+	}
+	updated := bodies["PUT /api/v1/alerts/12"]
+	if _, ok := updated["id"]; ok || updated["muted"] != false {
+		t.Errorf("unexpected update body %v", updated)
+	}
+
+	if _, err = client.AlertRuleDelete(12); err != nil {
+		t.Fatal(err)
+	}
+	if calls[len(calls)-1] != "DELETE /api/v1/alerts/12" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
@@ -0,0 +1,78 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strings"
+)
+
+// ArchivePolicy has a copy of every message sent kept in an archive mailbox, for example
+// for legal hold. Copies are sent as BCCs: each primary Recipient gets an archive
+// Recipient with header_to set to their address and the same substitution data, so the
+// copy renders exactly as they saw it. Set it as Client.Archive to apply it on every Send.
+type ArchivePolicy struct {
+	// Address is the archive mailbox.
+	Address string
+	// Domains, if set, are the only domains Address may be in, so a typo or a bad
+	// config value can't send copies of mail outside the organization.
+	Domains []string
+}
+
+// Validate checks Address is a valid email address in one of Domains.
+func (p *ArchivePolicy) Validate() error {
+	addr, err := mail.ParseAddress(p.Address)
+	if err != nil {
+		return fmt.Errorf("Invalid archive address [%s]: %s", p.Address, err)
+	}
+	if len(p.Domains) == 0 {
+		return nil
+	}
+	domain := addr.Address[strings.LastIndex(addr.Address, "@")+1:]
+	for _, d := range p.Domains {
+		if strings.EqualFold(domain, d) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Archive address [%s] isn't in an allowed domain (%s)", p.Address, strings.Join(p.Domains, ", "))
+}
+
+// ArchiveRecipients returns a copy of the provided inline Recipients, with an archive
+// copy of each primary (not CC or BCC) Recipient added. Recipients which already have an
+// archive copy don't get another. Stored recipient lists can't be archived, and return an error.
+func (p *ArchivePolicy) ArchiveRecipients(recips interface{}) ([]Recipient, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := inlineRecipients(recips)
+	if err != nil {
+		return nil, err
+	} else if list == nil {
+		return nil, fmt.Errorf("Can't archive [%s] Recipients, only inline Recipients", reflect.TypeOf(recips))
+	}
+
+	archived := map[string]bool{}
+	var primaries []Recipient
+	var emails []string
+	for _, r := range list {
+		addr, err := ParseAddress(r.Address)
+		if err != nil {
+			return nil, err
+		}
+		if addr.HeaderTo == "" || strings.EqualFold(addr.HeaderTo, addr.Email) {
+			primaries = append(primaries, r)
+			emails = append(emails, addr.Email)
+		} else if strings.EqualFold(addr.Email, p.Address) {
+			archived[strings.ToLower(addr.HeaderTo)] = true
+		}
+	}
+
+	for i, r := range primaries {
+		if archived[strings.ToLower(emails[i])] {
+			continue
+		}
+		r.Address = Address{Email: p.Address, HeaderTo: emails[i]}
+		list = append(list, r)
+	}
+	return list, nil
+}
@@ -0,0 +1,80 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestArchivePolicy(t *testing.T) {
+	p := &sp.ArchivePolicy{Address: "archive@corp.example.com", Domains: []string{"Corp.Example.com"}}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{"archive@gmail.com", "not an address", ""} {
+		if err := (&sp.ArchivePolicy{Address: bad, Domains: p.Domains}).Validate(); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	recips := []sp.Recipient{
+		{Address: "a@example.com", SubstitutionData: map[string]string{"name": "A"}},
+		{Address: sp.Address{Email: "boss@example.com", HeaderTo: "a@example.com"}},
+		{Address: "b@example.com"},
+	}
+	list, err := p.ArchiveRecipients(recips)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 5 || len(recips) != 3 {
+		t.Fatalf("expected 2 archive copies added to a copy, got %d recipients", len(list))
+	}
+	copyA := list[3]
+	if addr := copyA.Address.(sp.Address); addr.Email != p.Address || addr.HeaderTo != "a@example.com" {
+		t.Errorf("unexpected archive address %+v", addr)
+	}
+	if copyA.SubstitutionData.(map[string]string)["name"] != "A" {
+		t.Error("archive copy should keep the recipient's substitution data")
+	}
+
+	// applying the policy again doesn't add more copies
+	if again, err := p.ArchiveRecipients(list); err != nil || len(again) != 5 {
+		t.Errorf("expected the policy to be idempotent, got %d recipients, %v", len(again), err)
+	}
+	if _, err = p.ArchiveRecipients(map[string]string{"list_id": "vips"}); err == nil {
+		t.Error("expected an error for a stored recipient list")
+	}
+}
+
+func TestClientArchive(t *testing.T) {
+	var body struct {
+		Recipients []struct {
+			Address sp.Address `json:"address"`
+		} `json:"recipients"`
+	}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+	client.Archive = &sp.ArchivePolicy{Address: "archive@corp.example.com"}
+
+	tx := &sp.Transmission{
+		Recipients: []string{"a@example.com"},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+	}
+	if _, _, err := client.Send(tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Recipients) != 2 || body.Recipients[1].Address.HeaderTo != "a@example.com" {
+		t.Errorf("unexpected recipients %+v", body.Recipients)
+	}
+	if recips, ok := tx.Recipients.([]sp.Recipient); !ok || len(recips) != 1 {
+		t.Errorf("Send added archive copies to the caller's Transmission: %v", tx.Recipients)
+	}
+}
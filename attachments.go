@@ -0,0 +1,83 @@
+package gosparkpost
+
+import "fmt"
+
+// ExpandAttachments handles Recipients with their own Attachments, which
+// the Transmissions API doesn't support directly. Each such Recipient is
+// split out into a Transmission of its own, with their Attachments added to
+// the shared Content. The remaining Recipients stay together in one Transmission.
+// If no Recipient has Attachments, the original Transmission is returned as-is.
+func ExpandAttachments(t *Transmission) ([]*Transmission, error) {
+	if t == nil {
+		return nil, fmt.Errorf("ExpandAttachments called with nil Transmission")
+	}
+
+	var list []Recipient
+	switch rVal := t.Recipients.(type) {
+	case []Recipient:
+		list = rVal
+	case []interface{}:
+		for _, r := range rVal {
+			recip, ok := r.(Recipient)
+			if !ok {
+				return nil, fmt.Errorf("Failed to parse inline Transmission.Recipient list")
+			}
+			list = append(list, recip)
+		}
+	default:
+		// strings and stored lists can't carry attachments
+		return []*Transmission{t}, nil
+	}
+
+	var shared, individual []Recipient
+	for _, r := range list {
+		if len(r.Attachments) > 0 {
+			individual = append(individual, r)
+		} else {
+			shared = append(shared, r)
+		}
+	}
+	if len(individual) == 0 {
+		return []*Transmission{t}, nil
+	}
+
+	content, ok := t.Content.(Content)
+	if !ok {
+		return nil, fmt.Errorf("Per-recipient attachments require inline Content, not [%T]", t.Content)
+	}
+
+	txs := make([]*Transmission, 0, len(individual)+1)
+	if len(shared) > 0 {
+		tx := *t
+		tx.Recipients = shared
+		txs = append(txs, &tx)
+	}
+	for _, r := range individual {
+		rc := content
+		// copy so Recipients don't see each other's attachments
+		rc.Attachments = make([]Attachment, 0, len(content.Attachments)+len(r.Attachments))
+		rc.Attachments = append(rc.Attachments, content.Attachments...)
+		rc.Attachments = append(rc.Attachments, r.Attachments...)
+
+		tx := *t
+		tx.Content = rc
+		tx.Recipients = []Recipient{r}
+		txs = append(txs, &tx)
+	}
+	return txs, nil
+}
+
+// SendAll sends each of the provided Transmissions in order, for example
+// the output of ExpandAttachments. Sending stops at the first error;
+// ids holds the Transmissions created before that point.
+func (c *Client) SendAll(txs []*Transmission) (ids []string, err error) {
+	for _, tx := range txs {
+		var id string
+		id, _, err = c.Send(tx)
+		if err != nil {
+			return
+		}
+		ids = append(ids, id)
+	}
+	return
+}
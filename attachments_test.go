@@ -0,0 +1,49 @@
+package gosparkpost_test
+
+import (
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestExpandAttachments(t *testing.T) {
+	shared := sp.Attachment{MIMEType: "text/plain", Filename: "terms.txt", B64Data: "dGVybXM="}
+	tx := &sp.Transmission{
+		Content: sp.Content{
+			Subject:     "Your invoice",
+			Text:        "see attached",
+			From:        "billing@example.com",
+			Attachments: []sp.Attachment{shared},
+		},
+		Recipients: []sp.Recipient{
+			{Address: "a@example.com", Attachments: []sp.Attachment{
+				{MIMEType: "application/pdf", Filename: "a.pdf", B64Data: "YQ=="},
+			}},
+			{Address: "b@example.com"},
+			{Address: "c@example.com", Attachments: []sp.Attachment{
+				{MIMEType: "application/pdf", Filename: "c.pdf", B64Data: "Yw=="},
+			}},
+		},
+	}
+
+	txs, err := sp.ExpandAttachments(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 {
+		t.Fatalf("expected 3 transmissions, got %d", len(txs))
+	}
+
+	for i, want := range []int{1, 2, 2} {
+		atts := txs[i].Content.(sp.Content).Attachments
+		if len(atts) != want {
+			t.Errorf("transmission %d: expected %d attachments, got %d", i, want, len(atts))
+		}
+		if err = txs[i].Validate(); err != nil {
+			t.Errorf("transmission %d: %v", i, err)
+		}
+	}
+	if len(tx.Content.(sp.Content).Attachments) != 1 {
+		t.Error("expected original Content to be left alone")
+	}
+}
@@ -0,0 +1,100 @@
+package gosparkpost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Actions recorded by Client.Audit.
+const (
+	AuditSuppressionUpsert = "suppression.upsert"
+	AuditSuppressionDelete = "suppression.delete"
+)
+
+// AuditRecord describes one change to the suppression list made through this client.
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	// Actor and Reason are taken from the context the change was made with,
+	// see WithAuditActor and WithAuditReason.
+	Actor  string `json:"actor,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Entries are the entries upserted, or (with only Email set) the address deleted.
+	Entries []SuppressionEntry `json:"entries"`
+	// Error is set when the change failed, so attempted changes are recorded too.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink receives an AuditRecord for each suppression list change.
+type AuditSink interface {
+	Audit(ctx context.Context, record *AuditRecord) error
+}
+
+// AuditSinkFunc allows a function to be used as an AuditSink.
+type AuditSinkFunc func(ctx context.Context, record *AuditRecord) error
+
+func (f AuditSinkFunc) Audit(ctx context.Context, record *AuditRecord) error {
+	return f(ctx, record)
+}
+
+// JSONAuditSink writes each record to W as a line of JSON.
+type JSONAuditSink struct {
+	W  io.Writer
+	mu sync.Mutex
+}
+
+func (s *JSONAuditSink) Audit(ctx context.Context, record *AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.W.Write(append(line, '\n'))
+	return err
+}
+
+type auditKey int
+
+const (
+	auditActorKey auditKey = iota
+	auditReasonKey
+)
+
+// WithAuditActor returns a copy of ctx which attributes changes to actor, for example a user name.
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey, actor)
+}
+
+// WithAuditReason returns a copy of ctx which records why changes are being made.
+func WithAuditReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, auditReasonKey, reason)
+}
+
+// audit passes a record of a change to c.Audit, if set. The error returned is the one
+// which failed the change, with any error from the sink joined to it.
+func (c *Client) audit(ctx context.Context, action string, entries []SuppressionEntry, res *Response, err error) error {
+	if c.Audit == nil {
+		return err
+	}
+	record := &AuditRecord{Time: time.Now(), Action: action, Entries: entries}
+	record.Actor, _ = ctx.Value(auditActorKey).(string)
+	record.Reason, _ = ctx.Value(auditReasonKey).(string)
+	if err != nil {
+		record.Error = err.Error()
+	} else if res != nil && res.HTTP != nil && (res.HTTP.StatusCode < 200 || res.HTTP.StatusCode > 299) {
+		record.Error = res.HTTP.Status
+	}
+
+	if aerr := c.Audit.Audit(ctx, record); aerr != nil {
+		if err == nil {
+			return fmt.Errorf("Audit failed: %s", aerr)
+		}
+		return fmt.Errorf("%s; Audit failed: %s", err, aerr)
+	}
+	return err
+}
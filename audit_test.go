@@ -0,0 +1,87 @@
+package gosparkpost_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSuppressionAudit(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "PUT":
+			w.Write([]byte(`{"results":{"message":"Suppression List successfully updated"}}`))
+		case "DELETE":
+			if strings.HasSuffix(r.URL.Path, "/missing@example.com") {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors":[{"message":"Recipient could not be found"}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	defer done()
+
+	var buf bytes.Buffer
+	client.Audit = &sp.JSONAuditSink{W: &buf}
+
+	ctx := sp.WithAuditReason(sp.WithAuditActor(context.Background(), "alice"), "ticket 42")
+	entries := []sp.SuppressionEntry{{Email: "a@example.com", Transactional: true}}
+	if err := client.SuppressionInsertOrUpdateContext(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SuppressionDeleteContext(ctx, "a@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	client.SuppressionDelete("missing@example.com")
+
+	var records []sp.AuditRecord
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r sp.AuditRecord
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	up := records[0]
+	if up.Action != sp.AuditSuppressionUpsert || up.Actor != "alice" || up.Reason != "ticket 42" ||
+		up.Error != "" || up.Time.IsZero() || len(up.Entries) != 1 || !up.Entries[0].Transactional {
+		t.Errorf("unexpected upsert record %+v", up)
+	}
+	del := records[1]
+	if del.Action != sp.AuditSuppressionDelete || del.Actor != "alice" || del.Entries[0].Email != "a@example.com" || del.Error != "" {
+		t.Errorf("unexpected delete record %+v", del)
+	}
+	failed := records[2]
+	if failed.Actor != "" || failed.Error == "" {
+		t.Errorf("expected an unattributed failure, got %+v", failed)
+	}
+}
+
+func TestSuppressionAuditError(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{}}`))
+	})
+	defer done()
+
+	client.Audit = sp.AuditSinkFunc(func(ctx context.Context, r *sp.AuditRecord) error {
+		return fmt.Errorf("sink down")
+	})
+	err := client.SuppressionInsertOrUpdate([]sp.SuppressionEntry{{Email: "a@example.com"}})
+	if err == nil || err.Error() != "Audit failed: sink down" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
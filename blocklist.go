@@ -0,0 +1,84 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// https://developers.sparkpost.com/api/blocklist-monitors/
+var blocklistIncidentsPathFormat = "/api/v%d/blocklist-monitors/incidents"
+
+// Blocklist incident statuses.
+const (
+	BlocklistIncidentActive   = "active"
+	BlocklistIncidentResolved = "resolved"
+)
+
+// BlocklistIncident is a monitored domain or IP being listed on a blocklist.
+type BlocklistIncident struct {
+	ID            string `json:"id"`
+	Resource      string `json:"resource"`
+	BlocklistName string `json:"blocklist_name"`
+	Status        string `json:"status"`
+	// OccurredAt and ResolvedAt are RFC 3339 timestamps. ResolvedAt is blank while the incident is active.
+	OccurredAt string `json:"occurred_at"`
+	ResolvedAt string `json:"resolved_at,omitempty"`
+}
+
+// BlocklistParams filters BlocklistIncidents.
+type BlocklistParams struct {
+	From time.Time
+	To   time.Time
+	// Resources are the monitored domains or IPs to return incidents for. Defaults to all of them.
+	Resources []string
+	// Status is one of the BlocklistIncident constants. Defaults to both.
+	Status string
+}
+
+// BlocklistIncidents returns the blocklist incidents of monitored resources matching params, which may be nil.
+func (c *Client) BlocklistIncidents(params *BlocklistParams) ([]BlocklistIncident, *Response, error) {
+	if params == nil {
+		params = &BlocklistParams{}
+	}
+	path := fmt.Sprintf(blocklistIncidentsPathFormat, c.Config.ApiVersion)
+	u := QueryBuilder{}.
+		Time("from", params.From, QueryTimeFormat).
+		Time("to", params.To, QueryTimeFormat).
+		List("resources", params.Resources...).
+		Set("status", params.Status).
+		URL(c.Config.BaseUrl + path)
+
+	res, err := c.HttpGet(u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		if body, err = res.ReadBody(); err != nil {
+			return nil, res, err
+		}
+		var wrapper struct {
+			Results []BlocklistIncident `json:"results"`
+		}
+		if err = json.Unmarshal(body, &wrapper); err != nil {
+			return nil, res, err
+		}
+		return wrapper.Results, res, nil
+	}
+
+	if err = res.ParseResponse(); err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		if err = res.PrettyError("Blocklist incidents", "retrieve"); err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
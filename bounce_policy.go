@@ -0,0 +1,244 @@
+package gosparkpost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// ErrDomainsPaused is returned by Send when every Recipient is at a domain paused by the BouncePolicy.
+var ErrDomainsPaused = errors.New("all recipients are at paused domains")
+
+// BouncePolicy protects sending reputation from recipient domains which are hard bouncing,
+// for example after a bad list import or a mailbox provider retiring a domain. Evaluate
+// works out each domain's hard bounce rate from events, and for domains over Threshold
+// pauses sends to them and/or suppresses the recipients which bounced.
+// Set it as Client.BouncePolicy to have Send leave out Recipients at paused domains.
+type BouncePolicy struct {
+	// Window is how far back Evaluate looks, and how long domains are paused for.
+	// Defaults to 24 hours.
+	Window time.Duration
+	// Threshold is the fraction of messages to a domain which may hard bounce. Defaults to 0.1.
+	Threshold float64
+	// MinMessages is how many messages a domain needs before it's judged. Defaults to 100.
+	MinMessages int
+
+	Pause    bool
+	Suppress bool
+	// DryRun, if set, has Evaluate report what it would do without doing it.
+	DryRun bool
+	// OnAction, if set, is passed each action Evaluate takes (or would take).
+	OnAction func(BounceAction)
+	// OnPaused, if set, is passed the Recipients Send leaves out.
+	OnPaused func([]Recipient)
+
+	mu     sync.Mutex
+	paused map[string]time.Time
+}
+
+// BounceAction is what a BouncePolicy did about one recipient domain.
+type BounceAction struct {
+	Domain   string
+	Messages int
+	Rate     float64
+	// PausedUntil is set if the domain was paused.
+	PausedUntil time.Time
+	// Suppressed are the hard-bounced addresses added to the suppression list, once each.
+	Suppressed []string
+	DryRun     bool
+}
+
+func (a BounceAction) String() string {
+	s := fmt.Sprintf("%s: %.1f%% of %d messages hard bounced", a.Domain, a.Rate*100, a.Messages)
+	if !a.PausedUntil.IsZero() {
+		s += fmt.Sprintf(", paused until %s", a.PausedUntil.Format(time.RFC3339))
+	}
+	if len(a.Suppressed) > 0 {
+		s += fmt.Sprintf(", suppressed %d addresses", len(a.Suppressed))
+	}
+	if a.DryRun {
+		s += " (dry run)"
+	}
+	return s
+}
+
+func (p *BouncePolicy) window() time.Duration {
+	if p.Window <= 0 {
+		return 24 * time.Hour
+	}
+	return p.Window
+}
+
+// Evaluate examines the deliveries and bounces of the last Window, and acts on each recipient
+// domain over the Threshold. Suppressions are recorded by Client.Audit, with ctx, like other
+// suppression list changes.
+func (p *BouncePolicy) Evaluate(ctx context.Context, c *Client) ([]BounceAction, error) {
+	threshold, min := p.Threshold, p.MinMessages
+	if threshold <= 0 {
+		threshold = 0.1
+	}
+	if min <= 0 {
+		min = 100
+	}
+	hard := map[string]bool{}
+	for _, class := range strings.Split(hardBounceClasses, ",") {
+		hard[class] = true
+	}
+
+	type domainStats struct {
+		messages int
+		bounces  int
+		// bounced are the addresses which bounced, once each, as first seen
+		bounced []string
+		seen    map[string]bool
+	}
+	stats := map[string]*domainStats{}
+	count := func(recipient string, bounced bool) {
+		domain := strings.ToLower(recipient[strings.LastIndex(recipient, "@")+1:])
+		s := stats[domain]
+		if s == nil {
+			s = &domainStats{}
+			stats[domain] = s
+		}
+		s.messages++
+		if bounced {
+			s.bounces++
+			if s.seen == nil {
+				s.seen = map[string]bool{}
+			}
+			if key := strings.ToLower(recipient); !s.seen[key] {
+				s.seen[key] = true
+				s.bounced = append(s.bounced, recipient)
+			}
+		}
+	}
+
+	now := time.Now()
+	page, err := c.SearchEvents(&EventsParams{
+		Events: []string{"delivery", "bounce"},
+		From:   now.Add(-p.window()),
+		To:     now,
+	})
+	for err == nil {
+		for _, e := range page.Events {
+			switch ev := e.(type) {
+			case *events.Delivery:
+				count(ev.Recipient, false)
+			case *events.Bounce:
+				count(ev.Recipient, hard[ev.BounceClass])
+			}
+		}
+		page, err = page.Next()
+	}
+	if err != ErrEmptyPage {
+		return nil, err
+	}
+
+	domains := make([]string, 0, len(stats))
+	for d := range stats {
+		domains = append(domains, d)
+	}
+	sort.Strings(domains)
+
+	var actions []BounceAction
+	for _, d := range domains {
+		s := stats[d]
+		r := rate(s.bounces, s.messages)
+		if s.messages < min || r <= threshold {
+			continue
+		}
+		action := BounceAction{Domain: d, Messages: s.messages, Rate: r, DryRun: p.DryRun}
+		if p.Pause {
+			action.PausedUntil = now.Add(p.window())
+			if !p.DryRun {
+				p.pause(d, action.PausedUntil)
+			}
+		}
+		if p.Suppress {
+			action.Suppressed = s.bounced
+			if !p.DryRun {
+				entries := make([]SuppressionEntry, len(s.bounced))
+				for i, email := range s.bounced {
+					entries[i] = SuppressionEntry{
+						Email:            email,
+						Transactional:    true,
+						NonTransactional: true,
+						Description:      fmt.Sprintf("hard bounce at %s, which was over the bounce threshold", d),
+					}
+				}
+				actx := WithAuditReason(ctx, action.String())
+				if err = c.SuppressionInsertOrUpdateContext(actx, entries); err != nil {
+					return actions, err
+				}
+			}
+		}
+		if p.OnAction != nil {
+			p.OnAction(action)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+func (p *BouncePolicy) pause(domain string, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.paused == nil {
+		p.paused = map[string]time.Time{}
+	}
+	p.paused[domain] = until
+}
+
+// Paused reports whether sends to domain are paused.
+func (p *BouncePolicy) Paused(domain string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.paused[strings.ToLower(domain)]
+	if ok && time.Now().After(until) {
+		delete(p.paused, strings.ToLower(domain))
+		return false
+	}
+	return ok
+}
+
+// Resume lifts the pause on sends to domain.
+func (p *BouncePolicy) Resume(domain string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.paused, strings.ToLower(domain))
+}
+
+// Filter returns the provided Recipients whose domains aren't paused, passing the others to
+// OnPaused. It returns nil for a stored recipient list, which can't be filtered.
+func (p *BouncePolicy) Filter(recips interface{}) ([]Recipient, error) {
+	list, err := inlineRecipients(recips)
+	if err != nil || list == nil {
+		return nil, err
+	}
+	allowed := make([]Recipient, 0, len(list))
+	var paused []Recipient
+	for _, r := range list {
+		addr, err := ParseAddress(r.Address)
+		if err != nil {
+			return nil, err
+		}
+		if p.Paused(addr.Email[strings.LastIndex(addr.Email, "@")+1:]) {
+			paused = append(paused, r)
+		} else {
+			allowed = append(allowed, r)
+		}
+	}
+	if len(paused) > 0 && p.OnPaused != nil {
+		p.OnPaused(paused)
+	}
+	if len(allowed) == 0 {
+		return nil, ErrDomainsPaused
+	}
+	return allowed, nil
+}
@@ -0,0 +1,130 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func bouncePolicyClient(t *testing.T, suppressed *[]string) (*sp.Client, func()) {
+	return newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/events/message":
+			var evs []string
+			for i := 0; i < 4; i++ {
+				evs = append(evs, fmt.Sprintf(`{"type":"delivery","rcpt_to":"ok%d@good.com"}`, i))
+			}
+			evs = append(evs,
+				`{"type":"delivery","rcpt_to":"a@bad.com"}`,
+				`{"type":"bounce","bounce_class":"10","rcpt_to":"b@Bad.com"}`,
+				`{"type":"bounce","bounce_class":"30","rcpt_to":"c@bad.com"}`,
+				`{"type":"bounce","bounce_class":"21","rcpt_to":"d@bad.com"}`,
+				// bounced again: counted, but only suppressed once
+				`{"type":"bounce","bounce_class":"10","rcpt_to":"B@bad.com"}`,
+				`{"type":"bounce","bounce_class":"10","rcpt_to":"e@good.com"}`)
+			fmt.Fprintf(w, `{"results":[%s],"links":{}}`, strings.Join(evs, ","))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/suppression-list"):
+			var body struct {
+				Recipients []struct {
+					Email string `json:"email"`
+				} `json:"recipients"`
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(b, &body); err != nil {
+				t.Fatal(err)
+			}
+			for _, rcpt := range body.Recipients {
+				*suppressed = append(*suppressed, rcpt.Email)
+			}
+			fmt.Fprint(w, `{"results":{"message":"Suppression List successfully updated"}}`)
+		case r.URL.Path == "/api/v1/transmissions":
+			fmt.Fprint(w, `{"results":{"id":"1"}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+}
+
+func TestBouncePolicyDryRun(t *testing.T) {
+	var suppressed []string
+	client, done := bouncePolicyClient(t, &suppressed)
+	defer done()
+
+	var reported []sp.BounceAction
+	p := &sp.BouncePolicy{
+		MinMessages: 4,
+		Threshold:   0.3,
+		Pause:       true,
+		Suppress:    true,
+		DryRun:      true,
+		OnAction:    func(a sp.BounceAction) { reported = append(reported, a) },
+	}
+	actions, err := p.Evaluate(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].Domain != "bad.com" || actions[0].Messages != 5 || actions[0].Rate != 0.6 {
+		t.Fatalf("unexpected actions %+v", actions)
+	}
+	if len(reported) != 1 || len(actions[0].Suppressed) != 2 || actions[0].PausedUntil.IsZero() {
+		t.Errorf("unexpected action %+v", actions[0])
+	}
+	if !strings.HasSuffix(actions[0].String(), "(dry run)") {
+		t.Errorf("unexpected description %q", actions[0].String())
+	}
+	if len(suppressed) != 0 || p.Paused("bad.com") {
+		t.Error("dry run acted on the domain")
+	}
+}
+
+func TestBouncePolicy(t *testing.T) {
+	var suppressed []string
+	client, done := bouncePolicyClient(t, &suppressed)
+	defer done()
+
+	var paused []sp.Recipient
+	p := &sp.BouncePolicy{
+		MinMessages: 4,
+		Threshold:   0.3,
+		Pause:       true,
+		Suppress:    true,
+		OnPaused:    func(r []sp.Recipient) { paused = r },
+	}
+	if _, err := p.Evaluate(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if len(suppressed) != 2 || suppressed[0] != "b@Bad.com" || suppressed[1] != "c@bad.com" {
+		t.Errorf("unexpected suppressions %q", suppressed)
+	}
+	if !p.Paused("BAD.com") || p.Paused("good.com") {
+		t.Error("expected only bad.com to be paused")
+	}
+
+	client.BouncePolicy = p
+	tx := &sp.Transmission{
+		Recipients: []string{"x@bad.com", "y@good.com"},
+		Content:    sp.Content{From: "me@example.com", Subject: "hi", Text: "hi"},
+	}
+	if _, _, err := client.Send(tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(paused) != 1 {
+		t.Errorf("expected x@bad.com to be left out, got %+v", paused)
+	}
+	tx.Recipients = []string{"x@bad.com"}
+	if _, _, err := client.Send(tx); err != sp.ErrDomainsPaused {
+		t.Errorf("expected ErrDomainsPaused, got %v", err)
+	}
+
+	p.Resume("bad.com")
+	if p.Paused("bad.com") {
+		t.Error("expected bad.com to be resumed")
+	}
+}
@@ -0,0 +1,200 @@
+package gosparkpost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// CampaignReport summarizes a campaign sent with SendCampaign.
+type CampaignReport struct {
+	CampaignID string
+	// Outcome is TransmissionSucceeded or TransmissionFailed.
+	Outcome string
+	// Err is the error which stopped the campaign, if any.
+	Err error `json:"-"`
+	// Error is Err as a string, for notifiers which encode the report.
+	Error string `json:",omitempty"`
+
+	// Transmissions holds the status of each batch sent. Unless CampaignOptions.Wait
+	// was set, only the ID of each is filled in.
+	Transmissions []*TransmissionStatus
+	Batches       int
+	Sent          int
+
+	Recipients int
+	Generated  int
+	Failed     int
+
+	Started  time.Time
+	Finished time.Time
+}
+
+func (r *CampaignReport) String() string {
+	s := fmt.Sprintf("Campaign %q %s: %d/%d batches sent, %d/%d recipients generated, %d failed, in %s",
+		r.CampaignID, r.Outcome, r.Sent, r.Batches, r.Generated, r.Recipients, r.Failed,
+		r.Finished.Sub(r.Started).Round(time.Second))
+	if r.Err != nil {
+		s += fmt.Sprintf(" (%s)", r.Err)
+	}
+	return s
+}
+
+// Notifier is told when a campaign sent with SendCampaign finishes.
+type Notifier interface {
+	Notify(ctx context.Context, report *CampaignReport) error
+}
+
+// NotifierFunc allows a function to be used as a Notifier.
+type NotifierFunc func(ctx context.Context, report *CampaignReport) error
+
+func (f NotifierFunc) Notify(ctx context.Context, report *CampaignReport) error {
+	return f(ctx, report)
+}
+
+// CampaignOptions controls SendCampaign.
+type CampaignOptions struct {
+	// Wait, if set, is used to wait for each batch to be generated before sending the next,
+	// so the report includes generation counts.
+	Wait *WaitOptions
+	// FailureThreshold is the fraction (0-1) of recipients which may fail generation
+	// before the remaining batches are abandoned. It requires Wait. Zero means there's no threshold.
+	FailureThreshold float64
+	// Notifiers are each called with the report once the campaign finishes, successfully or not.
+	Notifiers []Notifier
+}
+
+// SendCampaign sends the batches of a campaign in order, optionally waiting for each
+// to be generated, and passes a summary to any configured Notifiers. The error returned
+// is the one which stopped the campaign; errors from Notifiers are joined to it.
+func (c *Client) SendCampaign(ctx context.Context, txs []*Transmission, opts *CampaignOptions) (*CampaignReport, error) {
+	if opts == nil {
+		opts = &CampaignOptions{}
+	}
+	report := &CampaignReport{Batches: len(txs), Started: time.Now(), Outcome: TransmissionSucceeded}
+	if len(txs) > 0 {
+		report.CampaignID = txs[0].CampaignID
+	}
+
+	for _, tx := range txs {
+		id, _, err := c.Send(tx)
+		if err != nil {
+			report.Err = err
+			break
+		}
+		report.Sent++
+
+		status := &TransmissionStatus{ID: id}
+		if opts.Wait != nil {
+			if status, err = c.WaitForTransmission(ctx, id, opts.Wait); err != nil {
+				report.Err = err
+				break
+			}
+			report.Recipients += status.Total
+			report.Generated += status.Generated
+			report.Failed += status.FailedGeneration + status.Invalid
+		}
+		report.Transmissions = append(report.Transmissions, status)
+
+		if status.Outcome == TransmissionFailed || status.Outcome == TransmissionCanceled {
+			report.Outcome = TransmissionFailed
+			break
+		}
+		if opts.FailureThreshold > 0 && report.Recipients > 0 &&
+			float64(report.Failed)/float64(report.Recipients) > opts.FailureThreshold {
+			report.Outcome = TransmissionFailed
+			break
+		}
+	}
+	if report.Err != nil {
+		report.Outcome = TransmissionFailed
+		report.Error = report.Err.Error()
+	}
+	report.Finished = time.Now()
+
+	err := report.Err
+	for _, n := range opts.Notifiers {
+		if nerr := n.Notify(ctx, report); nerr != nil {
+			if err == nil {
+				err = fmt.Errorf("Notifier failed: %s", nerr)
+			} else {
+				err = fmt.Errorf("%s; Notifier failed: %s", err, nerr)
+			}
+		}
+	}
+	return report, err
+}
+
+// WebhookNotifier POSTs the report, encoded as JSON, to URL.
+type WebhookNotifier struct {
+	URL string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, report *CampaignReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return postNotification(ctx, w.Client, w.URL, body)
+}
+
+// SlackNotifier posts a one-line summary of the report to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, report *CampaignReport) error {
+	body, err := json.Marshal(map[string]string{"text": report.String()})
+	if err != nil {
+		return err
+	}
+	return postNotification(ctx, s.Client, s.WebhookURL, body)
+}
+
+func postNotification(ctx context.Context, client *http.Client, url string, body []byte) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	ioutil.ReadAll(res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", url, res.Status)
+	}
+	return nil
+}
+
+// EmailNotifier sends the report by email, using SparkPost.
+type EmailNotifier struct {
+	Client *Client
+	From   string
+	To     []string
+}
+
+func (e *EmailNotifier) Notify(ctx context.Context, report *CampaignReport) error {
+	_, _, err := e.Client.Send(&Transmission{
+		Recipients: e.To,
+		Content: Content{
+			From:    e.From,
+			Subject: fmt.Sprintf("Campaign %q %s", report.CampaignID, report.Outcome),
+			Text:    report.String(),
+		},
+	})
+	return err
+}
@@ -0,0 +1,86 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSendCampaign(t *testing.T) {
+	sent := 0
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			sent++
+			fmt.Fprintf(w, `{"results":{"id":"%d"}}`, sent)
+			return
+		}
+		// the second batch has a lot of failures
+		failed := 0
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			failed = 6
+		}
+		fmt.Fprintf(w, `{"results":{"transmission":{"state":"Success","total_recipients":10,"num_generated":%d,"num_failed_generation":%d}}}`,
+			10-failed, failed)
+	})
+	defer done()
+
+	var hooked sp.CampaignReport
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &hooked); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer hook.Close()
+
+	var notified *sp.CampaignReport
+	txs := make([]*sp.Transmission, 3)
+	for i := range txs {
+		txs[i] = &sp.Transmission{
+			CampaignID: "spring",
+			Recipients: []string{"a@example.com"},
+			Content:    sp.Content{Subject: "sptest", Text: "sptest", From: "test@example.com"},
+		}
+	}
+	report, err := client.SendCampaign(context.Background(), txs, &sp.CampaignOptions{
+		Wait:             &sp.WaitOptions{Interval: time.Millisecond},
+		FailureThreshold: 0.25,
+		Notifiers: []sp.Notifier{
+			sp.NotifierFunc(func(ctx context.Context, r *sp.CampaignReport) error {
+				notified = r
+				return nil
+			}),
+			&sp.WebhookNotifier{URL: hook.URL},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Outcome != sp.TransmissionFailed || report.Sent != 2 || report.Batches != 3 ||
+		report.Recipients != 20 || report.Generated != 14 || report.Failed != 6 {
+		t.Errorf("expected the campaign to stop after the second batch, got %s", report)
+	}
+	if notified != report {
+		t.Error("expected the NotifierFunc to get the report")
+	}
+	if hooked.CampaignID != "spring" || hooked.Sent != 2 || len(hooked.Transmissions) != 2 {
+		t.Errorf("unexpected report sent to webhook: %+v", hooked)
+	}
+
+	failing := sp.NotifierFunc(func(ctx context.Context, r *sp.CampaignReport) error {
+		return fmt.Errorf("boom")
+	})
+	if _, err = client.SendCampaign(context.Background(), txs[:1], &sp.CampaignOptions{Notifiers: []sp.Notifier{failing}}); err == nil {
+		t.Error("expected Notifier errors to be returned")
+	}
+}
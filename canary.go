@@ -0,0 +1,166 @@
+package gosparkpost
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// CanaryCheck decides whether the rest of a campaign may be sent, after the canary
+// Transmission with the provided id was sent to sample. Returning an error holds the rest back.
+type CanaryCheck func(ctx context.Context, id string, sample []Recipient) error
+
+// CanaryOptions controls SendCanary.
+type CanaryOptions struct {
+	// First, if set, sends the canary to the first this many Recipients. Otherwise Percent
+	// (0-100) of them are sent it, spread evenly through the list. At least one is always sent to.
+	First   int
+	Percent float64
+	// Wait is how long to leave the canary before it's checked, so bounces and engagement
+	// have time to be reported.
+	Wait  time.Duration
+	Check CanaryCheck
+	// BatchSize, if set, splits the rest of the Recipients into Transmissions of at most this many.
+	BatchSize int
+}
+
+// CanaryResult is the outcome of SendCanary.
+type CanaryResult struct {
+	// CanaryID is the id of the canary Transmission.
+	CanaryID string
+	Sample   []Recipient
+	// IDs are the Transmissions sent to the rest of the Recipients.
+	IDs []string
+}
+
+// CanaryRejectedError is returned by SendCanary when the check fails, so the rest of the
+// Recipients weren't sent to.
+type CanaryRejectedError struct {
+	CanaryID string
+	// Held is how many Recipients weren't sent to.
+	Held int
+	Err  error
+}
+
+func (e *CanaryRejectedError) Error() string {
+	return fmt.Sprintf("Canary Transmission [%s] failed its check, holding back %d recipients: %s",
+		e.CanaryID, e.Held, e.Err)
+}
+
+// SendCanary sends the prepared Transmission to a sample of recips, waits, and only sends it to
+// the rest if opts.Check approves, so problems with a big campaign show up while it's small.
+// The result lists what was sent, even when an error is returned part way through.
+func (p *PreparedTransmission) SendCanary(ctx context.Context, recips []Recipient, opts *CanaryOptions) (*CanaryResult, error) {
+	if opts == nil || opts.Check == nil {
+		return nil, fmt.Errorf("SendCanary requires a Check")
+	}
+	sample, rest := canarySample(recips, opts)
+	if len(sample) == 0 {
+		return nil, fmt.Errorf("Transmission requires Recipients")
+	}
+
+	result := &CanaryResult{Sample: sample}
+	id, _, err := p.Send(sample)
+	if err != nil {
+		return result, err
+	}
+	result.CanaryID = id
+
+	if opts.Wait > 0 {
+		select {
+		case <-time.After(opts.Wait):
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+	}
+	if err = opts.Check(ctx, id, sample); err != nil {
+		return result, &CanaryRejectedError{CanaryID: id, Held: len(rest), Err: err}
+	}
+
+	size := opts.BatchSize
+	if size <= 0 {
+		size = len(rest)
+	}
+	for len(rest) > 0 {
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+		n := size
+		if n > len(rest) {
+			n = len(rest)
+		}
+		if id, _, err = p.Send(rest[:n]); err != nil {
+			return result, err
+		}
+		result.IDs = append(result.IDs, id)
+		rest = rest[n:]
+	}
+	return result, nil
+}
+
+// canarySample splits recips into the canary sample and the rest.
+func canarySample(recips []Recipient, opts *CanaryOptions) (sample, rest []Recipient) {
+	if len(recips) == 0 {
+		return nil, nil
+	}
+	if opts.First > 0 {
+		n := opts.First
+		if n > len(recips) {
+			n = len(recips)
+		}
+		return recips[:n], recips[n:]
+	}
+
+	n := int(math.Ceil(float64(len(recips)) * opts.Percent / 100))
+	if n < 1 {
+		n = 1
+	} else if n > len(recips) {
+		n = len(recips)
+	}
+	// take every (len/n)th Recipient, so the sample isn't just the start of the list
+	step := float64(len(recips)) / float64(n)
+	next := 0.0
+	for i, r := range recips {
+		if len(sample) < n && float64(i) >= next {
+			sample = append(sample, r)
+			next += step
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	return sample, rest
+}
+
+// CanaryBounceCheck returns a CanaryCheck which fails if more than maxRate (0-1) of the
+// canary's sample bounced, according to the Events API.
+func (c *Client) CanaryBounceCheck(maxRate float64) CanaryCheck {
+	return func(ctx context.Context, id string, sample []Recipient) error {
+		page, err := c.SearchEvents(&EventsParams{
+			Events:        []string{"bounce", "out_of_band"},
+			Transmissions: []string{id},
+			From:          time.Now().Add(-24 * time.Hour),
+		})
+		bounced := map[string]bool{}
+		for err == nil {
+			for _, e := range page.Events {
+				switch b := e.(type) {
+				case *events.Bounce:
+					bounced[b.Recipient] = true
+				case *events.OutOfBand:
+					bounced[b.Recipient] = true
+				}
+			}
+			page, err = page.Next()
+		}
+		if err != ErrEmptyPage {
+			return err
+		}
+		if rate := float64(len(bounced)) / float64(len(sample)); rate > maxRate {
+			return fmt.Errorf("%.1f%% of the sample bounced", rate*100)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSendCanary(t *testing.T) {
+	var sends [][]string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/api/v1/events/message") {
+			if r.URL.Query().Get("transmissions") != "1" {
+				t.Errorf("unexpected events query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"results":[{"type":"bounce","rcpt_to":"r0@example.com"}],"links":{}}`)
+			return
+		}
+		var body struct {
+			Recipients []sp.Recipient `json:"recipients"`
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		var emails []string
+		for _, r := range body.Recipients {
+			addr, _ := sp.ParseAddress(r.Address)
+			emails = append(emails, addr.Email)
+		}
+		sends = append(sends, emails)
+		fmt.Fprintf(w, `{"results":{"id":"%d"}}`, len(sends))
+	})
+	defer done()
+
+	p, err := client.Prepare(&sp.Transmission{
+		Content: sp.Content{From: "test@example.com", Subject: "Sale", Text: "Big sale"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recips []sp.Recipient
+	for i := 0; i < 20; i++ {
+		recips = append(recips, sp.Recipient{Address: fmt.Sprintf("r%d@example.com", i)})
+	}
+	ctx := context.Background()
+
+	// one bounce in a sample of two is too many
+	res, err := p.SendCanary(ctx, recips, &sp.CanaryOptions{Percent: 10, Check: client.CanaryBounceCheck(0.2)})
+	if rerr, ok := err.(*sp.CanaryRejectedError); !ok || rerr.Held != 18 || rerr.CanaryID != "1" {
+		t.Fatalf("expected the canary to be rejected, got %v", err)
+	}
+	if fmt.Sprint(sends) != "[[r0@example.com r10@example.com]]" || len(res.IDs) != 0 {
+		t.Fatalf("unexpected sends %v", sends)
+	}
+
+	sends = nil
+	var checked []sp.Recipient
+	res, err = p.SendCanary(ctx, recips, &sp.CanaryOptions{
+		First:     5,
+		BatchSize: 10,
+		Check: func(ctx context.Context, id string, sample []sp.Recipient) error {
+			checked = sample
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checked) != 5 || len(sends) != 3 || len(sends[0]) != 5 || len(sends[1]) != 10 || len(sends[2]) != 5 {
+		t.Errorf("unexpected sends %v", sends)
+	}
+	if res.CanaryID != "1" || fmt.Sprint(res.IDs) != "[2 3]" {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	if _, err = p.SendCanary(ctx, recips, &sp.CanaryOptions{Percent: 10}); err == nil {
+		t.Error("expected an error without a Check")
+	}
+	fail := errors.New("engagement too low")
+	if _, err = p.SendCanary(ctx, recips, &sp.CanaryOptions{First: 1, Check: func(context.Context, string, []sp.Recipient) error {
+		return fail
+	}}); err == nil || err.(*sp.CanaryRejectedError).Err != fail {
+		t.Errorf("expected the check's error, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Option configures a Client made by NewClient.
+type Option func(*Client) error
+
+// NewClient returns a Client configured by opts, checking the configuration as it goes, so
+// mistakes show up when the Client is made rather than on its first request. It's an
+// alternative to filling in a Config and calling Init, which new settings can be added to
+// without changing the Config struct.
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{Config: &Config{}}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.Config.ApiKey == "" && c.Config.Username == "" {
+		return nil, fmt.Errorf("NewClient requires WithAPIKey or WithBasicAuth")
+	}
+	if err := c.Init(c.Config); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WithAPIKey authenticates requests with key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return fmt.Errorf("WithAPIKey called with an empty key")
+		}
+		c.Config.ApiKey = key
+		return nil
+	}
+}
+
+// WithBasicAuth authenticates requests with a username and password instead of an API key.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) error {
+		if username == "" {
+			return fmt.Errorf("WithBasicAuth called with an empty username")
+		}
+		c.Config.Username, c.Config.Password = username, password
+		return nil
+	}
+}
+
+// WithBaseURL sends requests to an API other than https://api.sparkpost.com.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("Invalid base url [%s]: %s", baseURL, err)
+		} else if u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("API base url must be https!")
+		}
+		c.Config.BaseUrl = baseURL
+		return nil
+	}
+}
+
+// WithRegion sends requests to the API for region, RegionUS or RegionEU.
+func WithRegion(region string) Option {
+	return func(c *Client) error {
+		c.Config.Region = region
+		return c.Config.resolveRegion()
+	}
+}
+
+// WithHTTPClient makes requests using hc, for example to set timeouts or a proxy.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("WithHTTPClient called with a nil http.Client")
+		}
+		c.Client = hc
+		return nil
+	}
+}
+
+// WithRetries retries requests up to n times after network errors and transient responses,
+// using the defaults of RetryPolicy otherwise. Zero turns retries off.
+func WithRetries(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("WithRetries called with a negative count [%d]", n)
+		} else if n == 0 {
+			c.Config.Retry = nil
+			return nil
+		}
+		c.Config.Retry = &RetryPolicy{MaxAttempts: n + 1}
+		return nil
+	}
+}
+
+// WithUserAgent identifies the application making requests, see AppendUserAgent.
+func WithUserAgent(product, version string) Option {
+	return func(c *Client) error {
+		return c.AppendUserAgent(product, version)
+	}
+}
@@ -0,0 +1,69 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestNewClient(t *testing.T) {
+	attempts := 0
+	var got http.Header
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		got = r.Header
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := sp.NewClient(
+		sp.WithAPIKey("testkey"),
+		sp.WithBaseURL(server.URL),
+		sp.WithHTTPClient(server.Client()),
+		sp.WithRetries(1),
+		sp.WithUserAgent("billing", "2.3.1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Config.ApiVersion != 1 || client.Config.Retry.MaxAttempts != 2 {
+		t.Errorf("unexpected config %+v", client.Config)
+	}
+	if _, _, err = client.Templates(); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 || got.Get("Authorization") != "testkey" || got.Get("User-Agent") != "GoSparkPost/"+sp.Version+" billing/2.3.1" {
+		t.Errorf("unexpected request after %d attempts: %v", attempts, got)
+	}
+}
+
+func TestNewClientValidation(t *testing.T) {
+	for _, test := range []struct {
+		what string
+		opts []sp.Option
+	}{
+		{"no credentials", nil},
+		{"empty key", []sp.Option{sp.WithAPIKey("")}},
+		{"http url", []sp.Option{sp.WithAPIKey("k"), sp.WithBaseURL("http://api.sparkpost.com")}},
+		{"bad url", []sp.Option{sp.WithAPIKey("k"), sp.WithBaseURL("https://%zz")}},
+		{"nil http client", []sp.Option{sp.WithAPIKey("k"), sp.WithHTTPClient(nil)}},
+		{"negative retries", []sp.Option{sp.WithAPIKey("k"), sp.WithRetries(-1)}},
+		{"bad user agent", []sp.Option{sp.WithAPIKey("k"), sp.WithUserAgent("my app", "1")}},
+	} {
+		if _, err := sp.NewClient(test.opts...); err == nil {
+			t.Errorf("%s: expected an error", test.what)
+		}
+	}
+
+	client, err := sp.NewClient(sp.WithBasicAuth("user", "pass"), sp.WithRetries(0))
+	if err != nil || client.Config.BaseUrl != "https://api.sparkpost.com" || client.Config.Retry != nil {
+		t.Errorf("unexpected client %+v, %v", client, err)
+	}
+}
@@ -0,0 +1,94 @@
+package gosparkpost
+
+import "reflect"
+
+// Clone returns a deep copy of the Transmission, so it can be modified or sent
+// from another goroutine without affecting the original. Interface fields such as
+// Recipients, Metadata and SubstitutionData are copied all the way down.
+// Functions (such as Content loaders) are shared rather than copied.
+func (t *Transmission) Clone() *Transmission {
+	if t == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(t)).Interface().(*Transmission)
+}
+
+// Clone returns a deep copy of the Template.
+func (t *Template) Clone() *Template {
+	if t == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(t)).Interface().(*Template)
+}
+
+// Clone returns a deep copy of the Content, including Headers, Attachments and InlineImages.
+func (c Content) Clone() Content {
+	return deepCopy(reflect.ValueOf(c)).Interface().(Content)
+}
+
+// Clone returns a deep copy of the Recipient, including Tags, Metadata and SubstitutionData.
+func (r Recipient) Clone() Recipient {
+	return deepCopy(reflect.ValueOf(r)).Interface().(Recipient)
+}
+
+// deepCopy recursively copies maps, slices, pointers and interfaces.
+// Unexported struct fields are copied shallowly. Cyclic values aren't supported.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.New(v.Type().Elem())
+		n.Elem().Set(deepCopy(v.Elem()))
+		return n
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.New(v.Type()).Elem()
+		n.Set(deepCopy(v.Elem()))
+		return n
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			n.SetMapIndex(k, deepCopy(v.MapIndex(k)))
+		}
+		return n
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return n
+
+	case reflect.Array:
+		n := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return n
+
+	case reflect.Struct:
+		n := reflect.New(v.Type()).Elem()
+		n.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if n.Field(i).CanSet() {
+				n.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return n
+	}
+
+	// strings, numbers, funcs and channels
+	return v
+}
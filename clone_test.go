@@ -0,0 +1,78 @@
+package gosparkpost_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTransmissionClone(t *testing.T) {
+	start := sp.RFC3339(time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC))
+	base := &sp.Transmission{
+		CampaignID: "base",
+		Options:    &sp.TxOptions{StartTime: &start},
+		Recipients: []sp.Recipient{{
+			Address:          sp.Address{Email: "a@example.com"},
+			Tags:             []string{"a"},
+			SubstitutionData: map[string]interface{}{"items": []interface{}{"x"}},
+		}},
+		Metadata: map[string]interface{}{"batch": 1},
+		Content: sp.Content{
+			Subject:     "Hi",
+			Headers:     map[string]string{"X-Batch": "1"},
+			Attachments: []sp.Attachment{{MIMEType: "text/plain", Filename: "a.txt", B64Data: "YQ=="}},
+		},
+	}
+
+	clone := base.Clone()
+	if !reflect.DeepEqual(base, clone) {
+		t.Fatalf("clone differs from the original:\n%+v\n%+v", base, clone)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx := base.Clone()
+			recips := tx.Recipients.([]sp.Recipient)
+			recips[0].Tags[0] = "b"
+			recips[0].SubstitutionData.(map[string]interface{})["items"].([]interface{})[0] = i
+			tx.Metadata.(map[string]interface{})["batch"] = i
+			content := tx.Content.(sp.Content)
+			content.Headers["X-Batch"] = "2"
+			content.Attachments[0].Filename = "b.txt"
+			*tx.Options.StartTime = sp.RFC3339(time.Now())
+		}(i)
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(base, clone) {
+		t.Errorf("modifying clones changed the original:\n%+v\n%+v", base, clone)
+	}
+	if (*sp.Transmission)(nil).Clone() != nil {
+		t.Error("expected a nil clone of a nil Transmission")
+	}
+}
+
+func TestTemplateClone(t *testing.T) {
+	tmpl := &sp.Template{ID: "t", Options: &sp.TmplOptions{ClickTracking: true},
+		Content: sp.Content{Headers: map[string]string{"X-A": "1"}}}
+	clone := tmpl.Clone()
+	clone.Options.ClickTracking = false
+	clone.Content.Headers["X-A"] = "2"
+	if !tmpl.Options.ClickTracking || tmpl.Content.Headers["X-A"] != "1" {
+		t.Errorf("modifying the clone changed the original: %+v", tmpl)
+	}
+
+	r := sp.Recipient{Address: &sp.Address{Email: "a@example.com"}, Tags: []string{"a"}}
+	rc := r.Clone()
+	rc.Address.(*sp.Address).Email = "b@example.com"
+	rc.Tags[0] = "b"
+	if r.Address.(*sp.Address).Email != "a@example.com" || r.Tags[0] != "a" {
+		t.Errorf("modifying the clone changed the original: %+v", r)
+	}
+}
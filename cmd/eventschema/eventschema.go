@@ -0,0 +1,47 @@
+// Eventschema writes JSON Schemas for SparkPost events, derived from the structs in
+// the events package, for consumers of stored or forwarded events written in other languages.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+var eventType = flag.String("type", "", "event type to describe (default all, in one schema)")
+var dir = flag.String("dir", "", "write one <type>.schema.json file per event type to this directory")
+
+func main() {
+	flag.Parse()
+
+	if *dir != "" {
+		for _, t := range events.Types() {
+			schema, err := events.Schema(t)
+			if err != nil {
+				log.Fatal(err)
+			}
+			path := filepath.Join(*dir, t+".schema.json")
+			if err = ioutil.WriteFile(path, append(schema, '\n'), 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+		return
+	}
+
+	var schema []byte
+	var err error
+	if *eventType != "" {
+		schema, err = events.Schema(*eventType)
+	} else {
+		schema, err = events.Schemas()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintln(os.Stdout, string(schema))
+}
@@ -0,0 +1,115 @@
+// Package profile reads the named SparkPost accounts the commands in cmd can switch between.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// Profile holds the settings for one SparkPost account, so tools can switch between
+// several by name.
+type Profile struct {
+	ApiKey string `json:"api_key"`
+	// Region and BaseUrl are as in gosparkpost.Config.
+	Region  string `json:"region,omitempty"`
+	BaseUrl string `json:"base_url,omitempty"`
+	// Subaccount, if non-zero, is the Subaccount to act on behalf of, see gosparkpost.Client.ForSubaccount.
+	Subaccount int `json:"subaccount,omitempty"`
+}
+
+// Config returns a Config for the Profile's account. Client.Init sets its BaseUrl from the Region.
+func (p *Profile) Config() (*sp.Config, error) {
+	if p.ApiKey == "" {
+		return nil, fmt.Errorf("Profile has no api_key")
+	}
+	if _, ok := sp.RegionBaseUrls[strings.ToLower(p.Region)]; p.Region != "" && !ok {
+		return nil, fmt.Errorf("Profile has unknown region [%s]", p.Region)
+	}
+	return &sp.Config{ApiKey: p.ApiKey, BaseUrl: p.BaseUrl, Region: p.Region}, nil
+}
+
+// Profiles are named Profiles, as stored in a profiles file:
+//
+//	{
+//	  "default": "prod",
+//	  "profiles": {
+//	    "prod": {"api_key": "...", "region": "eu"},
+//	    "prod-marketing": {"api_key": "...", "region": "eu", "subaccount": 12}
+//	  }
+//	}
+type Profiles struct {
+	// Default names the Profile used when none is requested.
+	Default  string              `json:"default,omitempty"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// DefaultPath is where Load looks when it's passed an empty path: $SPARKPOST_PROFILES
+// if that's set, otherwise .sparkpost/profiles.json in the home directory.
+func DefaultPath() string {
+	if path := os.Getenv("SPARKPOST_PROFILES"); path != "" {
+		return path
+	}
+	home := os.Getenv("HOME")
+	if runtime.GOOS == "windows" {
+		home = os.Getenv("USERPROFILE")
+	}
+	return filepath.Join(home, ".sparkpost", "profiles.json")
+}
+
+// Load reads a profiles file, from DefaultPath if path is empty.
+// Since the file holds API keys, it's refused if other users can read or write it.
+func Load(path string) (*Profiles, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("Profiles file [%s] is accessible by other users (mode %s), run: chmod 600 %s",
+			path, info.Mode().Perm(), path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := &Profiles{}
+	if err = json.Unmarshal(b, profiles); err != nil {
+		return nil, fmt.Errorf("Failed to parse profiles file [%s]: %s", path, err)
+	}
+	return profiles, nil
+}
+
+// Profile returns the named Profile, or the Default one if name is empty.
+func (p *Profiles) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = p.Default
+		if name == "" {
+			return nil, fmt.Errorf("No profile requested, and no default profile set")
+		}
+	}
+	profile, ok := p.Profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("Profile [%s] not found", name)
+	}
+	return profile, nil
+}
+
+// Names returns the names of the Profiles, sorted.
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
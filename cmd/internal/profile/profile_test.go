@@ -0,0 +1,67 @@
+package profile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/SparkPost/gosparkpost/cmd/internal/profile"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "profiles.json")
+	err = ioutil.WriteFile(path, []byte(`{"default":"prod","profiles":{
+		"prod":{"api_key":"k1","region":"eu","subaccount":12},
+		"dev":{"api_key":"k2","base_url":"https://localhost:8443"},
+		"typo":{"api_key":"k3","region":"ap"}}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := profile.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := profiles.Names(); len(names) != 3 || names[0] != "dev" {
+		t.Errorf("unexpected names %q", names)
+	}
+	p, err := profiles.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := p.Config()
+	if err != nil || cfg.ApiKey != "k1" || cfg.Region != "eu" || p.Subaccount != 12 {
+		t.Errorf("unexpected default profile %+v, config %+v, %v", p, cfg, err)
+	}
+	if p, err = profiles.Profile("dev"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = p.Config(); err != nil || cfg.BaseUrl != "https://localhost:8443" {
+		t.Errorf("unexpected config %+v, %v", cfg, err)
+	}
+	if p, _ = profiles.Profile("typo"); p == nil {
+		t.Fatal("expected the typo profile")
+	}
+	if _, err = p.Config(); err == nil {
+		t.Error("expected an error for an unknown region")
+	}
+	if _, err = profiles.Profile("missing"); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+
+	if runtime.GOOS != "windows" {
+		if err = os.Chmod(path, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = profile.Load(path); err == nil {
+			t.Error("expected a world-readable profiles file to be refused")
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// prompter asks questions on the terminal.
+type prompter struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// ask returns the trimmed answer to question, or def if the answer is empty.
+func (p *prompter) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		log.Fatal("aborted, nothing sent")
+	}
+	if answer := strings.TrimSpace(p.in.Text()); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// askUntil asks question until check accepts the answer.
+func (p *prompter) askUntil(question, def string, check func(string) error) string {
+	for {
+		answer := p.ask(question, def)
+		err := check(answer)
+		if err == nil {
+			return answer
+		}
+		fmt.Fprintf(p.out, "  %s\n", err)
+	}
+}
+
+// compose builds a Transmission from answers to prompts, shows exactly what will be sent,
+// and only sends it once that's confirmed, which is harder to get wrong than a line of flags.
+func compose(args []string) {
+	fs := flag.NewFlagSet("sparks compose", flag.ExitOnError)
+	baseUrl := fs.String("url", "", "base url for api requests (optional)")
+	fs.Parse(args)
+
+	apiKey := os.Getenv("SPARKPOST_API_KEY")
+	if strings.TrimSpace(apiKey) == "" {
+		log.Fatal("FATAL: API key not found in environment!\n")
+	}
+	cfg := &sp.Config{ApiKey: apiKey, BaseUrl: *baseUrl}
+	var client sp.Client
+	if err := client.Init(cfg); err != nil {
+		log.Fatalf("SparkPost client init failed: %s\n", err)
+	}
+
+	p := &prompter{in: bufio.NewScanner(os.Stdin), out: os.Stdout}
+	tx := &sp.Transmission{}
+
+	var recipients []*mail.Address
+	p.askUntil("To (comma separated)", "", func(answer string) (err error) {
+		recipients, err = mail.ParseAddressList(answer)
+		return err
+	})
+	list := make([]sp.Recipient, len(recipients))
+	for i, addr := range recipients {
+		list[i] = sp.Recipient{Address: sp.Address{Email: addr.Address, Name: addr.Name}}
+	}
+	tx.Recipients = list
+
+	source := p.askUntil("Content from a [t]emplate or a [f]ile", "t", func(answer string) error {
+		if answer != "t" && answer != "f" {
+			return fmt.Errorf("answer t or f")
+		}
+		return nil
+	})
+	if source == "t" {
+		tx.Content = map[string]string{"template_id": pickTemplate(p, &client)}
+		if path := p.ask("Substitution data file (optional)", ""); path != "" {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			var data map[string]interface{}
+			if err = json.Unmarshal(b, &data); err != nil {
+				log.Fatalf("%s isn't a JSON object: %s", path, err)
+			}
+			tx.SubstitutionData = data
+		}
+	} else {
+		content := sp.Content{}
+		content.From = p.askUntil("From", "", func(answer string) error {
+			_, err := mail.ParseAddress(answer)
+			return err
+		})
+		content.Subject = p.ask("Subject", "")
+		path := p.askUntil("Body file (.html for HTML, anything else for text)", "", func(answer string) error {
+			_, err := os.Stat(answer)
+			return err
+		})
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".html", ".htm":
+			content.HTML = string(body)
+		default:
+			content.Text = string(body)
+		}
+		tx.Content = content
+	}
+
+	preview, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(p.out, "\nThis will be sent to %s:\n%s\n\n", client.Config.BaseUrl, preview)
+	confirm := fmt.Sprintf("Send to %d recipient", len(list))
+	if len(list) != 1 {
+		confirm += "s"
+	}
+	if p.ask(confirm+"? Type yes to send", "") != "yes" {
+		log.Fatal("not confirmed, nothing sent")
+	}
+
+	id, res, err := client.Send(tx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("HTTP [%s] TX %s\n", res.HTTP.Status, id)
+}
+
+// pickTemplate lists the account's templates and returns the id of the one chosen,
+// by number or by id.
+func pickTemplate(p *prompter, client *sp.Client) string {
+	templates, _, err := client.Templates()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(templates) == 0 {
+		log.Fatal("this account has no templates")
+	}
+	for i, t := range templates {
+		fmt.Fprintf(p.out, "%3d) %s  %s\n", i+1, t.ID, t.Name)
+	}
+	var id string
+	p.askUntil("Template (number or id)", "", func(answer string) error {
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(templates) {
+			id = templates[n-1].ID
+			return nil
+		}
+		for _, t := range templates {
+			if t.ID == answer {
+				id = t.ID
+				return nil
+			}
+		}
+		return fmt.Errorf("no template %q", answer)
+	})
+	return id
+}
@@ -56,6 +56,10 @@ var help = flag.Bool("help", false, "display a help message")
 var httpDump = flag.Bool("httpdump", false, "dump out http request and response")
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "compose" {
+		compose(os.Args[2:])
+		return
+	}
 	flag.Parse()
 
 	if *help {
@@ -236,15 +240,11 @@ func main() {
 	tx.Content = content
 
 	if strings.TrimSpace(*sendDelay) != "" {
-		if tx.Options == nil {
-			tx.Options = &sp.TxOptions{}
-		}
 		dur, err := time.ParseDuration(*sendDelay)
 		if err != nil {
 			log.Fatal(err)
 		}
-		start := sp.RFC3339(time.Now().Add(dur))
-		tx.Options.StartTime = &start
+		tx.ScheduleAt(time.Now().Add(dur))
 	}
 
 	if *inline != false {
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeCompletion writes a completion script for shell, which completes commands, flags,
+// output formats and the names of profiles.
+func writeCompletion(w io.Writer, shell string) error {
+	var flags []string
+	flag.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f.Name)
+	})
+
+	switch shell {
+	case "bash", "zsh":
+		if shell == "zsh" {
+			fmt.Fprintln(w, "autoload -U +X bashcompinit && bashcompinit")
+		}
+		fmt.Fprintf(w, `_splist() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+	case "$prev" in
+	-profile|--profile) COMPREPLY=($(compgen -W "$(splist profiles 2>/dev/null)" -- "$cur")); return ;;
+	-output|--output) COMPREPLY=($(compgen -W "table json yaml" -- "$cur")); return ;;
+	completion) COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur")); return ;;
+	esac
+	if [[ "$cur" == -* ]]; then
+		COMPREPLY=($(compgen -W "-%s" -- "$cur"))
+	else
+		COMPREPLY=($(compgen -W "%s" -- "$cur"))
+	fi
+}
+complete -F _splist splist
+`, strings.Join(flags, " -"), strings.Join(commands, " "))
+	case "fish":
+		fmt.Fprintf(w, "complete -c splist -f -n __fish_use_subcommand -a '%s'\n", strings.Join(commands, " "))
+		fmt.Fprintln(w, "complete -c splist -f -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'")
+		for _, name := range flags {
+			switch name {
+			case "profile":
+				fmt.Fprintln(w, "complete -c splist -o profile -x -a '(splist profiles 2>/dev/null)'")
+			case "output":
+				fmt.Fprintln(w, "complete -c splist -o output -x -a 'table json yaml'")
+			default:
+				fmt.Fprintf(w, "complete -c splist -o %s -r\n", name)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown shell %q, expected bash, zsh or fish", shell)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Splist lists templates, subaccounts, webhooks or suppression list entries,
+// as a table, JSON or YAML.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/cmd/internal/profile"
+	"github.com/SparkPost/gosparkpost/render"
+)
+
+var output = render.FormatTable
+var profileName = flag.String("profile", os.Getenv("SPARKPOST_PROFILE"), "profile to use from the profiles file (optional)")
+var url = flag.String("url", "", "base url for api requests (optional)")
+var limit = flag.Int("limit", 0, "maximum number of suppression list entries to list (optional)")
+var domain = flag.String("domain", "", "only list suppression list entries for this domain (optional)")
+
+// commands are what splist can list, followed by its other commands.
+var commands = []string{"templates", "subaccounts", "webhooks", "suppressions", "profiles", "completion"}
+
+func init() {
+	flag.Var(&output, "output", "output format: table, json or yaml")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] templates|subaccounts|webhooks|suppressions\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s profiles\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s completion bash|zsh|fish\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch flag.Arg(0) {
+	case "profiles":
+		profiles, err := profile.Load("")
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range profiles.Names() {
+			fmt.Println(name)
+		}
+		return
+	case "completion":
+		if err := writeCompletion(os.Stdout, flag.Arg(1)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	client, err := newClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var results interface{}
+	switch flag.Arg(0) {
+	case "templates":
+		results, _, err = client.Templates()
+	case "subaccounts":
+		results, _, err = client.Subaccounts()
+	case "webhooks":
+		results, err = client.ListWebhooks(nil)
+	case "suppressions":
+		results, err = client.SuppressionSearch(&sp.SuppressionSearchParams{Domain: *domain, Limit: *limit})
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = render.Write(os.Stdout, output, results); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newClient uses the requested profile, or SPARKPOST_API_KEY if there isn't one,
+// falling back on the default profile.
+func newClient() (*sp.Client, error) {
+	var cfg *sp.Config
+	var name string
+	subaccount := 0
+	apiKey := os.Getenv("SPARKPOST_API_KEY")
+	if *profileName == "" && strings.TrimSpace(apiKey) != "" {
+		cfg = &sp.Config{ApiKey: apiKey}
+	} else {
+		profiles, err := profile.Load("")
+		if os.IsNotExist(err) && *profileName == "" {
+			return nil, fmt.Errorf("FATAL: API key not found in environment, and no profiles file at %s",
+				profile.DefaultPath())
+		} else if err != nil {
+			return nil, err
+		}
+		p, err := profiles.Profile(*profileName)
+		if err != nil {
+			return nil, err
+		}
+		if cfg, err = p.Config(); err != nil {
+			return nil, err
+		}
+		subaccount = p.Subaccount
+		name = *profileName
+		if name == "" {
+			name = profiles.Default
+		}
+	}
+	if *url != "" {
+		cfg.BaseUrl = *url
+	}
+
+	client := &sp.Client{}
+	if err := client.Init(cfg); err != nil {
+		return nil, fmt.Errorf("SparkPost client init failed: %s", err)
+	}
+
+	// say which account is in use, so it's never a surprise
+	if name != "" {
+		fmt.Fprintf(os.Stderr, "using profile %s (%s", name, cfg.BaseUrl)
+		if subaccount != 0 {
+			fmt.Fprintf(os.Stderr, ", subaccount %d", subaccount)
+		}
+		fmt.Fprintln(os.Stderr, ")")
+	}
+	if subaccount != 0 {
+		client = client.ForSubaccount(subaccount)
+	}
+	return client, nil
+}
@@ -0,0 +1,77 @@
+// Tmplvars describes the substitution data a SparkPost template expects,
+// as a JSON Schema or a Go struct, so code and templates can be kept in sync.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+var id = flag.String("id", "", "id of a stored template to describe")
+var htmlFile = flag.String("html", "", "file containing html content to describe")
+var textFile = flag.String("text", "", "file containing text content to describe")
+var subject = flag.String("subject", "", "subject to describe")
+var format = flag.String("format", "schema", "output format: schema or go")
+var name = flag.String("name", "SubstitutionData", "name of the generated Go type")
+var url = flag.String("url", "", "base url for api requests (optional)")
+
+func main() {
+	flag.Parse()
+
+	var content sp.Content
+	if strings.TrimSpace(*id) != "" {
+		apiKey := os.Getenv("SPARKPOST_API_KEY")
+		if strings.TrimSpace(apiKey) == "" {
+			log.Fatal("FATAL: API key not found in environment!\n")
+		}
+		cfg := &sp.Config{ApiKey: apiKey, BaseUrl: *url}
+		var client sp.Client
+		if err := client.Init(cfg); err != nil {
+			log.Fatalf("SparkPost client init failed: %s\n", err)
+		}
+		tmpl, _, err := client.Template(*id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		content = tmpl.Content
+	} else {
+		content.Subject = *subject
+		for _, f := range []struct {
+			path string
+			dest *string
+		}{{*htmlFile, &content.HTML}, {*textFile, &content.Text}} {
+			if f.path == "" {
+				continue
+			}
+			fileBytes, err := ioutil.ReadFile(f.path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			*f.dest = string(fileBytes)
+		}
+	}
+
+	vars, err := sp.TemplateVars(content)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case "schema":
+		schema, err := sp.TemplateVarsSchema(vars)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(schema))
+	case "go":
+		fmt.Print(sp.TemplateVarsStruct(*name, vars))
+	default:
+		log.Fatalf("FATAL: unknown format %q\n", *format)
+	}
+}
@@ -2,7 +2,7 @@ package gosparkpost
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -10,19 +10,59 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
-
-	certifi "github.com/certifi/gocertifi"
+	"sync"
+	"time"
 )
 
 // Config includes all information necessary to make an API request.
 type Config struct {
+	// BaseUrl defaults to the API for Region, which defaults to RegionUS.
 	BaseUrl    string
 	ApiKey     string
 	Username   string
 	Password   string
 	ApiVersion int
 	Verbose    bool
+
+	// Region is where the account is, RegionUS or RegionEU. A BaseUrl for another
+	// region is an error; see VerifyRegion to check the ApiKey too.
+	Region string
+
+	// Sink, if set, redirects every Transmission sent using this Config to the sink domain
+	// provided (normally SinkDomain), so staging and load tests don't deliver real mail.
+	Sink string
+
+	// EscapeSubstitutionData, if set, applies EscapeSubstitution to every string in the substitution
+	// data of Transmissions sent using this Config, so values containing {{ or }} are shown as-is.
+	EscapeSubstitutionData bool
+
+	// Environment, if set, selects the Overlay applied to every Transmission sent using this
+	// Config, from Overlays, or DefaultOverlays if that's nil.
+	Environment string
+	Overlays    map[string]*Overlay
+
+	// TLS, if set, controls connections to the API. It can't be combined with a Client
+	// provided by the caller, which should configure its own transport.
+	TLS *TLSPolicy
+
+	// Retry, if set, retries requests after network errors and transient responses.
+	Retry *RetryPolicy
+
+	// Throttle, if set, makes requests wait, once a Response reports that the API's rate limit
+	// is used up, until it resets, rather than adding to a run of 429s.
+	Throttle bool
+
+	// DefaultHeaders are sent with every request made using this Config.
+	// See DoRequestWithHeaders for how they combine with other headers.
+	DefaultHeaders map[string]string
+
+	// SlowCallThreshold, if non-zero, makes any API call which takes longer than this
+	// to receive a response get passed to OnSlowCall, or logged if that's nil.
+	SlowCallThreshold time.Duration
+	OnSlowCall        func(CallTiming)
 }
 
 // Client contains connection and authentication information.
@@ -31,18 +71,57 @@ type Client struct {
 	Config  *Config
 	Client  *http.Client
 	headers map[string]string
+	agents  []string
+
+	// Dedupe, if set, stops Send from repeating an identical Transmission.
+	Dedupe *DuplicateGuard
+
+	// Latency, if set, records how long each API call takes.
+	Latency *LatencyHistogram
+
+	// Audit, if set, is passed a record of each change made to the suppression list.
+	Audit AuditSink
+
+	// Injection, if set, is applied by Send to each Transmission, see ScanTransmission.
+	Injection *InjectionPolicy
+
+	// Signer, if set, may add headers to each request, see RequestSigner.
+	Signer RequestSigner
+
+	// Limiter, if set, is waited on before each Transmission is sent.
+	Limiter *RateLimiter
+
+	// Archive, if set, has Send add an archive copy of every message, see ArchivePolicy.
+	Archive *ArchivePolicy
+
+	// FrequencyCap, if set, has Send leave out Recipients who've had too many marketing emails.
+	FrequencyCap *FrequencyCap
+
+	// BouncePolicy, if set, has Send leave out Recipients at domains it has paused.
+	BouncePolicy *BouncePolicy
+
+	throttleMu    sync.Mutex
+	throttleUntil time.Time
 }
 
+// Version is the version of this library, as reported in the User-Agent header.
+const Version = "0.1"
+
 var nonDigit *regexp.Regexp = regexp.MustCompile(`\D`)
+var userAgentToken *regexp.Regexp = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
 
 // NewConfig builds a Config object using the provided map.
 func NewConfig(m map[string]string) (*Config, error) {
 	c := &Config{}
 
+	c.Region = m["region"]
 	if baseurl, ok := m["baseurl"]; ok {
 		c.BaseUrl = baseurl
-	} else {
-		return nil, fmt.Errorf("BaseUrl is required for api config")
+	} else if c.Region == "" {
+		return nil, fmt.Errorf("BaseUrl or Region is required for api config")
+	}
+	if err := c.resolveRegion(); err != nil {
+		return nil, err
 	}
 
 	if apikey, ok := m["apikey"]; ok {
@@ -63,6 +142,9 @@ type Response struct {
 	Verbose map[string]string
 	Results map[string]interface{} `json:"results,omitempty"`
 	Errors  []Error                `json:"errors,omitempty"`
+
+	// RateLimit is the rate limit state reported by the API, if any.
+	RateLimit *RateLimit `json:"-"`
 }
 
 // Error mirrors the error format returned by SparkPost APIs.
@@ -86,6 +168,9 @@ func (e Error) Json() (string, error) {
 // Caller may provide their own http.Client by setting it in the provided API object.
 func (api *Client) Init(cfg *Config) error {
 	// Set default values
+	if err := cfg.resolveRegion(); err != nil {
+		return err
+	}
 	if cfg.BaseUrl == "" {
 		cfg.BaseUrl = "https://api.sparkpost.com"
 	} else if !strings.HasPrefix(cfg.BaseUrl, "https://") {
@@ -97,22 +182,21 @@ func (api *Client) Init(cfg *Config) error {
 	api.Config = cfg
 	api.headers = make(map[string]string)
 
-	if api.Client == nil {
-		// Ran into an issue where USERTrust was not recognized on OSX.
-		// The rest of this block was the fix.
+	if _, err := cfg.overlay(); err != nil {
+		return err
+	}
 
-		// load Mozilla cert pool
-		pool, err := certifi.CACerts()
+	if api.Client != nil && cfg.TLS != nil {
+		return fmt.Errorf("Config.TLS can't be applied to a caller provided http.Client")
+	}
+	if api.Client == nil {
+		tlsConfig, err := cfg.TLS.Config("")
 		if err != nil {
 			return err
 		}
 
-		// configure transport using Mozilla cert pool
-		transport := &http.Transport{
-			TLSClientConfig: &tls.Config{RootCAs: pool},
-		}
-
 		// configure http client using transport
+		transport := &http.Transport{TLSClientConfig: tlsConfig}
 		api.Client = &http.Client{Transport: transport}
 	}
 
@@ -120,7 +204,8 @@ func (api *Client) Init(cfg *Config) error {
 }
 
 // SetHeader adds additional HTTP headers for every API request made from client.
-// Usefull to set subaccount X-MSYS-SUBACCOUNT header and etc.
+// Usefull to set subaccount X-MSYS-SUBACCOUNT header and etc, though ForSubaccount
+// and WithSubaccount are simpler for that.
 func (c *Client) SetHeader(header string, value string) {
 	c.headers[header] = value
 }
@@ -130,6 +215,25 @@ func (c *Client) RemoveHeader(header string) {
 	delete(c.headers, header)
 }
 
+// AppendUserAgent adds an application identifier to the User-Agent header sent by this client.
+// SparkPost support may ask for this when diagnosing issues.
+// The header has the format "GoSparkPost/<Version> <product>/<version> ...",
+// for example "GoSparkPost/0.1 billing-service/2.3.1".
+func (c *Client) AppendUserAgent(product, version string) error {
+	if !userAgentToken.MatchString(product) {
+		return fmt.Errorf("Invalid User-Agent product [%s]", product)
+	} else if !userAgentToken.MatchString(version) {
+		return fmt.Errorf("Invalid User-Agent version [%s]", version)
+	}
+	c.agents = append(c.agents, product+"/"+version)
+	return nil
+}
+
+// UserAgent returns the User-Agent header sent by this client.
+func (c *Client) UserAgent() string {
+	return strings.Join(append([]string{"GoSparkPost/" + Version}, c.agents...), " ")
+}
+
 // HttpPost sends a Post request with the provided JSON payload to the specified url.
 // Query params are supported via net/url - roll your own and stringify it.
 // Authenticate using the configured API key.
@@ -159,11 +263,56 @@ func (c *Client) HttpDelete(url string) (*Response, error) {
 }
 
 func (c *Client) DoRequest(method, urlStr string, data []byte) (*Response, error) {
+	return c.DoRequestWithHeaders(method, urlStr, data, nil)
+}
+
+// DoRequestWithHeaders is like DoRequest, and also sends the provided headers with this request only.
+// When the same header is set in more than one place, the most specific value wins:
+// headers passed here override those set with SetHeader, which override Config.DefaultHeaders,
+// which override the library's own defaults (Content-Type, User-Agent).
+// Authorization is always derived from the Config.
+func (c *Client) DoRequestWithHeaders(method, urlStr string, data []byte, headers map[string]string) (*Response, error) {
+	return c.DoRequestContext(context.Background(), method, urlStr, data, headers)
+}
+
+// DoRequestContext is like DoRequestWithHeaders, and also attaches ctx to the request,
+// which may be used for cancellation or (with net/http/httptrace) to observe the connection.
+// Requests are retried according to Config.Retry, if it's set.
+func (c *Client) DoRequestContext(ctx context.Context, method, urlStr string, data []byte, headers map[string]string) (*Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := c.doRequest(ctx, method, urlStr, data, headers)
+		delay, retry := c.Config.Retry.retryDelay(attempt, method, res, err)
+		if !retry || ctx.Err() != nil {
+			return res, err
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return res, err
+		}
+		discardBody(res)
+	}
+}
+
+// doRequest makes a single attempt at a request.
+func (c *Client) doRequest(ctx context.Context, method, urlStr string, data []byte, headers map[string]string) (*Response, error) {
+	if c.Config.Throttle {
+		if err := c.waitRateLimit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := http.NewRequest(method, urlStr, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
 
+	var timer *callTimer
+	if c.Config.SlowCallThreshold > 0 || c.Latency != nil {
+		ctx, timer = newCallTimer(ctx)
+	}
+	req = req.WithContext(ctx)
+
 	ares := &Response{}
 	if c.Config.Verbose {
 		if ares.Verbose == nil {
@@ -180,12 +329,17 @@ func (c *Client) DoRequest(method, urlStr string, data []byte) (*Response, error
 		}
 	}
 
-	// TODO: set User-Agent based on gosparkpost version and possibly git's short hash
-	req.Header.Set("User-Agent", "GoSparkPost v0.1")
+	req.Header.Set("User-Agent", c.UserAgent())
 
-	// Forward additional headers set in client to request
-	for header, value := range c.headers {
-		req.Header.Set(header, value)
+	// Forward additional headers, least specific first
+	var scoped map[string]string
+	if id, ok := ctx.Value(subaccountKey{}).(int); ok {
+		scoped = map[string]string{SubaccountHeader: strconv.Itoa(id)}
+	}
+	for _, hmap := range []map[string]string{c.Config.DefaultHeaders, c.headers, scoped, headers} {
+		for header, value := range hmap {
+			req.Header.Set(header, value)
+		}
 	}
 
 	if c.Config.ApiKey != "" {
@@ -194,6 +348,12 @@ func (c *Client) DoRequest(method, urlStr string, data []byte) (*Response, error
 		req.Header.Add("Authorization", "Basic "+basicAuth(c.Config.Username, c.Config.Password))
 	}
 
+	if c.Signer != nil {
+		if err = c.Signer.SignRequest(req, data); err != nil {
+			return ares, err
+		}
+	}
+
 	if c.Config.Verbose {
 		reqBytes, err := httputil.DumpRequestOut(req, false)
 		if err != nil {
@@ -204,6 +364,30 @@ func (c *Client) DoRequest(method, urlStr string, data []byte) (*Response, error
 
 	res, err := c.Client.Do(req)
 	ares.HTTP = res
+	if res != nil {
+		ares.RateLimit = parseRateLimit(res, time.Now())
+		if c.Config.Throttle {
+			c.observeRateLimit(ares.RateLimit)
+		}
+	}
+
+	if timer != nil {
+		status := 0
+		if res != nil {
+			status = res.StatusCode
+		}
+		timing := timer.finish(method, urlStr, status, err)
+		if c.Latency != nil {
+			c.Latency.Observe(timing.Total)
+		}
+		if c.Config.SlowCallThreshold > 0 && timing.Total > c.Config.SlowCallThreshold {
+			if c.Config.OnSlowCall != nil {
+				c.Config.OnSlowCall(timing)
+			} else {
+				logSlowCall(timing)
+			}
+		}
+	}
 
 	if c.Config.Verbose {
 		ares.Verbose["http_status"] = ares.HTTP.Status
@@ -222,6 +406,37 @@ func basicAuth(username, password string) string {
 	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
+// jsonEqual reports whether a and b have the same JSON encoding.
+// Map keys are sorted when encoding, so this is stable.
+func jsonEqual(a, b interface{}) bool {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aj, bj)
+}
+
+// sameStrings reports whether a and b contain the same strings, ignoring order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as := append([]string(nil), a...)
+	bs := append([]string(nil), b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // ReadBody is a convenience method that returns the http.Response body.
 // The first time this function is called, the body is read from the
 // http.Response. For subsequent calls, the cached version in
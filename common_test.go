@@ -0,0 +1,86 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// newTestClient returns a Client which talks to a local server running the provided handler.
+// Call the returned func to shut the server down.
+func newTestClient(t *testing.T, cfg *sp.Config, handler http.HandlerFunc) (*sp.Client, func()) {
+	server := httptest.NewTLSServer(handler)
+	if cfg == nil {
+		cfg = &sp.Config{ApiKey: "testkey"}
+	}
+	cfg.BaseUrl = server.URL
+
+	client := &sp.Client{Client: server.Client()}
+	if err := client.Init(cfg); err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestHeaderPrecedence(t *testing.T) {
+	var got http.Header
+	cfg := &sp.Config{
+		ApiKey: "testkey",
+		DefaultHeaders: map[string]string{
+			"X-Account": "config",
+			"X-Client":  "config",
+			"X-Call":    "config",
+		},
+	}
+	client, done := newTestClient(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{}}`))
+	})
+	defer done()
+
+	client.SetHeader("X-Client", "client")
+	client.SetHeader("X-Call", "client")
+	_, err := client.DoRequestWithHeaders("GET", cfg.BaseUrl+"/api/v1/account", nil,
+		map[string]string{"X-Call": "call", "Authorization": "nope"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for header, want := range map[string]string{
+		"X-Account":     "config",
+		"X-Client":      "client",
+		"X-Call":        "call",
+		"Authorization": "testkey",
+	} {
+		if got.Get(header) != want {
+			t.Errorf("%s: expected %q, got %q", header, want, got.Get(header))
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var ua string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		ua = r.Header.Get("User-Agent")
+	})
+	defer done()
+
+	if err := client.AppendUserAgent("billing service", "1.0"); err == nil {
+		t.Error("expected an error for a product containing a space")
+	}
+	if err := client.AppendUserAgent("billing-service", "2.3.1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.HttpGet(client.Config.BaseUrl); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "GoSparkPost/" + sp.Version + " billing-service/2.3.1"
+	if ua != want {
+		t.Errorf("expected User-Agent %q, got %q", want, ua)
+	}
+}
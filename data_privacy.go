@@ -0,0 +1,85 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/data-privacy/
+var dataPrivacyPathFormat = "/api/v%d/data-privacy"
+
+// DataPrivacyRequest lists the recipients a data privacy request applies to.
+type DataPrivacyRequest struct {
+	Recipients []string
+	// IncludeSubaccounts, if set, applies the request to all subaccounts as well.
+	IncludeSubaccounts bool
+}
+
+func (r *DataPrivacyRequest) MarshalJSON() ([]byte, error) {
+	type recipient struct {
+		Email string `json:"email"`
+	}
+	recips := make([]recipient, len(r.Recipients))
+	for i, email := range r.Recipients {
+		recips[i].Email = email
+	}
+	return json.Marshal(struct {
+		Recipients         []recipient `json:"recipients"`
+		IncludeSubaccounts bool        `json:"include_subaccounts,omitempty"`
+	}{recips, r.IncludeSubaccounts})
+}
+
+// DataPrivacyForget asks SparkPost to delete the personal data it holds about the recipients,
+// to satisfy a GDPR "right to be forgotten" request. Their addresses are added to the suppression list.
+func (c *Client) DataPrivacyForget(r *DataPrivacyRequest) (*Response, error) {
+	return c.dataPrivacyRequest("rtbf-request", r)
+}
+
+// DataPrivacyOptOut adds the recipients to the suppression list for all mail,
+// transactional and non-transactional, to satisfy a GDPR opt-out request.
+func (c *Client) DataPrivacyOptOut(r *DataPrivacyRequest) (*Response, error) {
+	return c.dataPrivacyRequest("opt-out-request", r)
+}
+
+func (c *Client) dataPrivacyRequest(endpoint string, r *DataPrivacyRequest) (res *Response, err error) {
+	if r == nil || len(r.Recipients) == 0 {
+		err = fmt.Errorf("DataPrivacyRequest requires Recipients")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(dataPrivacyPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, endpoint)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("DataPrivacyRequest", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
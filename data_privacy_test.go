@@ -0,0 +1,40 @@
+package gosparkpost_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestDataPrivacy(t *testing.T) {
+	bodies := map[string]string{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies[r.Method+" "+r.URL.Path] = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"message":"Request accepted"}}`))
+	})
+	defer done()
+
+	if _, err := client.DataPrivacyForget(&sp.DataPrivacyRequest{}); err == nil {
+		t.Error("expected an error without recipients")
+	}
+
+	req := &sp.DataPrivacyRequest{Recipients: []string{"a@example.com", "b@example.com"}, IncludeSubaccounts: true}
+	if _, err := client.DataPrivacyForget(req); err != nil {
+		t.Fatal(err)
+	}
+	if body := bodies["POST /api/v1/data-privacy/rtbf-request"]; body != `{"recipients":[{"email":"a@example.com"},{"email":"b@example.com"}],"include_subaccounts":true}` {
+		t.Errorf("unexpected body %s", body)
+	}
+
+	req.IncludeSubaccounts = false
+	if _, err := client.DataPrivacyOptOut(req); err != nil {
+		t.Fatal(err)
+	}
+	if body := bodies["POST /api/v1/data-privacy/opt-out-request"]; body != `{"recipients":[{"email":"a@example.com"},{"email":"b@example.com"}]}` {
+		t.Errorf("unexpected body %s", body)
+	}
+}
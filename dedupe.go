@@ -0,0 +1,146 @@
+package gosparkpost
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateTransmission is returned by Send when the DuplicateGuard
+// has seen an identical payload within its window.
+var ErrDuplicateTransmission = errors.New("duplicate transmission")
+
+// DedupeStore remembers when a Transmission payload hash was last sent.
+// Implementations backed by shared storage let the guard work across processes.
+type DedupeStore interface {
+	LastSent(hash string) (at time.Time, ok bool, err error)
+	MarkSent(hash string, at time.Time) error
+}
+
+// DuplicateGuard protects against sending the same campaign twice in a row,
+// for example when a "send" button gets double-clicked.
+type DuplicateGuard struct {
+	Store  DedupeStore
+	Window time.Duration
+	// If Warn is set, duplicates are reported to it and sent anyway.
+	Warn func(hash string, last time.Time)
+
+	mu sync.Mutex
+	// hashes reserved by sends which are still in flight
+	pending map[string]time.Time
+}
+
+// ContentHash returns a stable hash of the parts of a Transmission that
+// determine what gets sent to whom: content (or template), recipients,
+// substitution data and campaign id.
+func (t *Transmission) ContentHash() (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("Can't hash a nil Transmission")
+	}
+	// encoding/json sorts map keys, so equivalent payloads hash the same
+	norm := struct {
+		CampaignID       string      `json:"campaign_id"`
+		Recipients       interface{} `json:"recipients"`
+		SubstitutionData interface{} `json:"substitution_data"`
+		Content          interface{} `json:"content"`
+	}{t.CampaignID, t.Recipients, t.SubstitutionData, t.Content}
+
+	jsonBytes, err := json.Marshal(norm)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(jsonBytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Check returns ErrDuplicateTransmission if the Transmission was sent within the window,
+// or is being sent under a reservation made by Reserve.
+// The returned hash should be passed to MarkSent once the send succeeds.
+func (g *DuplicateGuard) Check(t *Transmission) (hash string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.check(t)
+}
+
+func (g *DuplicateGuard) check(t *Transmission) (hash string, err error) {
+	hash, err = t.ContentHash()
+	if err != nil {
+		return
+	}
+
+	last, ok := g.pending[hash]
+	if !ok {
+		if last, ok, err = g.Store.LastSent(hash); err != nil || !ok {
+			return
+		}
+	}
+
+	if time.Since(last) < g.Window {
+		if g.Warn != nil {
+			g.Warn(hash, last)
+			return
+		}
+		err = ErrDuplicateTransmission
+	}
+	return
+}
+
+// MarkSent records that the payload with the provided hash was just sent.
+func (g *DuplicateGuard) MarkSent(hash string) error {
+	return g.Store.MarkSent(hash, time.Now())
+}
+
+// Reserve is Check, which also reserves the hash until it's passed to Release, so a concurrent
+// identical send is rejected while this one is in flight. Reservations are held in process
+// memory, so sends from other processes sharing the Store are only caught once marked sent.
+func (g *DuplicateGuard) Reserve(t *Transmission) (hash string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if hash, err = g.check(t); err != nil {
+		return
+	}
+	if g.pending == nil {
+		g.pending = map[string]time.Time{}
+	}
+	g.pending[hash] = time.Now()
+	return
+}
+
+// Release ends a reservation made by Reserve, first marking the hash sent if sent is true.
+// A failed send is released with sent false, so it can be retried.
+func (g *DuplicateGuard) Release(hash string, sent bool) (err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if sent {
+		err = g.Store.MarkSent(hash, time.Now())
+	}
+	delete(g.pending, hash)
+	return
+}
+
+// MemoryDedupeStore is a DedupeStore that lives in process memory.
+type MemoryDedupeStore struct {
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+func (m *MemoryDedupeStore) LastSent(hash string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	at, ok := m.sent[hash]
+	return at, ok, nil
+}
+
+func (m *MemoryDedupeStore) MarkSent(hash string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sent == nil {
+		m.sent = map[string]time.Time{}
+	}
+	m.sent[hash] = at
+	return nil
+}
@@ -0,0 +1,111 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestDuplicateGuard(t *testing.T) {
+	tx := func() *sp.Transmission {
+		return &sp.Transmission{
+			CampaignID: "dedupe",
+			Recipients: []string{"a@example.com"},
+			Content:    map[string]string{"template_id": "welcome"},
+			SubstitutionData: map[string]interface{}{
+				"b": 2, "a": 1,
+			},
+		}
+	}
+
+	h1, err := tx().ContentHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := tx().ContentHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Fatalf("expected identical hashes, got %s and %s", h1, h2)
+	}
+
+	other := tx()
+	other.Recipients = []string{"b@example.com"}
+	if h3, _ := other.ContentHash(); h3 == h1 {
+		t.Fatal("expected different recipients to change the hash")
+	}
+
+	guard := &sp.DuplicateGuard{Store: &sp.MemoryDedupeStore{}, Window: time.Minute}
+	hash, err := guard.Check(tx())
+	if err != nil {
+		t.Fatalf("first send: unexpected error %v", err)
+	}
+	if err = guard.MarkSent(hash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = guard.Check(tx()); err != sp.ErrDuplicateTransmission {
+		t.Fatalf("expected ErrDuplicateTransmission, got %v", err)
+	}
+
+	warned := false
+	guard.Warn = func(string, time.Time) { warned = true }
+	if _, err = guard.Check(tx()); err != nil || !warned {
+		t.Fatalf("expected a warning and no error, got warned=%t err=%v", warned, err)
+	}
+}
+
+func TestDuplicateGuardInFlight(t *testing.T) {
+	inFlight, proceed := make(chan struct{}), make(chan struct{})
+	status, body := http.StatusBadRequest, `{"errors":[{"message":"invalid"}]}`
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		inFlight <- struct{}{}
+		<-proceed
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	defer done()
+	client.Dedupe = &sp.DuplicateGuard{Store: &sp.MemoryDedupeStore{}, Window: time.Minute}
+
+	tx := func() *sp.Transmission {
+		return &sp.Transmission{
+			CampaignID: "dedupe",
+			Recipients: []string{"a@example.com"},
+			Content:    map[string]string{"template_id": "welcome"},
+		}
+	}
+	send := func() chan error {
+		errc := make(chan error, 1)
+		go func() {
+			_, _, err := client.Send(tx())
+			errc <- err
+		}()
+		return errc
+	}
+
+	// an identical send made while the first is in flight is rejected
+	first := send()
+	<-inFlight
+	if _, _, err := client.Send(tx()); err != sp.ErrDuplicateTransmission {
+		t.Fatalf("expected ErrDuplicateTransmission while in flight, got %v", err)
+	}
+	proceed <- struct{}{}
+	if err := <-first; err == nil {
+		t.Fatal("expected the first send to fail")
+	}
+
+	// which released the reservation, so it can be retried
+	status, body = http.StatusOK, `{"results":{"id":"1"}}`
+	retry := send()
+	<-inFlight
+	proceed <- struct{}{}
+	if err := <-retry; err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if _, _, err := client.Send(tx()); err != sp.ErrDuplicateTransmission {
+		t.Fatalf("expected ErrDuplicateTransmission after sending, got %v", err)
+	}
+}
@@ -3,8 +3,6 @@ package gosparkpost
 import (
 	"encoding/json"
 	"fmt"
-
-	URL "net/url"
 )
 
 // https://www.sparkpost.com/api#/reference/message-events
@@ -42,6 +40,7 @@ type DeliverabilityMetricItem struct {
 	CountAccepted               int    `json:"count_accepted,omitempty"`
 	CountSpamComplaint          int    `json:"count_spam_complaint,omitempty"`
 	Domain                      string `json:"domain,omitempty"`
+	SendingDomain               string `json:"sending_domain,omitempty"`
 	CampaignId                  string `json:"campaign_id,omitempty"`
 	TemplateId                  string `json:"template_id,omitempty"`
 	TimeStamp                   string `json:"ts,omitempty"`
@@ -61,25 +60,13 @@ type DeliverabilityMetricEventsWrapper struct {
 // https://developers.sparkpost.com/api/#/reference/metrics/deliverability-metrics-by-domain
 func (c *Client) QueryDeliverabilityMetrics(extraPath string, parameters map[string]string) (*DeliverabilityMetricEventsWrapper, error) {
 
-	var finalUrl string
 	path := fmt.Sprintf(deliverabilityMetricPathFormat, c.Config.ApiVersion)
 
 	if extraPath != "" {
 		path = fmt.Sprintf("%s/%s", path, extraPath)
 	}
 
-	//log.Printf("Path: %s", path)
-
-	if parameters == nil || len(parameters) == 0 {
-		finalUrl = fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
-	} else {
-		params := URL.Values{}
-		for k, v := range parameters {
-			params.Add(k, v)
-		}
-
-		finalUrl = fmt.Sprintf("%s%s?%s", c.Config.BaseUrl, path, params.Encode())
-	}
+	finalUrl := QueryBuilder{}.Params(parameters).URL(c.Config.BaseUrl + path)
 
 	return doMetricsRequest(c, finalUrl)
 }
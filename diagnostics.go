@@ -0,0 +1,113 @@
+package gosparkpost
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// Diagnostics describes how a Client's connections to the API behave.
+type Diagnostics struct {
+	URL      string
+	Requests int
+	// Protocol is the protocol of the last response, for example "HTTP/1.1" or "HTTP/2.0".
+	Protocol string
+
+	// NewConns counts requests which needed a new connection, and ReusedConns
+	// those which reused an existing one. Keep-alive is working if all but the first are reused.
+	NewConns    int
+	ReusedConns int
+
+	// Latencies holds the duration of each request, in order.
+	Latencies []time.Duration
+
+	// TLS details of the last new connection.
+	TLSVersion         uint16
+	CipherSuite        uint16
+	NegotiatedProtocol string
+	ServerName         string
+	// CertSubject, CertIssuer and CertExpiry describe the server's leaf certificate.
+	CertSubject string
+	CertIssuer  string
+	CertExpiry  time.Time
+}
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	0x0304:           "TLS 1.3",
+}
+
+func (d *Diagnostics) String() string {
+	version, ok := tlsVersions[d.TLSVersion]
+	if !ok {
+		version = fmt.Sprintf("0x%04x", d.TLSVersion)
+	}
+	lat := make([]string, len(d.Latencies))
+	for i, l := range d.Latencies {
+		lat[i] = l.String()
+	}
+	return fmt.Sprintf("URL:\t%s\nProto:\t%s\nConns:\t%d new, %d reused of %d requests\nTLS:\t%s, cipher 0x%04x, ALPN %q, SNI %q\nCert:\t%s (issued by %s, expires %s)\nTimes:\t%s\n",
+		d.URL, d.Protocol, d.NewConns, d.ReusedConns, d.Requests,
+		version, d.CipherSuite, d.NegotiatedProtocol, d.ServerName,
+		d.CertSubject, d.CertIssuer, d.CertExpiry.Format(time.RFC3339),
+		strings.Join(lat, " "))
+}
+
+// Diagnose makes the requested number of sequential calls to the Account API,
+// reporting the protocol negotiated, how often connections were reused, and TLS details.
+// The status of each response is ignored, so an API key without access to the
+// Account API can still be used.
+func (c *Client) Diagnose(ctx context.Context, requests int) (*Diagnostics, error) {
+	if requests <= 0 {
+		requests = 1
+	}
+	path := fmt.Sprintf(accountPathFormat, c.Config.ApiVersion)
+	d := &Diagnostics{URL: fmt.Sprintf("%s%s", c.Config.BaseUrl, path)}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				d.ReusedConns++
+			} else {
+				d.NewConns++
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err != nil {
+				return
+			}
+			d.TLSVersion = state.Version
+			d.CipherSuite = state.CipherSuite
+			d.NegotiatedProtocol = state.NegotiatedProtocol
+			d.ServerName = state.ServerName
+			if len(state.PeerCertificates) > 0 {
+				cert := state.PeerCertificates[0]
+				d.CertSubject = cert.Subject.CommonName
+				d.CertIssuer = cert.Issuer.CommonName
+				d.CertExpiry = cert.NotAfter
+			}
+		},
+	}
+	ctx = httptrace.WithClientTrace(ctx, trace)
+
+	for i := 0; i < requests; i++ {
+		start := time.Now()
+		res, err := c.DoRequestContext(ctx, "GET", d.URL, nil, nil)
+		if err != nil {
+			return d, err
+		}
+		// the body has to be read for the connection to be reused
+		if _, err = res.ReadBody(); err != nil {
+			return d, err
+		}
+		d.Latencies = append(d.Latencies, time.Since(start))
+		d.Protocol = res.HTTP.Proto
+		d.Requests++
+	}
+	return d, nil
+}
@@ -0,0 +1,30 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{}}`))
+	})
+	defer done()
+
+	d, err := client.Diagnose(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Requests != 3 || d.NewConns != 1 || d.ReusedConns != 2 || len(d.Latencies) != 3 {
+		t.Errorf("expected one connection to be reused for 3 requests, got %+v", d)
+	}
+	if d.Protocol != "HTTP/1.1" || d.TLSVersion == 0 || d.CertExpiry.IsZero() {
+		t.Errorf("expected protocol and TLS details, got %+v", d)
+	}
+	if !strings.Contains(d.String(), "1 new, 2 reused of 3 requests") {
+		t.Errorf("unexpected summary:\n%s", d)
+	}
+}
@@ -0,0 +1,120 @@
+package gosparkpost
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DomainReport brings together what's known about the health of one sending domain.
+type DomainReport struct {
+	Domain string
+	Status *SendingDomainStatus
+	// Verified is set if the domain's ownership and DKIM are verified and it isn't
+	// blocked for compliance, so it can be sent from.
+	Verified bool
+
+	// Sent, BounceRate (of messages sent) and ComplaintRate (of messages delivered) cover
+	// the report's window.
+	Sent          int
+	BounceRate    float64
+	ComplaintRate float64
+
+	// HealthScore is the latest Signals health score (0-1), if HasHealthScore is set.
+	HealthScore    float64
+	HasHealthScore bool
+
+	// Incidents are the domain's active blocklist incidents.
+	Incidents []BlocklistIncident
+}
+
+// DomainReports builds a DomainReport for each sending domain, with rates covering the time
+// since from. Like Snapshot, the requests are made concurrently, the first error is returned,
+// and ctx being done returns ctx.Err() without waiting for them.
+func (c *Client) DomainReports(ctx context.Context, from time.Time) ([]DomainReport, error) {
+	var domains []SendingDomain
+	var metrics []*DeliverabilityMetricItem
+	var scores []HealthScoreResult
+	var incidents []BlocklistIncident
+	fetches := []func() error{
+		func() (err error) {
+			domains, _, err = c.SendingDomains()
+			return
+		},
+		func() (err error) {
+			metrics, _, err = c.DeliverabilityMetricsBy(MetricsBySendingDomain, &MetricsParams{
+				From:    from,
+				Metrics: []string{"count_sent", "count_bounce", "count_delivered", "count_spam_complaint"},
+			})
+			return
+		},
+		func() (err error) {
+			scores, _, err = c.HealthScores(SignalsFacetSendingDomain, &SignalsParams{From: from})
+			return
+		},
+		func() (err error) {
+			incidents, _, err = c.BlocklistIncidents(&BlocklistParams{Status: BlocklistIncidentActive})
+			return
+		},
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, fetch := range fetches {
+		wg.Add(1)
+		go func(fetch func() error) {
+			defer wg.Done()
+			if err := fetch(); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(fetch)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	reports := make([]DomainReport, len(domains))
+	index := map[string]*DomainReport{}
+	for i, d := range domains {
+		r := &reports[i]
+		r.Domain = d.Domain
+		r.Status = d.Status
+		r.Verified = d.Status != nil && d.Status.OwnershipVerified &&
+			d.Status.DKIMStatus == "valid" && d.Status.ComplianceStatus == "valid"
+		index[strings.ToLower(d.Domain)] = r
+	}
+	for _, m := range metrics {
+		if r := index[strings.ToLower(m.SendingDomain)]; r != nil {
+			r.Sent = m.CountSent
+			r.BounceRate = rate(m.CountBounce, m.CountSent)
+			r.ComplaintRate = rate(m.CountSpamComplaint, m.CountDelivered)
+		}
+	}
+	for i := range scores {
+		if r := index[strings.ToLower(scores[i].Facet)]; r != nil {
+			if latest, ok := scores[i].Latest(); ok {
+				r.HealthScore, r.HasHealthScore = latest.Score, true
+			}
+		}
+	}
+	for _, inc := range incidents {
+		if r := index[strings.ToLower(inc.Resource)]; r != nil {
+			r.Incidents = append(r.Incidents, inc)
+		}
+	}
+	return reports, nil
+}
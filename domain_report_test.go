@@ -0,0 +1,59 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestDomainReports(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/sending-domains":
+			fmt.Fprint(w, `{"results":[
+				{"domain":"good.example.com","status":{"ownership_verified":true,"dkim_status":"valid","compliance_status":"valid"}},
+				{"domain":"bad.example.com","status":{"ownership_verified":true,"dkim_status":"unverified","compliance_status":"valid"}}]}`)
+		case "/api/v1/metrics/deliverability/sending-domain":
+			if r.URL.Query().Get("from") != "2026-03-01T00:00" {
+				t.Errorf("unexpected metrics query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"results":[{"sending_domain":"Good.example.com","count_sent":200,"count_bounce":10,
+				"count_delivered":190,"count_spam_complaint":1}]}`)
+		case "/api/v1/signals/health-score/sending-domain":
+			fmt.Fprint(w, `{"results":[{"sending_domain":"good.example.com","history":[
+				{"dt":"2026-03-01","health_score":0.7},{"dt":"2026-03-02","health_score":0.9}]}]}`)
+		case "/api/v1/blocklist-monitors/incidents":
+			if r.URL.Query().Get("status") != "active" {
+				t.Errorf("unexpected incidents query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"results":[{"id":"1","resource":"bad.example.com","blocklist_name":"spamhaus","status":"active"},
+				{"id":"2","resource":"203.0.113.9","blocklist_name":"spamcop","status":"active"}]}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer done()
+
+	reports, err := client.DomainReports(context.Background(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var good, bad sp.DomainReport
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	good, bad = reports[0], reports[1]
+	if !good.Verified || good.Sent != 200 || good.BounceRate != 0.05 || good.ComplaintRate != 1.0/190 ||
+		!good.HasHealthScore || good.HealthScore != 0.9 || len(good.Incidents) != 0 {
+		t.Errorf("unexpected report %+v", good)
+	}
+	if bad.Verified || bad.Sent != 0 || bad.HasHealthScore || len(bad.Incidents) != 1 || bad.Incidents[0].BlocklistName != "spamhaus" {
+		t.Errorf("unexpected report %+v", bad)
+	}
+}
@@ -0,0 +1,113 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestEqualAfterRoundTrip(t *testing.T) {
+	sub := &sp.Subaccount{ID: 12, Name: "sub", Grants: []string{"smtp/inject", "transmissions/modify"}, Status: "active"}
+	var sub2 sp.Subaccount
+	roundTrip(t, sub, &sub2)
+	if !sub.Equal(&sub2) {
+		t.Errorf("Subaccount %s changed after a round trip", sub)
+	}
+	sub2.Grants = []string{"transmissions/modify", "smtp/inject"}
+	if !sub.Equal(&sub2) {
+		t.Error("expected Grants order to be ignored")
+	}
+	sub2.Status = "suspended"
+	if sub.Equal(&sub2) {
+		t.Error("expected Subaccounts with different statuses to differ")
+	}
+
+	entry := &sp.SuppressionEntry{Email: "Bob@example.com", Transactional: true, Description: "bounced"}
+	var entry2 sp.SuppressionEntry
+	roundTrip(t, entry, &entry2)
+	entry2.Recipient, entry2.Email = "bob@example.com", ""
+	entry2.Updated = "2018-07-01T00:00:00+00:00"
+	if !entry.Equal(&entry2) {
+		t.Errorf("SuppressionEntry %s != %s", entry, &entry2)
+	}
+	entry2.NonTransactional = true
+	if entry.Equal(&entry2) {
+		t.Error("expected entries with different types to differ")
+	}
+
+	tmpl := &sp.Template{ID: "welcome", Name: "Welcome", Published: true,
+		Content:    sp.Content{Subject: "Hi", HTML: "<b>Hi</b>", Headers: map[string]string{"X-A": "1", "X-B": "2"}},
+		Options:    &sp.TmplOptions{ClickTracking: true},
+		LastUpdate: time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)}
+	var tmpl2 sp.Template
+	roundTrip(t, tmpl, &tmpl2)
+	tmpl2.LastUse = time.Now()
+	if !tmpl.Equal(&tmpl2) {
+		t.Errorf("Template %s changed after a round trip", tmpl)
+	}
+	tmpl2.Content.Subject = "Hello"
+	if tmpl.Equal(&tmpl2) {
+		t.Error("expected Templates with different content to differ")
+	}
+
+	webhook := &sp.WebhookItem{ID: "1", Name: "hook", Target: "https://example.com", Events: []string{"open", "click"}}
+	webhook.AuthCredentials.Username = "user"
+	var webhook2 sp.WebhookItem
+	roundTrip(t, webhook, &webhook2)
+	webhook2.Events = []string{"click", "open"}
+	webhook2.LastFailure = "2018-07-01T00:00:00+00:00"
+	if !webhook.Equal(&webhook2) {
+		t.Errorf("Webhook %s changed after a round trip", webhook)
+	}
+	webhook2.AuthCredentials.Username = "other"
+	if webhook.Equal(&webhook2) {
+		t.Error("expected webhooks with different credentials to differ")
+	}
+
+	if (*sp.Template)(nil).Equal(tmpl) || !(*sp.Template)(nil).Equal(nil) {
+		t.Error("unexpected result comparing nil Templates")
+	}
+}
+
+func roundTrip(t *testing.T, in, out interface{}) {
+	t.Helper()
+	jsonBytes, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(jsonBytes, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStringNil(t *testing.T) {
+	for _, s := range []fmt.Stringer{
+		(*sp.Subaccount)(nil),
+		(*sp.SuppressionEntry)(nil),
+		(*sp.Template)(nil),
+		(*sp.WebhookItem)(nil),
+	} {
+		if str := s.String(); str != "<nil>" {
+			t.Errorf("%T: expected <nil>, got %q", s, str)
+		}
+	}
+}
+
+func TestTemplateEqualLoaders(t *testing.T) {
+	calls := 0
+	loader := func() (string, error) {
+		calls++
+		return "<p>hi</p>", nil
+	}
+	a := &sp.Template{ID: "t", Content: sp.Content{Subject: "hi", HTMLLoader: loader}}
+	b := &sp.Template{ID: "t", Content: sp.Content{Subject: "hi"}}
+	if !a.Equal(b) {
+		t.Error("expected templates with the same loaded content to be equal")
+	}
+	if calls != 0 {
+		t.Errorf("expected Equal not to call loaders, got %d calls", calls)
+	}
+}
@@ -0,0 +1,88 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// The template language has macros for literal braces, which are never treated as tags.
+// https://developers.sparkpost.com/api/template-language/
+var substitutionEscaper = strings.NewReplacer(
+	"{{", "{{opening_double_curly()}}",
+	"}}", "{{closing_double_curly()}}",
+)
+
+// EscapeSubstitution returns s with each {{ and }} replaced by the template language's macros
+// for literal braces, so content from untrusted users can't add tags of its own.
+func EscapeSubstitution(s string) string {
+	if !strings.Contains(s, "{{") && !strings.Contains(s, "}}") {
+		return s
+	}
+	return substitutionEscaper.Replace(s)
+}
+
+// EscapeSubstitutionData returns a copy of substitution data (or metadata) with EscapeSubstitution
+// applied to every string value, however deeply nested. Keys are left alone.
+func EscapeSubstitutionData(data interface{}) (interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err = json.Unmarshal(jsonBytes, &v); err != nil {
+		return nil, err
+	}
+	return escapeValue(v), nil
+}
+
+func escapeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return EscapeSubstitution(val)
+	case map[string]interface{}:
+		for k, elem := range val {
+			val[k] = escapeValue(elem)
+		}
+	case []interface{}:
+		for i, elem := range val {
+			val[i] = escapeValue(elem)
+		}
+	}
+	return v
+}
+
+// escapeRecipients returns a copy of recips with their substitution data escaped.
+func escapeRecipients(recips []Recipient) ([]Recipient, error) {
+	escaped := make([]Recipient, len(recips))
+	for i, r := range recips {
+		data, err := EscapeSubstitutionData(r.SubstitutionData)
+		if err != nil {
+			return nil, err
+		}
+		r.SubstitutionData = data
+		escaped[i] = r
+	}
+	return escaped, nil
+}
+
+// escapeTransmission returns a copy of t with the substitution data of the Transmission,
+// and of any inline Recipients, escaped.
+func escapeTransmission(t *Transmission) (*Transmission, error) {
+	tx := *t
+	var err error
+	if tx.SubstitutionData, err = EscapeSubstitutionData(t.SubstitutionData); err != nil {
+		return nil, err
+	}
+	recips, err := inlineRecipients(t.Recipients)
+	if err != nil {
+		return nil, err
+	} else if recips != nil {
+		if tx.Recipients, err = escapeRecipients(recips); err != nil {
+			return nil, err
+		}
+	}
+	return &tx, nil
+}
@@ -0,0 +1,79 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestEscapeSubstitution(t *testing.T) {
+	for in, out := range map[string]string{
+		"plain":           "plain",
+		"{single}":        "{single}",
+		"{{ name }}":      "{{opening_double_curly()}} name {{closing_double_curly()}}",
+		"{{{ html }}}":    "{{opening_double_curly()}}{ html {{closing_double_curly()}}}",
+		"a }} b {{":       "a {{closing_double_curly()}} b {{opening_double_curly()}}",
+		"{{each items}}x": "{{opening_double_curly()}}each items{{closing_double_curly()}}x",
+	} {
+		if got := sp.EscapeSubstitution(in); got != out {
+			t.Errorf("%q: expected %q, got %q", in, out, got)
+		}
+	}
+}
+
+func TestEscapeSubstitutionData(t *testing.T) {
+	var body map[string]interface{}
+	cfg := &sp.Config{ApiKey: "testkey", EscapeSubstitutionData: true}
+	client, done := newTestClient(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+
+	data := map[string]interface{}{"comment": "{{ evil }}", "tags": []string{"ok", "}}"}}
+	tx := &sp.Transmission{
+		Recipients: []sp.Recipient{{
+			Address:          "a@example.com",
+			SubstitutionData: struct{ Name string }{"{{x}}"},
+		}},
+		Content:          sp.Content{From: "test@example.com", Subject: "Hi", Text: "{{ comment }}"},
+		SubstitutionData: data,
+	}
+	if _, _, err := client.Send(tx); err != nil {
+		t.Fatal(err)
+	}
+	sub := body["substitution_data"].(map[string]interface{})
+	if sub["comment"] != "{{opening_double_curly()}} evil {{closing_double_curly()}}" ||
+		sub["tags"].([]interface{})[1] != "{{closing_double_curly()}}" {
+		t.Errorf("unexpected substitution data %v", sub)
+	}
+	recip := body["recipients"].([]interface{})[0].(map[string]interface{})
+	if recip["substitution_data"].(map[string]interface{})["Name"] != "{{opening_double_curly()}}x{{closing_double_curly()}}" {
+		t.Errorf("unexpected recipient %v", recip)
+	}
+	if data["comment"] != "{{ evil }}" {
+		t.Error("the caller's substitution data was modified")
+	}
+	if content := body["content"].(map[string]interface{}); content["text"] != "{{ comment }}" {
+		t.Errorf("content shouldn't be escaped, got %v", content)
+	}
+
+	prepared, err := client.Prepare(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = prepared.Send([]sp.Recipient{{Address: "b@example.com", SubstitutionData: map[string]string{"n": "}}"}}}); err != nil {
+		t.Fatal(err)
+	}
+	sub = body["substitution_data"].(map[string]interface{})
+	recip = body["recipients"].([]interface{})[0].(map[string]interface{})
+	if sub["comment"] != "{{opening_double_curly()}} evil {{closing_double_curly()}}" ||
+		recip["substitution_data"].(map[string]interface{})["n"] != "{{closing_double_curly()}}" {
+		t.Errorf("unexpected prepared body %v", body)
+	}
+}
@@ -0,0 +1,127 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// https://developers.sparkpost.com/api/events/#events-get-search-for-message-events
+// This replaces the deprecated Message Events API (see MessageEvents).
+var eventsMessagePathFormat = "/api/v%d/events/message"
+
+// EventsParams filters the results of SearchEvents. Empty fields are ignored.
+type EventsParams struct {
+	// Events are the event types to return, for example "delivery" or "bounce".
+	Events []string
+	// From and To limit the time window searched. They default to the last 24 hours.
+	From time.Time
+	To   time.Time
+
+	Recipients    []string
+	Campaigns     []string
+	Templates     []string
+	Transmissions []string
+	Messages      []string
+	Subaccounts   []int
+	BounceClasses []int
+
+	// PerPage defaults to 1000, and may be at most MaxEventsPerPage.
+	PerPage int
+}
+
+func (p *EventsParams) query() QueryBuilder {
+	q := QueryBuilder{}
+	if p == nil {
+		return q
+	}
+	return q.List("events", p.Events...).
+		Time("from", p.From, time.RFC3339).
+		Time("to", p.To, time.RFC3339).
+		List("recipients", p.Recipients...).
+		List("campaigns", p.Campaigns...).
+		List("templates", p.Templates...).
+		List("transmissions", p.Transmissions...).
+		List("messages", p.Messages...).
+		Ints("subaccounts", p.Subaccounts...).
+		Ints("bounce_classes", p.BounceClasses...).
+		Int("per_page", p.PerPage)
+}
+
+// EventsCursorPage is a page of results from SearchEvents.
+type EventsCursorPage struct {
+	client *Client
+
+	Events     events.Events
+	TotalCount int
+	nextPage   string
+}
+
+func (ep *EventsCursorPage) UnmarshalJSON(data []byte) error {
+	var wrapper struct {
+		RawEvents  []json.RawMessage `json:"results"`
+		TotalCount int               `json:"total_count"`
+		Links      struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+	err := json.Unmarshal(data, &wrapper)
+	if err != nil {
+		return err
+	}
+
+	*ep = EventsCursorPage{TotalCount: wrapper.TotalCount, nextPage: wrapper.Links.Next}
+	ep.Events, err = events.ParseRawJSONEvents(wrapper.RawEvents)
+	return err
+}
+
+// SearchEvents returns the first page of message events matching params.
+// Use Next to page through the rest.
+func (c *Client) SearchEvents(params *EventsParams) (*EventsCursorPage, error) {
+	path := fmt.Sprintf(eventsMessagePathFormat, c.Config.ApiVersion)
+	return c.eventsCursorPage(params.query().URL(c.Config.BaseUrl + path))
+}
+
+// Next returns the following page of results, or ErrEmptyPage if this is the last page.
+func (ep *EventsCursorPage) Next() (*EventsCursorPage, error) {
+	if ep.nextPage == "" {
+		return nil, ErrEmptyPage
+	}
+	return ep.client.eventsCursorPage(ep.client.Config.BaseUrl + ep.nextPage)
+}
+
+func (c *Client) eventsCursorPage(url string) (*EventsCursorPage, error) {
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, err
+	}
+
+	if res.HTTP.StatusCode != 200 {
+		if err = res.ParseResponse(); err != nil {
+			return nil, err
+		}
+		if err = res.PrettyError("Events", "search"); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	bodyBytes, err := res.ReadBody()
+	if err != nil {
+		return nil, err
+	}
+
+	var page EventsCursorPage
+	if err = json.Unmarshal(bodyBytes, &page); err != nil {
+		return nil, err
+	}
+	page.client = c
+
+	return &page, nil
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -30,97 +32,96 @@ func ValidEventType(eventType string) bool {
 	return true
 }
 
+// eventTypes maps each event type to a function returning a new struct for it.
+var eventTypes = map[string]func() Event{
+	"bounce":               func() Event { return &Bounce{} },
+	"click":                func() Event { return &Click{} },
+	"creation":             func() Event { return &Creation{} },
+	"delay":                func() Event { return &Delay{} },
+	"delivery":             func() Event { return &Delivery{} },
+	"generation_failure":   func() Event { return &GenerationFailure{} },
+	"generation_rejection": func() Event { return &GenerationRejection{} },
+	"injection":            func() Event { return &Injection{} },
+	"list_unsubscribe":     func() Event { return &ListUnsubscribe{} },
+	"link_unsubscribe":     func() Event { return &LinkUnsubscribe{} },
+	"open":                 func() Event { return &Open{} },
+	"initial_open":         func() Event { return &InitialOpen{} },
+	"amp_open":             func() Event { return &AMPOpen{} },
+	"amp_initial_open":     func() Event { return &AMPInitialOpen{} },
+	"amp_click":            func() Event { return &AMPClick{} },
+	"out_of_band":          func() Event { return &OutOfBand{} },
+	"policy_rejection":     func() Event { return &PolicyRejection{} },
+	"spam_complaint":       func() Event { return &SpamComplaint{} },
+	"relay_delivery":       func() Event { return &RelayDelivery{} },
+	"relay_injection":      func() Event { return &RelayInjection{} },
+	"relay_message":        func() Event { return &RelayMessage{} },
+	"relay_permfail":       func() Event { return &RelayPermfail{} },
+	"relay_rejection":      func() Event { return &RelayRejection{} },
+	"relay_tempfail":       func() Event { return &RelayTempfail{} },
+	"sms_status":           func() Event { return &SMSStatus{} },
+}
+
 // EventForName returns a struct matching the passed-in type.
 func EventForName(eventType string) Event {
-	switch eventType {
-	case "bounce":
-		return &Bounce{}
-	case "click":
-		return &Click{}
-	case "creation":
-		return &Creation{}
-	case "delay":
-		return &Delay{}
-	case "delivery":
-		return &Delivery{}
-	case "generation_failure":
-		return &GenerationFailure{}
-	case "generation_rejection":
-		return &GenerationRejection{}
-	case "injection":
-		return &Injection{}
-	case "list_unsubscribe":
-		return &ListUnsubscribe{}
-	case "link_unsubscribe":
-		return &LinkUnsubscribe{}
-	case "open":
-		return &Open{}
-	case "out_of_band":
-		return &OutOfBand{}
-	case "policy_rejection":
-		return &PolicyRejection{}
-	case "spam_complaint":
-		return &SpamComplaint{}
-	case "relay_delivery":
-		return &RelayDelivery{}
-	case "relay_injection":
-		return &RelayInjection{}
-	case "relay_message":
-		return &RelayMessage{}
-	case "relay_permfail":
-		return &RelayPermfail{}
-	case "relay_rejection":
-		return &RelayRejection{}
-	case "relay_tempfail":
-		return &RelayTempfail{}
-	case "sms_status":
-		return &SMSStatus{}
+	if newEvent, ok := eventTypes[eventType]; ok {
+		return newEvent()
 	}
 	return &Unknown{}
 }
 
+// Types returns the event types which have a struct, in alphabetical order.
+func Types() []string {
+	types := make([]string, 0, len(eventTypes))
+	for t := range eventTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ParseEvent decodes a single event, as found in the Events API and (once unwrapped) in webhook
+// payloads, into the struct for its "type". Events of types without a struct, or which fail to
+// decode, are returned as an *Unknown holding the raw JSON and the reason.
+func ParseEvent(rawEvent json.RawMessage) Event {
+	var typeLookup EventCommon
+	if err := json.Unmarshal(rawEvent, &typeLookup); err != nil {
+		typeLookup.Type = "unknown"
+	}
+
+	event := EventForName(typeLookup.EventType())
+	if e, ok := event.(*Unknown); ok {
+		e.EventCommon.Type = typeLookup.EventType()
+		e.RawJSON = rawEvent
+		e.Error = ErrNotImplemented
+		return e
+	}
+
+	// Unmarshal into specic event object.
+	if err := json.Unmarshal(rawEvent, &event); err != nil {
+		return &Unknown{
+			EventCommon: EventCommon{Type: typeLookup.EventType()},
+			RawJSON:     rawEvent,
+			Error:       err,
+		}
+	}
+	return event
+}
+
 func ParseRawJSONEvents(rawEvents []json.RawMessage) ([]Event, error) {
 	events := []Event{}
 
 	// Each item is event data in raw JSON.
 	for _, rawEvent := range rawEvents {
-		var typeLookup EventCommon
-		if err := json.Unmarshal(rawEvent, &typeLookup); err != nil {
-			typeLookup.Type = "unknown"
-		}
-
-		event := EventForName(typeLookup.EventType())
-		if e, ok := event.(*Unknown); ok {
-			e.EventCommon.Type = typeLookup.EventType()
-			e.RawJSON = rawEvent
-			e.Error = ErrNotImplemented
-			events = append(events, e)
-			continue
-		}
-
-		// Unmarshal into specic event object.
-		if err := json.Unmarshal(rawEvent, &event); err != nil {
-			event = &Unknown{
-				EventCommon: EventCommon{Type: typeLookup.EventType()},
-				RawJSON:     rawEvent,
-				Error:       err,
-			}
-		}
-		events = append(events, event)
+		events = append(events, ParseEvent(rawEvent))
 	}
 
 	return events, nil
 }
 
 func (events *Events) UnmarshalJSON(data []byte) error {
-	// Parse raw events from Event Webhook ("msys"-wrapped array of events).
-	rawEvents, err := parseRawJSONEventsFromWebhook(data)
+	rawEvents, err := RawEvents(data)
 	if err != nil {
-		// Parse raw events from Event Samples ("results" object with array of events).
-		rawEvents, err = parseRawJSONEventsFromSamples(data)
-		if err != nil {
-			return err
-		}
+		return err
 	}
 
 	*events, err = ParseRawJSONEvents(rawEvents)
@@ -130,18 +131,40 @@ func (events *Events) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// RawEvents splits a webhook batch (or an Event Samples response) into the JSON of each event,
+// for callers which need more than ParseEvent keeps.
+func RawEvents(data []byte) ([]json.RawMessage, error) {
+	// Parse raw events from Event Webhook ("msys"-wrapped array of events).
+	rawEvents, err := parseRawJSONEventsFromWebhook(data)
+	if err != nil {
+		// Parse raw events from Event Samples ("results" object with array of events).
+		rawEvents, err = parseRawJSONEventsFromSamples(data)
+	}
+	return rawEvents, err
+}
+
 func parseRawJSONEventsFromWebhook(data []byte) ([]json.RawMessage, error) {
 	var rawEvents []json.RawMessage
 
 	// These "msys"-wrapped events are being sent on Webhooks.
-	var msysEventWrappers []struct {
-		MsysEventWrapper map[string]json.RawMessage `json:"msys"`
-	}
-	if err := json.Unmarshal(data, &msysEventWrappers); err != nil {
+	// A plain array of events, as stored by a webhook consumer, is accepted too.
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
 		return nil, err
 	}
 
-	for _, wrapper := range msysEventWrappers {
+	for _, item := range items {
+		var wrapper struct {
+			MsysEventWrapper map[string]json.RawMessage `json:"msys"`
+			Type             string                     `json:"type"`
+		}
+		if err := json.Unmarshal(item, &wrapper); err != nil {
+			return nil, err
+		}
+		if wrapper.MsysEventWrapper == nil && wrapper.Type != "" {
+			rawEvents = append(rawEvents, item)
+			continue
+		}
 		for _, rawEvent := range wrapper.MsysEventWrapper {
 			rawEvents = append(rawEvents, rawEvent)
 		}
@@ -162,6 +185,43 @@ func parseRawJSONEventsFromSamples(data []byte) ([]json.RawMessage, error) {
 	return resultsWrapper.RawEvents, nil
 }
 
+// TimeOf returns the time an event happened, or the zero time if it has no timestamp.
+func TimeOf(e Event) time.Time {
+	v := reflect.ValueOf(e)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return time.Time{}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return time.Time{}
+	}
+	if f := v.FieldByName("Timestamp"); f.IsValid() && f.Type() == reflect.TypeOf(Timestamp{}) {
+		return time.Time(f.Interface().(Timestamp))
+	}
+	return time.Time{}
+}
+
+// Equal reports whether two events are of the same type and have the same JSON encoding.
+func Equal(a, b Event) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aj, bj)
+}
+
 func ECLog(e Event) string {
 	// XXX: this feels like the wrong way; can't figure out the right way
 	switch e.(type) {
@@ -217,8 +277,10 @@ func (t Timestamp) String() string {
 	return time.Time(t).String()
 }
 
-func (t *Timestamp) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprint(time.Time(*t).Unix())), nil
+// MarshalJSON encodes the Timestamp as a Unix timestamp, as used by webhooks.
+// It has a value receiver so Timestamps in non-addressable structs are encoded the same way.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(time.Time(t).Unix(), 10)), nil
 }
 
 func (t *Timestamp) UnmarshalJSON(data []byte) error {
@@ -253,8 +315,8 @@ type GeoIP struct {
 // The API inconsistently returns float or string. We need a custom unmarshaller.
 type LatLong float32
 
-func (v *LatLong) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprint("%v", v)), nil
+func (v LatLong) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(v), 'f', -1, 32)), nil
 }
 
 func (v *LatLong) UnmarshalJSON(data []byte) error {
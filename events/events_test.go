@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -51,3 +52,194 @@ func TestSampleWebhookValidationRequest(t *testing.T) {
 		t.Fatalf("expected zero events, got %d: %v", len(events), events)
 	}
 }
+
+func TestRoundTrip(t *testing.T) {
+	payload, err := ioutil.ReadFile("sample-events.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events Events
+	if err = json.Unmarshal(payload, &events); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range events {
+		raw, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("%s: %v", event.EventType(), err)
+		}
+		again, err := ParseRawJSONEvents([]json.RawMessage{raw})
+		if err != nil {
+			t.Fatalf("%s: %v", event.EventType(), err)
+		}
+		if !Equal(event, again[0]) {
+			t.Errorf("%s changed after a round trip:\n%s", event.EventType(), raw)
+		}
+		if Equal(event, &Unknown{}) {
+			t.Errorf("%s shouldn't equal an event of a different type", event.EventType())
+		}
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	for _, test := range []struct {
+		raw      string
+		expected Event
+	}{
+		{`{"type":"initial_open","transmission_id":"1"}`, &InitialOpen{}},
+		{`{"type":"amp_open","transmission_id":"1"}`, &AMPOpen{}},
+		{`{"type":"amp_initial_open","transmission_id":"1"}`, &AMPInitialOpen{}},
+		{`{"type":"amp_click","target_link_url":"https://example.com"}`, &AMPClick{}},
+		{`{"type":"delivery","timestamp":"1454442600"}`, &Delivery{}},
+		{`{"type":"not_a_type"}`, &Unknown{}},
+		{`{"type":"bounce","timestamp":"nope"}`, &Unknown{}},
+	} {
+		event := ParseEvent(json.RawMessage(test.raw))
+		if reflect.TypeOf(event) != reflect.TypeOf(test.expected) {
+			t.Errorf("ParseEvent(%s) => %T, want %T", test.raw, event, test.expected)
+		}
+	}
+}
+
+func TestPlainEventArray(t *testing.T) {
+	payload := []byte(`[{"type":"delivery","rcpt_to":"a@example.com"},{"type":"amp_click","rcpt_to":"b@example.com"}]`)
+	var events Events
+	if err := json.Unmarshal(payload, &events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if d, ok := events[0].(*Delivery); !ok || d.Recipient != "a@example.com" {
+		t.Errorf("unexpected first event %#v", events[0])
+	}
+	if c, ok := events[1].(*AMPClick); !ok || c.Recipient != "b@example.com" {
+		t.Errorf("unexpected second event %#v", events[1])
+	}
+}
+
+func TestSchema(t *testing.T) {
+	payload, err := Schema("click")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Title      string
+		Required   []string
+		Properties map[string]struct {
+			Type       string
+			Const      string
+			Properties map[string]struct{ Type string }
+		}
+	}
+	if err = json.Unmarshal(payload, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Title != "Click" || schema.Properties["type"].Const != "click" {
+		t.Errorf("unexpected schema %s", payload)
+	}
+	for prop, typ := range map[string]string{
+		"timestamp": "integer", "rcpt_tags": "array", "target_link_url": "string", "geo_ip": "object",
+	} {
+		if schema.Properties[prop].Type != typ {
+			t.Errorf("expected %s to be %s, got %q", prop, typ, schema.Properties[prop].Type)
+		}
+	}
+	if schema.Properties["geo_ip"].Properties["latitude"].Type != "number" {
+		t.Errorf("expected geo_ip.latitude to be a number")
+	}
+
+	if _, err = Schema("nope"); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+
+	payload, err = Schemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all struct {
+		Definitions map[string]json.RawMessage
+		OneOf       []map[string]string
+	}
+	if err = json.Unmarshal(payload, &all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Definitions) != len(Types()) || len(all.OneOf) != len(Types()) {
+		t.Errorf("expected a definition for each of %d types, got %d", len(Types()), len(all.Definitions))
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	payload, err := ioutil.ReadFile("sample-events.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events Events
+	if err = json.Unmarshal(payload, &events); err != nil {
+		t.Fatal(err)
+	}
+
+	records := map[string]*Record{}
+	for _, e := range events {
+		r, err := Flatten(e)
+		if err != nil {
+			t.Fatalf("Flatten(%s): %s", e.EventType(), err)
+		}
+		if r.Type != e.EventType() || r.Timestamp.Unix() != 1454442600 || r.CustomerID != 1 {
+			t.Errorf("%s: unexpected common columns %+v", e.EventType(), r)
+		}
+		records[r.Type] = r
+	}
+
+	for _, test := range []struct {
+		eventType string
+		check     func(r *Record) bool
+	}{
+		{"bounce", func(r *Record) bool {
+			return r.BounceClass == 1 && r.MessageSize == 1337 && r.Retries == 2 && r.Recipient == "recipient@example.com"
+		}},
+		{"delivery", func(r *Record) bool {
+			return r.MessageSize == 1337 && len(r.RecipientTags) == 2 && r.RecipientTags[1] == "US"
+		}},
+		{"injection", func(r *Record) bool {
+			return len(r.Metadata) == 1 && r.Metadata[0] == MetadataField{"customKey", "customValue"}
+		}},
+		{"spam_complaint", func(r *Record) bool { return r.FeedbackType == "abuse" }},
+		{"out_of_band", func(r *Record) bool { return r.BounceClass == 1 }},
+		{"click", func(r *Record) bool {
+			return r.GeoCity == "Columbia" && r.GeoLatitude > 39.17 && r.GeoLatitude < 39.18 &&
+				r.GeoLongitude < -76.83 && r.TargetLinkURL != "" && r.UserAgent != ""
+		}},
+		{"open", func(r *Record) bool { return r.GeoCountry == "US" && r.GeoRegion == "MD" }},
+		{"list_unsubscribe", func(r *Record) bool { return r.Sender != "" }},
+		{"link_unsubscribe", func(r *Record) bool { return r.UserAgent != "" }},
+		{"sms_status", func(r *Record) bool { return len(r.Metadata) == 0 && r.RecipientTags != nil }},
+	} {
+		r := records[test.eventType]
+		if r == nil {
+			t.Errorf("no %s event in sample-events.json", test.eventType)
+		} else if !test.check(r) {
+			t.Errorf("%s: unexpected record %+v", test.eventType, r)
+		}
+	}
+
+	open := ParseEvent(json.RawMessage(`{"type":"open","timestamp":"1454442600",
+		"rcpt_meta":{"plan":{"tier":"gold","seats":5},"flags":[true,null]},
+		"user_agent_parsed":{"agent_family":"Chrome","os_family":"Android","is_mobile":true}}`))
+	r, err := Flatten(open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.UserAgentFamily != "Chrome" || r.UserAgentOSFamily != "Android" || !r.UserAgentIsMobile {
+		t.Errorf("unexpected user agent columns %+v", r)
+	}
+	expected := []MetadataField{{"flags.0", "true"}, {"flags.1", ""}, {"plan.seats", "5"}, {"plan.tier", "gold"}}
+	if !reflect.DeepEqual(r.Metadata, expected) {
+		t.Errorf("expected metadata %v, got %v", expected, r.Metadata)
+	}
+
+	if _, err = Flatten(ParseEvent(json.RawMessage(`{"type":"mystery"}`))); err == nil {
+		t.Error("expected an error flattening an unknown event")
+	}
+}
@@ -0,0 +1,239 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Record is an event flattened into a fixed set of typed columns, for loading into
+// analytical stores such as BigQuery or ClickHouse. Every event type produces the same
+// columns; those it doesn't have are left empty.
+type Record struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+
+	MessageID       string `json:"message_id"`
+	TransmissionID  string `json:"transmission_id"`
+	CampaignID      string `json:"campaign_id"`
+	TemplateID      string `json:"template_id"`
+	TemplateVersion string `json:"template_version"`
+	CustomerID      int64  `json:"customer_id"`
+	SubaccountID    int64  `json:"subaccount_id"`
+
+	Recipient     string   `json:"recipient"`
+	RecipientType string   `json:"recipient_type"`
+	RecipientTags []string `json:"recipient_tags"`
+	// Metadata is rcpt_meta, with nested keys joined by dots and values as strings.
+	Metadata []MetadataField `json:"metadata"`
+
+	Sender        string `json:"sender"`
+	FriendlyFrom  string `json:"friendly_from"`
+	Subject       string `json:"subject"`
+	RoutingDomain string `json:"routing_domain"`
+	Binding       string `json:"binding"`
+	BindingGroup  string `json:"binding_group"`
+	IPAddress     string `json:"ip_address"`
+	MessageSize   int64  `json:"message_size"`
+	Retries       int64  `json:"retries"`
+
+	BounceClass  int64  `json:"bounce_class"`
+	ErrorCode    string `json:"error_code"`
+	Reason       string `json:"reason"`
+	RawReason    string `json:"raw_reason"`
+	FeedbackType string `json:"feedback_type"`
+
+	TargetLinkName string `json:"target_link_name"`
+	TargetLinkURL  string `json:"target_link_url"`
+
+	UserAgent             string `json:"user_agent"`
+	UserAgentFamily       string `json:"user_agent_family"`
+	UserAgentDeviceBrand  string `json:"user_agent_device_brand"`
+	UserAgentDeviceFamily string `json:"user_agent_device_family"`
+	UserAgentOSFamily     string `json:"user_agent_os_family"`
+	UserAgentOSVersion    string `json:"user_agent_os_version"`
+	UserAgentIsMobile     bool   `json:"user_agent_is_mobile"`
+	UserAgentIsProxy      bool   `json:"user_agent_is_proxy"`
+	UserAgentIsPrefetched bool   `json:"user_agent_is_prefetched"`
+
+	GeoCountry   string  `json:"geo_country"`
+	GeoRegion    string  `json:"geo_region"`
+	GeoCity      string  `json:"geo_city"`
+	GeoLatitude  float64 `json:"geo_latitude"`
+	GeoLongitude float64 `json:"geo_longitude"`
+}
+
+// MetadataField is one flattened recipient metadata value.
+type MetadataField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// flatSource holds the fields of any event, under their SparkPost names.
+type flatSource struct {
+	Type            string          `json:"type"`
+	Timestamp       Timestamp       `json:"timestamp"`
+	MessageID       string          `json:"message_id"`
+	TransmissionID  string          `json:"transmission_id"`
+	CampaignID      string          `json:"campaign_id"`
+	TemplateID      string          `json:"template_id"`
+	TemplateVersion string          `json:"template_version"`
+	CustomerID      flexInt         `json:"customer_id"`
+	SubaccountID    flexInt         `json:"subaccount_id"`
+	Recipient       string          `json:"rcpt_to"`
+	RecipientType   string          `json:"rcpt_type"`
+	Tags            []string        `json:"rcpt_tags"`
+	Metadata        json.RawMessage `json:"rcpt_meta"`
+	MessageFrom     string          `json:"msg_from"`
+	MailFrom        string          `json:"mailfrom"`
+	FriendlyFrom    string          `json:"friendly_from"`
+	Subject         string          `json:"subject"`
+	RoutingDomain   string          `json:"routing_domain"`
+	Binding         string          `json:"binding"`
+	BindingGroup    string          `json:"binding_group"`
+	IPAddress       string          `json:"ip_address"`
+	MessageSize     flexInt         `json:"msg_size"`
+	Retries         flexInt         `json:"num_retries"`
+	BounceClass     flexInt         `json:"bounce_class"`
+	ErrorCode       string          `json:"error_code"`
+	Reason          string          `json:"reason"`
+	RawReason       string          `json:"raw_reason"`
+	FeedbackType    string          `json:"fbtype"`
+	TargetLinkName  string          `json:"target_link_name"`
+	TargetLinkURL   string          `json:"target_link_url"`
+	UserAgent       string          `json:"user_agent"`
+	UserAgentParsed *UserAgent      `json:"user_agent_parsed"`
+	GeoIP           *GeoIP          `json:"geo_ip"`
+}
+
+// flexInt decodes integers the API sends as either numbers or strings.
+type flexInt int64
+
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	i, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = flexInt(i)
+	return nil
+}
+
+// Flatten converts an event into a Record.
+func Flatten(e Event) (*Record, error) {
+	if u, ok := e.(*Unknown); ok {
+		return nil, fmt.Errorf("can't flatten unknown event type %q", u.EventCommon.EventType())
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var src flatSource
+	if err = json.Unmarshal(data, &src); err != nil {
+		return nil, err
+	}
+
+	r := &Record{
+		Type:            e.EventType(),
+		MessageID:       src.MessageID,
+		TransmissionID:  src.TransmissionID,
+		CampaignID:      src.CampaignID,
+		TemplateID:      src.TemplateID,
+		TemplateVersion: src.TemplateVersion,
+		CustomerID:      int64(src.CustomerID),
+		SubaccountID:    int64(src.SubaccountID),
+		Recipient:       src.Recipient,
+		RecipientType:   src.RecipientType,
+		RecipientTags:   src.Tags,
+		Sender:          src.MessageFrom,
+		FriendlyFrom:    src.FriendlyFrom,
+		Subject:         src.Subject,
+		RoutingDomain:   src.RoutingDomain,
+		Binding:         src.Binding,
+		BindingGroup:    src.BindingGroup,
+		IPAddress:       src.IPAddress,
+		MessageSize:     int64(src.MessageSize),
+		Retries:         int64(src.Retries),
+		BounceClass:     int64(src.BounceClass),
+		ErrorCode:       src.ErrorCode,
+		Reason:          src.Reason,
+		RawReason:       src.RawReason,
+		FeedbackType:    src.FeedbackType,
+		TargetLinkName:  src.TargetLinkName,
+		TargetLinkURL:   src.TargetLinkURL,
+		UserAgent:       src.UserAgent,
+	}
+	if !time.Time(src.Timestamp).IsZero() {
+		r.Timestamp = time.Time(src.Timestamp).UTC()
+	}
+	if r.Sender == "" {
+		// unsubscribe events call it mailfrom
+		r.Sender = src.MailFrom
+	}
+	if r.RecipientTags == nil {
+		r.RecipientTags = []string{}
+	}
+	if ua := src.UserAgentParsed; ua != nil {
+		r.UserAgentFamily = ua.AgentFamily
+		r.UserAgentDeviceBrand = ua.DeviceBrand
+		r.UserAgentDeviceFamily = ua.DeviceFamily
+		r.UserAgentOSFamily = ua.OSFamily
+		r.UserAgentOSVersion = ua.OSVersion
+		r.UserAgentIsMobile = ua.IsMobile
+		r.UserAgentIsProxy = ua.IsProxy
+		r.UserAgentIsPrefetched = ua.IsPrefetched
+	}
+	if geo := src.GeoIP; geo != nil {
+		r.GeoCountry = geo.Country
+		r.GeoRegion = geo.Region
+		r.GeoCity = geo.City
+		r.GeoLatitude = float64(geo.Latitude)
+		r.GeoLongitude = float64(geo.Longitude)
+	}
+
+	r.Metadata = []MetadataField{}
+	if len(src.Metadata) > 0 {
+		var meta interface{}
+		if err = json.Unmarshal(src.Metadata, &meta); err != nil {
+			return nil, err
+		}
+		flattenMetadata(&r.Metadata, "", meta)
+		sort.Slice(r.Metadata, func(i, j int) bool { return r.Metadata[i].Key < r.Metadata[j].Key })
+	}
+	return r, nil
+}
+
+func flattenMetadata(fields *[]MetadataField, prefix string, v interface{}) {
+	key := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenMetadata(fields, key(k), child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenMetadata(fields, key(strconv.Itoa(i)), child)
+		}
+	case nil:
+		if prefix != "" {
+			*fields = append(*fields, MetadataField{Key: prefix})
+		}
+	case string:
+		*fields = append(*fields, MetadataField{Key: prefix, Value: v})
+	default:
+		// numbers and booleans, as they appear in JSON
+		b, _ := json.Marshal(v)
+		*fields = append(*fields, MetadataField{Key: prefix, Value: string(b)})
+	}
+}
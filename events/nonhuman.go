@@ -0,0 +1,173 @@
+package events
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Reasons an engagement event may be flagged as non-human by EngagementFilter.
+const (
+	// NonHumanPrefetch is an open from a privacy proxy which fetches every image when a message
+	// is delivered, such as Apple Mail Privacy Protection, rather than when it's read.
+	NonHumanPrefetch = "prefetch"
+	// NonHumanBot is an event whose user agent identifies a crawler.
+	NonHumanBot = "bot"
+	// NonHumanScanner is a click by a link scanner, identified by its user agent, or by
+	// many links in one message being clicked at once.
+	NonHumanScanner = "scanner"
+	// NonHumanDatacenter is an event from an IP address the IPClassifier placed in a datacenter.
+	NonHumanDatacenter = "datacenter"
+)
+
+// IPClassifier reports whether an IP address belongs to a datacenter, cloud provider
+// or security vendor, rather than a recipient's own connection.
+type IPClassifier interface {
+	IsDatacenter(ip net.IP) bool
+}
+
+// IPClassifierFunc allows a function to be used as an IPClassifier.
+type IPClassifierFunc func(ip net.IP) bool
+
+func (f IPClassifierFunc) IsDatacenter(ip net.IP) bool { return f(ip) }
+
+// CIDRClassifier is an IPClassifier matching a fixed list of ranges.
+type CIDRClassifier []*net.IPNet
+
+// NewCIDRClassifier parses ranges such as "35.190.0.0/17" into a CIDRClassifier.
+func NewCIDRClassifier(cidrs ...string) (CIDRClassifier, error) {
+	c := make(CIDRClassifier, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, n)
+	}
+	return c, nil
+}
+
+func (c CIDRClassifier) IsDatacenter(ip net.IP) bool {
+	for _, n := range c {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Annotated is an event, with the reasons (if any) it looks like non-human engagement.
+type Annotated struct {
+	Event    Event
+	NonHuman bool
+	Reasons  []string
+}
+
+// EngagementFilter flags opens and clicks which were likely not made by a person, so they can
+// be left out of engagement metrics. Events are annotated, never dropped, and events other
+// than opens and clicks are never flagged. The zero value applies the default heuristics,
+// without datacenter detection.
+type EngagementFilter struct {
+	// IPs, if set, flags events from datacenter addresses.
+	IPs IPClassifier
+	// ClickBurst is the number of distinct links in one message which, clicked within
+	// BurstWindow of each other, are taken to be a scanner. Defaults to 3 links in 5 seconds.
+	ClickBurst  int
+	BurstWindow time.Duration
+}
+
+var uaScanner = regexp.MustCompile(`(?i)barracuda|mimecast|proofpoint|symantec|messagelabs|trend ?micro|safelinks|urldefense|python-requests|curl/|wget/|go-http-client|java/`)
+
+// Check returns the reasons a single event looks non-human. It can't detect click bursts,
+// which need the surrounding events; use Annotate for those.
+func (f *EngagementFilter) Check(e Event) []string {
+	fields, ok := engagementFieldsOf(e)
+	if !ok {
+		return nil
+	}
+
+	var reasons []string
+	if fields.parsed != nil && fields.parsed.IsPrefetched || fields.link == "" && isPrefetchAgent(fields.userAgent) {
+		reasons = append(reasons, NonHumanPrefetch)
+	}
+	if ParseUserAgent(fields.userAgent).Category == DeviceBot {
+		reasons = append(reasons, NonHumanBot)
+	}
+	if fields.link != "" && uaScanner.MatchString(fields.userAgent) {
+		reasons = append(reasons, NonHumanScanner)
+	}
+	if f.IPs != nil {
+		if ip := net.ParseIP(fields.ipAddress); ip != nil && f.IPs.IsDatacenter(ip) {
+			reasons = append(reasons, NonHumanDatacenter)
+		}
+	}
+	return reasons
+}
+
+// isPrefetchAgent reports whether a user agent is the bare one sent by Apple Mail Privacy Protection.
+func isPrefetchAgent(ua string) bool {
+	return ua == "Mozilla/5.0"
+}
+
+// Annotate checks each event, additionally flagging bursts of clicks on different links in
+// the same message, which is how link scanners behave. Events may be in any order.
+func (f *EngagementFilter) Annotate(evs []Event) []Annotated {
+	out := make([]Annotated, len(evs))
+	type click struct {
+		idx  int
+		link string
+		at   time.Time
+	}
+	clicks := map[string][]click{}
+	for i, e := range evs {
+		out[i] = Annotated{Event: e, Reasons: f.Check(e)}
+		if fields, ok := engagementFieldsOf(e); ok && fields.link != "" && fields.messageID != "" {
+			clicks[fields.messageID] = append(clicks[fields.messageID],
+				click{i, fields.link, time.Time(fields.timestamp)})
+		}
+	}
+
+	burst, window := f.ClickBurst, f.BurstWindow
+	if burst <= 0 {
+		burst = 3
+	}
+	if window <= 0 {
+		window = 5 * time.Second
+	}
+	for _, cs := range clicks {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].at.Before(cs[j].at) })
+		flagged := map[int]bool{}
+		for start := range cs {
+			links := map[string]bool{}
+			end := start
+			for ; end < len(cs) && cs[end].at.Sub(cs[start].at) <= window; end++ {
+				links[cs[end].link] = true
+			}
+			if len(links) >= burst {
+				for _, c := range cs[start:end] {
+					flagged[c.idx] = true
+				}
+			}
+		}
+		for idx := range flagged {
+			if !hasReason(out[idx].Reasons, NonHumanScanner) {
+				out[idx].Reasons = append(out[idx].Reasons, NonHumanScanner)
+			}
+		}
+	}
+
+	for i := range out {
+		out[i].NonHuman = len(out[i].Reasons) > 0
+	}
+	return out
+}
+
+func hasReason(reasons []string, reason string) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
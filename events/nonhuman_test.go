@@ -0,0 +1,71 @@
+package events
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestEngagementFilter(t *testing.T) {
+	raw := []string{
+		// 0: a person on an iPhone
+		`{"type":"open","message_id":"m1","timestamp":"1454442600","ip_address":"203.0.113.9",
+		  "user_agent":"Mozilla/5.0 (iPhone; CPU iPhone OS 14_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148"}`,
+		// 1: Apple Mail Privacy Protection
+		`{"type":"open","message_id":"m2","timestamp":"1454442600","user_agent":"Mozilla/5.0"}`,
+		// 2: SparkPost says it was prefetched, from a datacenter address
+		`{"type":"initial_open","message_id":"m2","timestamp":"1454442600","ip_address":"35.190.1.2",
+		  "user_agent":"Mozilla/5.0 (Windows NT 10.0)","user_agent_parsed":{"is_prefetched":true}}`,
+		// 3-5: three links in one message within two seconds
+		`{"type":"click","message_id":"m3","timestamp":"1454442600","target_link_url":"https://example.com/a","user_agent":"Mozilla/5.0 (Windows NT 10.0)"}`,
+		`{"type":"click","message_id":"m3","timestamp":"1454442602","target_link_url":"https://example.com/c","user_agent":"Mozilla/5.0 (Windows NT 10.0)"}`,
+		`{"type":"click","message_id":"m3","timestamp":"1454442601","target_link_url":"https://example.com/b","user_agent":"Mozilla/5.0 (Windows NT 10.0)"}`,
+		// 6: the same person clicking again a minute later
+		`{"type":"click","message_id":"m3","timestamp":"1454442660","target_link_url":"https://example.com/a","user_agent":"Mozilla/5.0 (Windows NT 10.0)"}`,
+		// 7: a scanner identified by its user agent
+		`{"type":"amp_click","message_id":"m4","timestamp":"1454442600","target_link_url":"https://example.com/a","user_agent":"Mimecast/1.0"}`,
+		// 8: a crawler
+		`{"type":"open","message_id":"m5","timestamp":"1454442600","user_agent":"Mozilla/5.0 (compatible; bingbot/2.0)"}`,
+		// 9: not an engagement
+		`{"type":"delivery","message_id":"m1","timestamp":"1454442600","ip_address":"35.190.1.2"}`,
+	}
+	var evs []Event
+	for _, r := range raw {
+		evs = append(evs, ParseEvent(json.RawMessage(r)))
+	}
+
+	ips, err := NewCIDRClassifier("35.190.0.0/17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &EngagementFilter{IPs: ips}
+	expected := [][]string{
+		nil,
+		{NonHumanPrefetch},
+		{NonHumanPrefetch, NonHumanDatacenter},
+		{NonHumanScanner},
+		{NonHumanScanner},
+		{NonHumanScanner},
+		nil,
+		{NonHumanScanner},
+		{NonHumanBot},
+		nil,
+	}
+	for i, a := range f.Annotate(evs) {
+		if a.Event != evs[i] {
+			t.Errorf("%d: event not preserved", i)
+		}
+		if !reflect.DeepEqual(a.Reasons, expected[i]) || a.NonHuman != (len(expected[i]) > 0) {
+			t.Errorf("%d: expected %v, got %v (NonHuman %v)", i, expected[i], a.Reasons, a.NonHuman)
+		}
+	}
+
+	if _, err = NewCIDRClassifier("not a range"); err == nil {
+		t.Error("expected an error for an invalid range")
+	}
+	classifier := IPClassifierFunc(func(ip net.IP) bool { return ip.Equal(net.ParseIP("203.0.113.9")) })
+	if reasons := (&EngagementFilter{IPs: classifier}).Check(evs[0]); !reflect.DeepEqual(reasons, []string{NonHumanDatacenter}) {
+		t.Errorf("expected the custom classifier to be used, got %v", reasons)
+	}
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const jsonSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+var (
+	timestampType = reflect.TypeOf(Timestamp{})
+	latLongType   = reflect.TypeOf(LatLong(0))
+)
+
+// Schema returns a JSON Schema for events of the provided type, as encoded by encoding/json.
+// It's derived from the event's struct, so consumers in other languages stay in step with it.
+func Schema(eventType string) ([]byte, error) {
+	schema, err := eventSchema(eventType)
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = jsonSchemaVersion
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// Schemas returns a single JSON Schema matching an event of any type, with a definition for each,
+// suitable for a stream or table holding every kind of event.
+func Schemas() ([]byte, error) {
+	definitions := map[string]interface{}{}
+	var oneOf []interface{}
+	for _, t := range Types() {
+		schema, err := eventSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		definitions[t] = schema
+		oneOf = append(oneOf, map[string]string{"$ref": "#/definitions/" + t})
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema":     jsonSchemaVersion,
+		"definitions": definitions,
+		"oneOf":       oneOf,
+	}, "", "  ")
+}
+
+func eventSchema(eventType string) (map[string]interface{}, error) {
+	newEvent, ok := eventTypes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+	t := reflect.TypeOf(newEvent()).Elem()
+	schema := typeSchema(t)
+	schema["title"] = t.Name()
+	// the type property is what consumers dispatch on
+	schema["properties"].(map[string]interface{})["type"] = map[string]interface{}{
+		"type": "string", "const": eventType,
+	}
+	schema["required"] = []string{"type"}
+	return schema, nil
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timestampType:
+		return map[string]interface{}{"type": "integer", "description": "Unix timestamp"}
+	case latLongType:
+		return map[string]interface{}{"type": "number"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		addProperties(props, t)
+		return map[string]interface{}{"type": "object", "properties": props}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte, including json.RawMessage, could hold anything
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	// interface{}, such as recipient metadata
+	return map[string]interface{}{}
+}
+
+// addProperties adds the JSON fields of a struct, including those of embedded structs.
+func addProperties(props map[string]interface{}, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+			if name == "-" {
+				continue
+			}
+		}
+		if f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+			addProperties(props, f.Type)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = typeSchema(f.Type)
+	}
+}
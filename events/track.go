@@ -2,6 +2,18 @@ package events
 
 import "fmt"
 
+// UserAgent is SparkPost's breakdown of the user agent string of an engagement event.
+type UserAgent struct {
+	AgentFamily  string `json:"agent_family"`
+	DeviceBrand  string `json:"device_brand"`
+	DeviceFamily string `json:"device_family"`
+	OSFamily     string `json:"os_family"`
+	OSVersion    string `json:"os_version"`
+	IsMobile     bool   `json:"is_mobile"`
+	IsProxy      bool   `json:"is_proxy"`
+	IsPrefetched bool   `json:"is_prefetched"`
+}
+
 type Click struct {
 	EventCommon
 	CampaignID      string      `json:"campaign_id"`
@@ -21,6 +33,7 @@ type Click struct {
 	Timestamp       Timestamp   `json:"timestamp"`
 	TransmissionID  string      `json:"transmission_id"`
 	UserAgent       string      `json:"user_agent"`
+	UserAgentParsed *UserAgent  `json:"user_agent_parsed,omitempty"`
 }
 
 // String returns a brief summary of a Click event
@@ -46,6 +59,7 @@ type Open struct {
 	Timestamp       Timestamp   `json:"timestamp"`
 	TransmissionID  string      `json:"transmission_id"`
 	UserAgent       string      `json:"user_agent"`
+	UserAgentParsed *UserAgent  `json:"user_agent_parsed,omitempty"`
 }
 
 // String returns a brief summary of an Open event
@@ -53,3 +67,36 @@ func (o *Open) String() string {
 	return fmt.Sprintf("%s O %s %s",
 		o.Timestamp, o.TransmissionID, o.Recipient)
 }
+
+// InitialOpen is the first open of a message, tracked by a pixel at the top of the HTML.
+type InitialOpen Open
+
+// String returns a brief summary of an InitialOpen event
+func (o *InitialOpen) String() string {
+	return fmt.Sprintf("%s IO %s %s",
+		o.Timestamp, o.TransmissionID, o.Recipient)
+}
+
+type AMPOpen Open
+
+// String returns a brief summary of an AMPOpen event
+func (o *AMPOpen) String() string {
+	return fmt.Sprintf("%s AO %s %s",
+		o.Timestamp, o.TransmissionID, o.Recipient)
+}
+
+type AMPInitialOpen Open
+
+// String returns a brief summary of an AMPInitialOpen event
+func (o *AMPInitialOpen) String() string {
+	return fmt.Sprintf("%s AIO %s %s",
+		o.Timestamp, o.TransmissionID, o.Recipient)
+}
+
+type AMPClick Click
+
+// String returns a brief summary of an AMPClick event
+func (c *AMPClick) String() string {
+	return fmt.Sprintf("%s AC %s %s => %s",
+		c.Timestamp, c.TransmissionID, c.Recipient, c.TargetLinkURL)
+}
@@ -0,0 +1,201 @@
+package events
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Device categories reported by ParseUserAgent.
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	// DeviceProxy is a mailbox provider fetching images or links on the recipient's behalf,
+	// such as Gmail's image proxy, so the real device is unknown.
+	DeviceProxy   = "proxy"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Device describes what a recipient used to open or click a message.
+type Device struct {
+	// Category is one of the Device* constants.
+	Category  string
+	OS        string
+	OSVersion string
+	// Client is the mail client or browser, for example "Apple Mail", "Outlook" or "Chrome".
+	Client        string
+	ClientVersion string
+}
+
+var (
+	uaWindowsPhone = regexp.MustCompile(`Windows Phone(?: OS)? ([\d.]+)`)
+	uaWindows      = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	uaIOS          = regexp.MustCompile(`(?:iPhone|CPU) OS (\d+(?:_\d+)*)`)
+	uaMac          = regexp.MustCompile(`Mac OS X (\d+(?:[_.]\d+)*)`)
+	uaAndroid      = regexp.MustCompile(`Android ([\d.]+)`)
+	uaBot          = regexp.MustCompile(`(?i)bot\b|crawler|spider|slurp`)
+
+	// uaClients are tried in order, so more specific clients come first.
+	uaClients = []struct {
+		name string
+		re   *regexp.Regexp
+	}{
+		{"Gmail", regexp.MustCompile(`GoogleImageProxy`)},
+		{"Yahoo Mail", regexp.MustCompile(`YahooMailProxy`)},
+		{"Outlook", regexp.MustCompile(`(?:Microsoft Outlook|Outlook-iOS|Outlook-Android)[ /]?([\d.]*)`)},
+		{"Thunderbird", regexp.MustCompile(`Thunderbird/([\d.]+)`)},
+		{"Edge", regexp.MustCompile(`Edge?/([\d.]+)`)},
+		{"Opera", regexp.MustCompile(`OPR/([\d.]+)`)},
+		{"Firefox", regexp.MustCompile(`Firefox/([\d.]+)`)},
+		{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+		{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+		{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+		// Apple Mail uses WebKit, but doesn't identify itself as Safari
+		{"Apple Mail", regexp.MustCompile(`AppleWebKit/[\d.]+ \(KHTML, like Gecko\)(?: Mobile/\w+)?$`)},
+	}
+
+	windowsVersions = map[string]string{
+		"10.0": "10", "6.3": "8.1", "6.2": "8", "6.1": "7", "6.0": "Vista", "5.1": "XP",
+	}
+)
+
+// ParseUserAgent classifies a user agent string by device category, operating system and client.
+// It recognizes common mail clients, browsers and mailbox provider proxies; fields it can't
+// determine are left empty, and Category is then DeviceUnknown.
+func ParseUserAgent(ua string) Device {
+	d := Device{Category: DeviceUnknown}
+	if ua == "" {
+		return d
+	}
+
+	for _, c := range uaClients {
+		if m := c.re.FindStringSubmatch(ua); m != nil {
+			d.Client = c.name
+			if len(m) > 1 {
+				d.ClientVersion = m[1]
+			}
+			break
+		}
+	}
+
+	if m := uaWindowsPhone.FindStringSubmatch(ua); m != nil {
+		d.OS, d.OSVersion, d.Category = "Windows Phone", m[1], DeviceMobile
+	} else if m = uaIOS.FindStringSubmatch(ua); m != nil {
+		d.OS, d.OSVersion = "iOS", strings.Replace(m[1], "_", ".", -1)
+		d.Category = DeviceMobile
+		if strings.Contains(ua, "iPad") {
+			d.Category = DeviceTablet
+		}
+	} else if m = uaAndroid.FindStringSubmatch(ua); m != nil {
+		d.OS, d.OSVersion = "Android", m[1]
+		// Android tablets leave "Mobile" out
+		d.Category = DeviceTablet
+		if strings.Contains(ua, "Mobile") {
+			d.Category = DeviceMobile
+		}
+	} else if m = uaWindows.FindStringSubmatch(ua); m != nil {
+		d.OS, d.OSVersion, d.Category = "Windows", windowsVersions[m[1]], DeviceDesktop
+		if d.OSVersion == "" {
+			d.OSVersion = m[1]
+		}
+	} else if m = uaMac.FindStringSubmatch(ua); m != nil {
+		d.OS, d.OSVersion, d.Category = "macOS", strings.Replace(m[1], "_", ".", -1), DeviceDesktop
+	} else if strings.Contains(ua, "CrOS") {
+		d.OS, d.Category = "Chrome OS", DeviceDesktop
+	} else if strings.Contains(ua, "Linux") {
+		d.OS, d.Category = "Linux", DeviceDesktop
+	}
+
+	switch {
+	case d.Client == "Gmail" || d.Client == "Yahoo Mail":
+		d.Category, d.OS, d.OSVersion = DeviceProxy, "", ""
+	case uaBot.MatchString(ua):
+		d.Category = DeviceBot
+	}
+	return d
+}
+
+// Engagement holds the device and location details of an open or click.
+type Engagement struct {
+	Device Device
+	// Location is the zero GeoIP if the event had none.
+	Location GeoIP
+	// HasLocation reports whether the event had geo_ip coordinates.
+	HasLocation bool
+}
+
+// Latitude returns the latitude of the engagement, as a float64.
+func (e *Engagement) Latitude() float64 { return float64(e.Location.Latitude) }
+
+// Longitude returns the longitude of the engagement, as a float64.
+func (e *Engagement) Longitude() float64 { return float64(e.Location.Longitude) }
+
+// EngagementOf returns the device and location details of open and click events (including
+// initial and AMP opens and clicks), and false for other events. SparkPost's own breakdown of
+// the user agent is used where the event includes one, and ParseUserAgent otherwise.
+func EngagementOf(e Event) (*Engagement, bool) {
+	f, ok := engagementFieldsOf(e)
+	if !ok {
+		return nil, false
+	}
+
+	eng := &Engagement{Device: ParseUserAgent(f.userAgent)}
+	if parsed := f.parsed; parsed != nil {
+		if parsed.AgentFamily != "" {
+			eng.Device.Client = parsed.AgentFamily
+		}
+		if parsed.OSFamily != "" {
+			eng.Device.OS, eng.Device.OSVersion = parsed.OSFamily, parsed.OSVersion
+		}
+		switch {
+		case parsed.IsProxy || parsed.IsPrefetched:
+			eng.Device.Category = DeviceProxy
+		case parsed.IsMobile && eng.Device.Category != DeviceTablet:
+			eng.Device.Category = DeviceMobile
+		}
+	}
+	if f.geo != nil {
+		eng.Location = *f.geo
+		eng.HasLocation = f.geo.Latitude != 0 || f.geo.Longitude != 0
+	}
+	return eng, true
+}
+
+// engagementFields are the fields shared by the open and click event types.
+type engagementFields struct {
+	userAgent string
+	parsed    *UserAgent
+	geo       *GeoIP
+	ipAddress string
+	messageID string
+	// link is only set for clicks.
+	link      string
+	timestamp Timestamp
+}
+
+func engagementFieldsOf(e Event) (*engagementFields, bool) {
+	switch e := e.(type) {
+	case *Open:
+		return openFields((*Open)(e)), true
+	case *InitialOpen:
+		return openFields((*Open)(e)), true
+	case *AMPOpen:
+		return openFields((*Open)(e)), true
+	case *AMPInitialOpen:
+		return openFields((*Open)(e)), true
+	case *Click:
+		return clickFields(e), true
+	case *AMPClick:
+		return clickFields((*Click)(e)), true
+	}
+	return nil, false
+}
+
+func openFields(o *Open) *engagementFields {
+	return &engagementFields{o.UserAgent, o.UserAgentParsed, o.GeoIP, o.IPAddress, o.MessageID, "", o.Timestamp}
+}
+
+func clickFields(c *Click) *engagementFields {
+	return &engagementFields{c.UserAgent, c.UserAgentParsed, c.GeoIP, c.IPAddress, c.MessageID, c.TargetLinkURL, c.Timestamp}
+}
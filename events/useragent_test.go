@@ -0,0 +1,64 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	for _, test := range []struct {
+		ua       string
+		expected Device
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_10_3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.118 Safari/537.36",
+			Device{DeviceDesktop, "macOS", "10.10.3", "Chrome", "41.0.2272.118"}},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko)",
+			Device{DeviceDesktop, "macOS", "10.15.7", "Apple Mail", ""}},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 14_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148",
+			Device{DeviceMobile, "iOS", "14.4", "Apple Mail", ""}},
+		{"Mozilla/5.0 (iPad; CPU OS 12_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.1 Mobile/15E148 Safari/604.1",
+			Device{DeviceTablet, "iOS", "12.2", "Safari", "12.1"}},
+		{"Mozilla/5.0 (Linux; Android 10; SM-G973F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/83.0.4103.106 Mobile Safari/537.36",
+			Device{DeviceMobile, "Android", "10", "Chrome", "83.0.4103.106"}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36 Edg/91.0.864.59",
+			Device{DeviceDesktop, "Windows", "10", "Edge", "91.0.864.59"}},
+		{"Microsoft Office/16.0 (Windows NT 6.1; Microsoft Outlook 16.0.4266; Pro)",
+			Device{DeviceDesktop, "Windows", "7", "Outlook", "16.0.4266"}},
+		{"Mozilla/5.0 (Windows NT 5.1; rv:11.0) Gecko Firefox/11.0 (via ggpht.com GoogleImageProxy)",
+			Device{DeviceProxy, "", "", "Gmail", ""}},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Device{DeviceBot, "", "", "", ""}},
+		{"", Device{Category: DeviceUnknown}},
+	} {
+		if got := ParseUserAgent(test.ua); got != test.expected {
+			t.Errorf("ParseUserAgent(%q)\n got %+v\nwant %+v", test.ua, got, test.expected)
+		}
+	}
+}
+
+func TestEngagementOf(t *testing.T) {
+	click := ParseEvent(json.RawMessage(`{"type":"amp_click",
+		"user_agent":"Mozilla/5.0 (Linux; Android 9; SM-T820) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.99 Safari/537.36",
+		"geo_ip":{"country":"US","region":"MD","city":"Columbia","latitude":"39.1749","longitude":-76.8375}}`))
+	eng, ok := EngagementOf(click)
+	if !ok {
+		t.Fatal("expected an engagement for amp_click")
+	}
+	if eng.Device.Category != DeviceTablet || eng.Device.Client != "Chrome" {
+		t.Errorf("unexpected device %+v", eng.Device)
+	}
+	if !eng.HasLocation || eng.Location.Country != "US" || eng.Latitude() < 39.17 || eng.Longitude() > -76.83 {
+		t.Errorf("unexpected location %+v", eng.Location)
+	}
+
+	open := ParseEvent(json.RawMessage(`{"type":"open","user_agent":"Mozilla/5.0",
+		"user_agent_parsed":{"agent_family":"Gmail Image Proxy","os_family":"Other","is_prefetched":true}}`))
+	eng, ok = EngagementOf(open)
+	if !ok || eng.Device.Category != DeviceProxy || eng.Device.Client != "Gmail Image Proxy" || eng.HasLocation {
+		t.Errorf("unexpected engagement %+v", eng)
+	}
+
+	if _, ok = EngagementOf(&Delivery{}); ok {
+		t.Error("expected no engagement for a delivery")
+	}
+}
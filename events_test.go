@@ -0,0 +1,59 @@
+package gosparkpost_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+func TestSearchEvents(t *testing.T) {
+	var queries []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/events/message" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"results":[{"type":"delivery","rcpt_to":"a@example.com"}],"total_count":2,
+				"links":{"next":"/api/v1/events/message?cursor=abc&per_page=1"}}`)
+		} else {
+			fmt.Fprint(w, `{"results":[{"type":"bounce","rcpt_to":"b@example.com"}],"total_count":2,"links":{}}`)
+		}
+	})
+	defer done()
+
+	page, err := client.SearchEvents(&sp.EventsParams{
+		Events:      []string{"delivery", "bounce"},
+		From:        time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+		Subaccounts: []int{0},
+		PerPage:     1,
+	})
+	var got []string
+	for err == nil {
+		for _, ev := range page.Events {
+			switch e := ev.(type) {
+			case *events.Delivery:
+				got = append(got, "delivery "+e.Recipient)
+			case *events.Bounce:
+				got = append(got, "bounce "+e.Recipient)
+			}
+		}
+		page, err = page.Next()
+	}
+	if err != sp.ErrEmptyPage {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(got) != "[delivery a@example.com bounce b@example.com]" {
+		t.Errorf("unexpected events %v", got)
+	}
+	expected := "events=delivery%2Cbounce&from=2018-07-01T00%3A00%3A00Z&per_page=1&subaccounts=0"
+	if len(queries) != 2 || queries[0] != expected || queries[1] != "cursor=abc&per_page=1" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+}
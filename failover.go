@@ -0,0 +1,344 @@
+package gosparkpost
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Message is a complete email, which can be sent over either the REST API or SMTP.
+type Message struct {
+	// From is the envelope sender (return path).
+	From string
+	To   []string
+	// Raw is the message in RFC 822 format, headers and all.
+	Raw        []byte
+	CampaignID string
+	Metadata   map[string]interface{}
+}
+
+// MessageSender sends a Message. Client implements it using the REST API, and RelaySender
+// using any SMTP provider; implement it to plug in another provider's API as the Secondary
+// of a FailoverSender. Errors which mean the path used is unhealthy, rather than that the
+// message itself was refused, should have a Temporary method returning true;
+// FailoverSender only fails over on those. Those which leave it unknown whether the message
+// was accepted should also have an Ambiguous method returning true, and aren't resent.
+type MessageSender interface {
+	SendMessage(ctx context.Context, m *Message) error
+}
+
+// temporaryError marks an error as a problem with the sending path, which may have
+// happened after the message was accepted.
+type temporaryError struct {
+	error
+	ambiguous bool
+}
+
+func (e temporaryError) Temporary() bool { return true }
+func (e temporaryError) Ambiguous() bool { return e.ambiguous }
+
+func isTemporary(err error) bool {
+	t, ok := err.(interface {
+		Temporary() bool
+	})
+	return ok && t.Temporary()
+}
+
+func isAmbiguous(err error) bool {
+	a, ok := err.(interface {
+		Ambiguous() bool
+	})
+	return ok && a.Ambiguous()
+}
+
+// RESTSender sends Messages as Transmissions. Connection errors, 429s and 5xx responses
+// are temporary. Of those, only failures to connect and 429s are known to have sent nothing;
+// a 5xx, or a connection lost once the request was made, is ambiguous.
+type RESTSender struct {
+	Client *Client
+}
+
+func (s *RESTSender) SendMessage(ctx context.Context, m *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tx := &Transmission{
+		Recipients: m.To,
+		ReturnPath: m.From,
+		CampaignID: m.CampaignID,
+		Content:    Content{EmailRFC822: string(m.Raw)},
+	}
+	if m.Metadata != nil {
+		tx.Metadata = m.Metadata
+	}
+	// anything Validate catches would fail on every path
+	if err := tx.Validate(); err != nil {
+		return err
+	}
+
+	id, res, err := s.Client.Send(tx)
+	if err == nil {
+		if id == "" {
+			// never report a Message as sent without a Transmission to show for it
+			return fmt.Errorf("No Transmission id returned")
+		}
+		return nil
+	} else if res != nil && res.HTTP != nil {
+		if code := res.HTTP.StatusCode; code == 429 {
+			return temporaryError{err, false}
+		} else if code >= 500 {
+			return temporaryError{err, true}
+		}
+	} else if uerr, ok := err.(*url.Error); ok {
+		operr, ok := uerr.Err.(*net.OpError)
+		return temporaryError{err, !ok || operr.Op != "dial"}
+	}
+	return err
+}
+
+// SendMessage sends m as a Transmission, see RESTSender.
+func (c *Client) SendMessage(ctx context.Context, m *Message) error {
+	return (&RESTSender{Client: c}).SendMessage(ctx, m)
+}
+
+// SMTPSender sends Messages through an SMTPPool, passing CampaignID and Metadata in an
+// X-MSYS-API header. Errors other than 5xx SMTP replies are temporary.
+type SMTPSender struct {
+	Pool *SMTPPool
+}
+
+func (s *SMTPSender) SendMessage(ctx context.Context, m *Message) error {
+	raw := m.Raw
+	if m.CampaignID != "" || m.Metadata != nil {
+		fields := map[string]interface{}{}
+		if m.CampaignID != "" {
+			fields["campaign_id"] = m.CampaignID
+		}
+		if m.Metadata != nil {
+			fields["metadata"] = m.Metadata
+		}
+		api, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "X-MSYS-API: %s\r\n", api)
+		buf.Write(raw)
+		raw = buf.Bytes()
+	}
+
+	err := s.Pool.Send(ctx, m.From, m.To, raw)
+	if perr, ok := err.(*textproto.Error); ok && perr.Code >= 500 {
+		return err
+	} else if err != nil && err != ctx.Err() {
+		return temporaryError{err, false}
+	}
+	return err
+}
+
+// RelaySender sends Messages through another provider's SMTP relay, one connection per
+// Message, as a fallback for when SparkPost can't be reached. CampaignID and Metadata are
+// ignored. Errors other than 5xx SMTP replies are temporary.
+type RelaySender struct {
+	// Addr is the host:port of the relay.
+	Addr string
+	// Auth, if set, is used when the relay supports AUTH.
+	Auth smtp.Auth
+	// TLSConfig is used with STARTTLS, which is required unless Insecure is set.
+	// It defaults to verifying the relay's host name.
+	TLSConfig *tls.Config
+	Insecure  bool
+}
+
+func (r *RelaySender) SendMessage(ctx context.Context, m *Message) error {
+	err := r.send(ctx, m)
+	if perr, ok := err.(*textproto.Error); ok && perr.Code >= 500 {
+		return err
+	} else if err != nil && err != ctx.Err() {
+		return temporaryError{err, false}
+	}
+	return err
+}
+
+func (r *RelaySender) send(ctx context.Context, m *Message) error {
+	host, _, err := net.SplitHostPort(r.Addr)
+	if err != nil {
+		return err
+	}
+	d := &net.Dialer{Timeout: 30 * time.Second}
+	conn, err := d.DialContext(ctx, "tcp", r.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		tlsConfig := r.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: host}
+		}
+		if err = client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	} else if !r.Insecure {
+		return fmt.Errorf("SMTP relay %s doesn't support STARTTLS", r.Addr)
+	}
+	if ok, _ := client.Extension("AUTH"); ok && r.Auth != nil {
+		if err = client.Auth(r.Auth); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(m.From); err != nil {
+		return err
+	}
+	for _, addr := range m.To {
+		if err = client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(m.Raw); err != nil {
+		w.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FailoverSender sends through Primary, switching to Secondary once Primary has failed with
+// Threshold temporary errors in a row. After Cooldown, the next Message tries Primary again,
+// and if that works it's used from then on. A Message which fails with a temporary error is
+// retried on the other path, so it's only lost if both fail. An ambiguous error, after which
+// the Message may have been sent anyway, counts as a failure of the path but is returned
+// rather than retried, since that could send the Message twice. A FailoverSender may be
+// used concurrently.
+type FailoverSender struct {
+	Primary   MessageSender
+	Secondary MessageSender
+	// Threshold defaults to 3.
+	Threshold int
+	// Cooldown defaults to one minute.
+	Cooldown time.Duration
+	// OnSwitch, if set, is called (without any locks held) when the preferred path changes.
+	OnSwitch func(onSecondary bool)
+
+	mu         sync.Mutex
+	failures   int
+	switchedAt time.Time
+	stats      FailoverStats
+}
+
+// FailoverStats reports which paths a FailoverSender has used.
+type FailoverStats struct {
+	// Primary and Secondary count the Messages sent over each path.
+	Primary   int
+	Secondary int
+	// Failovers counts Messages sent on one path after failing on the other.
+	Failovers int
+	// Switches counts changes of the preferred path.
+	Switches    int
+	OnSecondary bool
+}
+
+// Stats returns a snapshot of the paths used so far.
+func (f *FailoverSender) Stats() FailoverStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+func (f *FailoverSender) SendMessage(ctx context.Context, m *Message) error {
+	primaryFirst := f.primaryFirst()
+	first, second := f.Primary, f.Secondary
+	if !primaryFirst {
+		first, second = second, first
+	}
+
+	err := first.SendMessage(ctx, m)
+	f.record(primaryFirst, err, false)
+	if err == nil || !isTemporary(err) || isAmbiguous(err) {
+		return err
+	}
+
+	if err2 := second.SendMessage(ctx, m); err2 != nil {
+		f.record(!primaryFirst, err2, true)
+		return fmt.Errorf("%s; failover failed: %s", err, err2)
+	}
+	f.record(!primaryFirst, nil, true)
+	return nil
+}
+
+// primaryFirst reports whether the next Message should try Primary first.
+func (f *FailoverSender) primaryFirst() bool {
+	cooldown := f.Cooldown
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.stats.OnSecondary || time.Since(f.switchedAt) >= cooldown
+}
+
+// record updates the health of a path after a send over it.
+func (f *FailoverSender) record(primary bool, err error, failover bool) {
+	threshold := f.Threshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+
+	f.mu.Lock()
+	before := f.stats.OnSecondary
+	switch {
+	case err == nil:
+		if primary {
+			f.stats.Primary++
+			f.failures = 0
+			f.stats.OnSecondary = false
+		} else {
+			f.stats.Secondary++
+		}
+		if failover {
+			f.stats.Failovers++
+		}
+	case primary && isTemporary(err):
+		if f.stats.OnSecondary {
+			// a failed retry of Primary restarts the cooldown
+			f.switchedAt = time.Now()
+		} else if f.failures++; f.failures >= threshold {
+			f.stats.OnSecondary = true
+			f.switchedAt = time.Now()
+		}
+	}
+	switched := f.stats.OnSecondary != before
+	if switched {
+		f.stats.Switches++
+	}
+	onSecondary := f.stats.OnSecondary
+	f.mu.Unlock()
+
+	if switched && f.OnSwitch != nil {
+		f.OnSwitch(onSecondary)
+	}
+}
@@ -0,0 +1,256 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+type temporary struct{ error }
+
+func (temporary) Temporary() bool { return true }
+
+// fakeSender fails with err, if it's set, and counts the Messages it's sent.
+type fakeSender struct {
+	err  error
+	sent int
+}
+
+func (f *fakeSender) SendMessage(ctx context.Context, m *sp.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent++
+	return nil
+}
+
+func TestFailoverSender(t *testing.T) {
+	primary, secondary := &fakeSender{err: temporary{fmt.Errorf("503")}}, &fakeSender{}
+	var switches []bool
+	f := &sp.FailoverSender{
+		Primary:   primary,
+		Secondary: secondary,
+		Cooldown:  20 * time.Millisecond,
+		OnSwitch:  func(onSecondary bool) { switches = append(switches, onSecondary) },
+	}
+	ctx := context.Background()
+	m := &sp.Message{From: "a@example.com", To: []string{"b@example.com"}, Raw: []byte("Subject: hi\r\n\r\nhi\r\n")}
+
+	for i := 0; i < 4; i++ {
+		if err := f.SendMessage(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats := f.Stats()
+	if !stats.OnSecondary || stats.Failovers != 3 || stats.Secondary != 4 || stats.Switches != 1 {
+		t.Errorf("expected to switch after 3 failovers, got %+v", stats)
+	}
+
+	// after the cooldown Primary is tried again, and kept once it works
+	time.Sleep(30 * time.Millisecond)
+	primary.err = nil
+	for i := 0; i < 2; i++ {
+		if err := f.SendMessage(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats = f.Stats()
+	if stats.OnSecondary || stats.Primary != 2 || stats.Switches != 2 {
+		t.Errorf("expected to switch back to Primary, got %+v", stats)
+	}
+	if len(switches) != 2 || !switches[0] || switches[1] {
+		t.Errorf("unexpected switches %v", switches)
+	}
+
+	// a refused message isn't retried
+	primary.err = fmt.Errorf("invalid message")
+	if err := f.SendMessage(ctx, m); err == nil || f.Stats().Secondary != 4 {
+		t.Errorf("expected no failover, got %v and %+v", err, f.Stats())
+	}
+
+	secondary.err = temporary{fmt.Errorf("down")}
+	primary.err = temporary{fmt.Errorf("503")}
+	if err := f.SendMessage(ctx, m); err == nil || !strings.Contains(err.Error(), "failover failed") {
+		t.Errorf("expected both paths to fail, got %v", err)
+	}
+}
+
+func TestRESTSender(t *testing.T) {
+	status, results := 503, `{"results":{"id":"1"}}`
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == 200 {
+			w.Write([]byte(results))
+		} else {
+			w.Write([]byte(`{"errors":[{"message":"oops","code":"1"}]}`))
+		}
+	})
+	defer done()
+
+	s := &sp.RESTSender{Client: client}
+	m := &sp.Message{From: "a@example.com", To: []string{"b@example.com"}, Raw: []byte("Subject: hi\r\n\r\nhi\r\n")}
+	isTemporary := func(err error) bool {
+		tmp, ok := err.(interface{ Temporary() bool })
+		return ok && tmp.Temporary()
+	}
+	isAmbiguous := func(err error) bool {
+		a, ok := err.(interface{ Ambiguous() bool })
+		return ok && a.Ambiguous()
+	}
+	if err := s.SendMessage(context.Background(), m); err == nil || !isTemporary(err) || !isAmbiguous(err) {
+		t.Errorf("expected an ambiguous temporary error for a 503, got %v", err)
+	}
+	status = 429
+	if err := s.SendMessage(context.Background(), m); err == nil || !isTemporary(err) || isAmbiguous(err) {
+		t.Errorf("expected an unambiguous temporary error for a 429, got %v", err)
+	}
+	status = 422
+	if err := s.SendMessage(context.Background(), m); err == nil || isTemporary(err) {
+		t.Errorf("expected a permanent error for a 422, got %v", err)
+	}
+	status = 200
+	if err := s.SendMessage(context.Background(), m); err != nil {
+		t.Error(err)
+	}
+	results = `{"results":{}}`
+	if err := s.SendMessage(context.Background(), m); err == nil {
+		t.Error("expected an error for a 200 without an id")
+	}
+
+	// nothing can have been sent if the connection was refused
+	done()
+	if err := s.SendMessage(context.Background(), m); err == nil || !isTemporary(err) || isAmbiguous(err) {
+		t.Errorf("expected an unambiguous temporary error when refused, got %v", err)
+	}
+}
+
+func TestClientSendMessage(t *testing.T) {
+	status, body := 200, `{"results":{"id":""}}`
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	defer done()
+
+	var sender sp.MessageSender = client
+	m := &sp.Message{From: "a@example.com", To: []string{"b@example.com"}, Raw: []byte("Subject: hi\r\n\r\nhi\r\n")}
+	if err := sender.SendMessage(context.Background(), m); err == nil {
+		t.Error("expected an error without a Transmission id")
+	}
+	status, body = 502, `{}`
+	if err := sender.SendMessage(context.Background(), m); err == nil {
+		t.Error("expected an error for a 502 without errors")
+	}
+	status, body = 200, `{"results":{"id":"1"}}`
+	if err := sender.SendMessage(context.Background(), m); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	server := newFakeSMTP(t, 0, false)
+	defer server.ln.Close()
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{ApiKey: "testkey"}); err != nil {
+		t.Fatal(err)
+	}
+	pool := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String()}
+	defer pool.Close()
+
+	s := &sp.SMTPSender{Pool: pool}
+	m := &sp.Message{
+		From:       "a@example.com",
+		To:         []string{"b@example.com"},
+		Raw:        []byte("Subject: hi\r\n\r\nhi\r\n"),
+		CampaignID: "spring",
+	}
+	if err := s.SendMessage(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	m.To = []string{"bad@example.com"}
+	if err := s.SendMessage(context.Background(), m); err == nil {
+		t.Error("expected an error for a rejected recipient")
+	} else if tmp, ok := err.(interface{ Temporary() bool }); ok && tmp.Temporary() {
+		t.Errorf("expected a permanent error, got %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 1 || !strings.HasPrefix(server.messages[0], `X-MSYS-API: {"campaign_id":"spring"}`) {
+		t.Errorf("unexpected messages %q", server.messages)
+	}
+}
+
+func TestFailoverToRelay(t *testing.T) {
+	status, body := 429, `{"errors":[{"message":"Unavailable"}]}`
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	defer done()
+
+	relay := newFakeSMTP(t, 0, false)
+	defer relay.ln.Close()
+
+	secure := &sp.RelaySender{Addr: relay.ln.Addr().String()}
+	m := &sp.Message{From: "a@example.com", To: []string{"b@example.com"}, Raw: []byte("Subject: hi\r\n\r\nhi\r\n")}
+	if err := secure.SendMessage(context.Background(), m); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("expected an error without STARTTLS, got %v", err)
+	}
+
+	f := &sp.FailoverSender{
+		Primary:   client,
+		Secondary: &sp.RelaySender{Addr: relay.ln.Addr().String(), Insecure: true},
+		Threshold: 1,
+	}
+	for i := 0; i < 2; i++ {
+		if err := f.SendMessage(context.Background(), m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := f.Stats(); !stats.OnSecondary || stats.Secondary != 2 || stats.Failovers != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// a 5xx may have been accepted anyway, so it's returned rather than resent
+	status = 503
+	f = &sp.FailoverSender{Primary: client, Secondary: f.Secondary, Threshold: 1}
+	if err := f.SendMessage(context.Background(), m); err == nil {
+		t.Error("expected the 503 to be returned")
+	}
+	if stats := f.Stats(); !stats.OnSecondary || stats.Failovers != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// a failure without an errors body is still a failure, not a silent success
+	status, body = 503, `{}`
+	f = &sp.FailoverSender{Primary: client, Secondary: f.Secondary, Threshold: 1}
+	if err := f.SendMessage(context.Background(), m); err == nil || err.Error() != "503: {}" {
+		t.Errorf("expected the 503 to be returned, got %v", err)
+	}
+	// and counts towards Threshold, so the next message goes to the relay
+	if stats := f.Stats(); stats.Primary != 0 || !stats.OnSecondary || stats.Switches != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if err := f.SendMessage(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	if stats := f.Stats(); stats.Secondary != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	relay.mu.Lock()
+	defer relay.mu.Unlock()
+	if len(relay.messages) != 3 {
+		t.Errorf("expected 3 messages through the relay, got %d", len(relay.messages))
+	}
+}
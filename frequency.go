@@ -0,0 +1,153 @@
+package gosparkpost
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFrequencyCapped is returned by Send when every Recipient is over the FrequencyCap.
+var ErrFrequencyCapped = errors.New("all recipients are over their frequency cap")
+
+// FrequencyStore remembers when each email address was sent to.
+// Implementations backed by shared storage let the cap apply across processes.
+type FrequencyStore interface {
+	// SentSince returns the times email was sent to since the provided time.
+	SentSince(email string, since time.Time) ([]time.Time, error)
+	RecordSent(emails []string, at time.Time) error
+}
+
+// FrequencyCap limits how many marketing emails each Recipient gets in a rolling window,
+// which SparkPost doesn't do server-side. Set it as Client.FrequencyCap to apply it on every
+// Send of a Transmission which isn't marked transactional. Only inline Recipients are capped.
+//
+// Recipients over the cap are removed from the Transmission and passed to Report. The check
+// and the record of a send aren't atomic, so concurrent sends to the same address may
+// occasionally go over the cap.
+type FrequencyCap struct {
+	Store  FrequencyStore
+	Max    int
+	Window time.Duration
+	// Defer reports over-cap Recipients as deferred, with the time they can next be sent to,
+	// instead of dropped, for the caller to send to later.
+	Defer  bool
+	Report func(FrequencyReport)
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// FrequencyReport lists the Recipients which were left out of a send.
+type FrequencyReport struct {
+	Dropped  []Recipient
+	Deferred []DeferredRecipient
+}
+
+// DeferredRecipient is a Recipient which is over the cap until Until.
+type DeferredRecipient struct {
+	Recipient
+	Until time.Time
+}
+
+func (f *FrequencyCap) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+	return time.Now()
+}
+
+// Filter returns the provided Recipients which are under the cap, reporting the others.
+// It returns nil for a stored recipient list, which can't be capped.
+func (f *FrequencyCap) Filter(recips interface{}) ([]Recipient, error) {
+	list, err := inlineRecipients(recips)
+	if err != nil || list == nil {
+		return nil, err
+	}
+
+	now := f.now()
+	allowed := make([]Recipient, 0, len(list))
+	var report FrequencyReport
+	for _, r := range list {
+		addr, err := ParseAddress(r.Address)
+		if err != nil {
+			return nil, err
+		}
+		sent, err := f.Store.SentSince(strings.ToLower(addr.Email), now.Add(-f.Window))
+		if err != nil {
+			return nil, err
+		}
+		if len(sent) < f.Max {
+			allowed = append(allowed, r)
+		} else if f.Defer {
+			// the recipient is under the cap once enough of these sends leave the window
+			sort.Slice(sent, func(i, j int) bool { return sent[i].Before(sent[j]) })
+			until := sent[len(sent)-f.Max].Add(f.Window)
+			report.Deferred = append(report.Deferred, DeferredRecipient{Recipient: r, Until: until})
+		} else {
+			report.Dropped = append(report.Dropped, r)
+		}
+	}
+
+	if f.Report != nil && len(allowed) < len(list) {
+		f.Report(report)
+	}
+	if len(allowed) == 0 {
+		return nil, ErrFrequencyCapped
+	}
+	return allowed, nil
+}
+
+// Record counts a send to each of the provided Recipients against the cap.
+func (f *FrequencyCap) Record(recips []Recipient) error {
+	emails := make([]string, 0, len(recips))
+	for _, r := range recips {
+		addr, err := ParseAddress(r.Address)
+		if err != nil {
+			return err
+		}
+		emails = append(emails, strings.ToLower(addr.Email))
+	}
+	return f.Store.RecordSent(emails, f.now())
+}
+
+// transactional returns whether t is marked as transactional, so isn't frequency capped.
+func (t *Transmission) transactional() bool {
+	return t.Options != nil && t.Options.Transactional
+}
+
+// MemoryFrequencyStore is a FrequencyStore that lives in process memory.
+type MemoryFrequencyStore struct {
+	mu   sync.Mutex
+	sent map[string][]time.Time
+}
+
+func (m *MemoryFrequencyStore) SentSince(email string, since time.Time) ([]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var recent []time.Time
+	for _, at := range m.sent[email] {
+		if at.After(since) {
+			recent = append(recent, at)
+		}
+	}
+	// forget sends which have left the window
+	if len(recent) == 0 {
+		delete(m.sent, email)
+	} else {
+		m.sent[email] = recent
+	}
+	return append([]time.Time(nil), recent...), nil
+}
+
+func (m *MemoryFrequencyStore) RecordSent(emails []string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sent == nil {
+		m.sent = map[string][]time.Time{}
+	}
+	for _, email := range emails {
+		m.sent[email] = append(m.sent[email], at)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestFrequencyCap(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &sp.MemoryFrequencyStore{}
+	store.RecordSent([]string{"a@example.com"}, now.Add(-30*time.Hour))
+	store.RecordSent([]string{"a@example.com", "b@example.com"}, now.Add(-2*time.Hour))
+
+	var report sp.FrequencyReport
+	f := &sp.FrequencyCap{
+		Store:  store,
+		Max:    2,
+		Window: 48 * time.Hour,
+		Report: func(r sp.FrequencyReport) { report = r },
+		Now:    func() time.Time { return now },
+	}
+	recips := []string{"A@Example.com", "b@example.com", "c@example.com"}
+	allowed, err := f.Filter(recips)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allowed) != 2 || len(report.Dropped) != 1 || report.Dropped[0].Address != "A@Example.com" {
+		t.Fatalf("unexpected allowed %v, report %+v", allowed, report)
+	}
+
+	f.Defer = true
+	if _, err = f.Filter(recips); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deferred) != 1 || !report.Deferred[0].Until.Equal(now.Add(18*time.Hour)) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	if err = f.Record(allowed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Filter([]string{"a@example.com", "b@example.com"}); err != sp.ErrFrequencyCapped {
+		t.Errorf("expected ErrFrequencyCapped, got %v", err)
+	}
+	if list, err := f.Filter(map[string]string{"list_id": "vips"}); list != nil || err != nil {
+		t.Errorf("expected a stored list to be left alone, got %v, %v", list, err)
+	}
+}
+
+func TestClientFrequencyCap(t *testing.T) {
+	var body struct {
+		Recipients []struct {
+			Address sp.Address `json:"address"`
+		} `json:"recipients"`
+	}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+	client.FrequencyCap = &sp.FrequencyCap{Store: &sp.MemoryFrequencyStore{}, Max: 1, Window: time.Hour}
+
+	tx := func(recips ...string) *sp.Transmission {
+		return &sp.Transmission{
+			Recipients: recips,
+			Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+		}
+	}
+	if _, _, err := client.Send(tx("a@example.com")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Send(tx("a@example.com", "b@example.com")); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Recipients) != 1 || body.Recipients[0].Address.Email != "b@example.com" {
+		t.Errorf("expected only b to be sent to, got %+v", body.Recipients)
+	}
+	if _, _, err := client.Send(tx("b@example.com")); err != sp.ErrFrequencyCapped {
+		t.Errorf("expected ErrFrequencyCapped, got %v", err)
+	}
+
+	// transactional mail isn't capped
+	transactional := tx("a@example.com")
+	transactional.Options = &sp.TxOptions{TmplOptions: sp.TmplOptions{Transactional: true}}
+	if _, _, err := client.Send(transactional); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,344 @@
+// Package gotmpl converts Go text/template source to the SparkPost template language,
+// so existing Go templates can be stored as SparkPost templates.
+//
+// Only a subset of text/template can be expressed:
+//
+//	{{.Field.Sub}}                   becomes {{ Field.Sub }}
+//	{{if .A}} {{else if .B}} {{else}} become {{ if A }} {{ elseif B }} {{ else }}
+//	{{range .Items}}{{.Name}}{{end}} becomes {{ each Items }}{{ loop_var.Name }}{{ end }}
+//	{{range $item := .Items}}        $item becomes loop_var, or loop_vars.Items from an inner loop
+//	eq ne lt le gt ge and or not     become == != < <= > >= and or not
+//
+// SparkPost has no parentheses for grouping, so arguments are limited to those which keep
+// their meaning without them: comparisons of values, not of a value, and and or of those.
+// Anything else (with, define, template, other functions, pipelines) is an error.
+package gotmpl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template/parse"
+)
+
+// Converter converts Go templates. The zero value keeps field names as they are.
+type Converter struct {
+	// FieldName, if set, renames each field, for example from FirstName to first_name
+	// to match substitution data encoded from tagged structs.
+	FieldName func(string) string
+}
+
+// Convert converts src using the zero Converter.
+func Convert(src string) (string, error) {
+	return (&Converter{}).Convert(src)
+}
+
+var operators = map[string]string{
+	"eq": "==", "ne": "!=", "lt": "<", "le": "<=", "gt": ">", "ge": ">=",
+}
+
+// builtins are the functions the parser should accept; the rest are rejected by it.
+// Only the names matter, but the parser ignores nil values.
+var builtins = map[string]interface{}{
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+	"and": true, "or": true, "not": true,
+}
+
+// loop is an enclosing {{range}}.
+type loop struct {
+	array    string
+	variable string
+}
+
+type converter struct {
+	*Converter
+	tree  *parse.Tree
+	buf   bytes.Buffer
+	loops []loop
+}
+
+// Convert converts src to the SparkPost template language.
+func (c *Converter) Convert(src string) (string, error) {
+	trees, err := parse.Parse("template", src, "", "", builtins)
+	if err != nil {
+		return "", err
+	}
+	if len(trees) > 1 {
+		return "", fmt.Errorf("Nested template definitions aren't supported")
+	}
+	tree := trees["template"]
+	if tree == nil || tree.Root == nil {
+		return "", nil
+	}
+	conv := &converter{Converter: c, tree: tree}
+	if err = conv.list(tree.Root); err != nil {
+		return "", err
+	}
+	return conv.buf.String(), nil
+}
+
+func (c *converter) unsupported(n parse.Node, what string) error {
+	location, _ := c.tree.ErrorContext(n)
+	return fmt.Errorf("%s: %s isn't supported: %s", location, what, n)
+}
+
+func (c *converter) list(l *parse.ListNode) error {
+	if l == nil {
+		return nil
+	}
+	for _, n := range l.Nodes {
+		if err := c.node(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) node(n parse.Node) error {
+	switch n := n.(type) {
+	case *parse.TextNode:
+		c.buf.Write(n.Text)
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return c.unsupported(n, "variable declaration")
+		}
+		expr, err := c.pipe(n.Pipe)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&c.buf, "{{ %s }}", expr)
+	case *parse.IfNode:
+		return c.ifNode(n, "if")
+	case *parse.RangeNode:
+		return c.rangeNode(n)
+	default:
+		return c.unsupported(n, "this action")
+	}
+	return nil
+}
+
+func (c *converter) ifNode(n *parse.IfNode, keyword string) error {
+	cond, err := c.pipe(n.Pipe)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&c.buf, "{{ %s %s }}", keyword, cond)
+	if err = c.list(n.List); err != nil {
+		return err
+	}
+	if n.ElseList != nil {
+		// {{else if}} is parsed as an {{if}} alone in the else branch
+		var elseif *parse.IfNode
+		if len(n.ElseList.Nodes) == 1 {
+			elseif, _ = n.ElseList.Nodes[0].(*parse.IfNode)
+		}
+		if elseif != nil {
+			err = c.ifNode(elseif, "elseif")
+		} else {
+			c.buf.WriteString("{{ else }}")
+			err = c.list(n.ElseList)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if keyword == "if" {
+		c.buf.WriteString("{{ end }}")
+	}
+	return nil
+}
+
+func (c *converter) rangeNode(n *parse.RangeNode) error {
+	if len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) != 1 {
+		return c.unsupported(n, "ranging over anything but a field")
+	}
+	array, err := c.arg(n.Pipe.Cmds[0].Args[0])
+	if err != nil {
+		return err
+	}
+	l := loop{array: array}
+	if i := strings.LastIndex(array, "."); i >= 0 {
+		l.array = array[i+1:]
+	}
+	switch len(n.Pipe.Decl) {
+	case 1:
+		l.variable = n.Pipe.Decl[0].Ident[0]
+	case 2:
+		return c.unsupported(n, "ranging with an index")
+	}
+
+	// {{range}} with {{else}} becomes {{ if }}{{ each }}...{{ end }}{{ else }}...{{ end }}
+	if n.ElseList != nil {
+		fmt.Fprintf(&c.buf, "{{ if %s }}", array)
+	}
+	fmt.Fprintf(&c.buf, "{{ each %s }}", array)
+	c.loops = append(c.loops, l)
+	err = c.list(n.List)
+	c.loops = c.loops[:len(c.loops)-1]
+	if err != nil {
+		return err
+	}
+	c.buf.WriteString("{{ end }}")
+	if n.ElseList != nil {
+		c.buf.WriteString("{{ else }}")
+		if err = c.list(n.ElseList); err != nil {
+			return err
+		}
+		c.buf.WriteString("{{ end }}")
+	}
+	return nil
+}
+
+func (c *converter) pipe(p *parse.PipeNode) (string, error) {
+	if len(p.Cmds) != 1 {
+		return "", c.unsupported(p, "a pipeline")
+	}
+	expr, _, err := c.command(p.Cmds[0])
+	return expr, err
+}
+
+// Precedence of converted expressions, which the SparkPost template language has no
+// parentheses to override, so an argument can only be converted if it binds tighter
+// than the function it's passed to. Comparisons, for example, can be combined with and,
+// but not can't be applied to them.
+const (
+	precAtom = iota
+	precComparison
+	precNot
+	precAndOr
+)
+
+// command converts a single command, also returning the precedence of the result.
+func (c *converter) command(cmd *parse.CommandNode) (string, int, error) {
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		if len(cmd.Args) > 1 {
+			return "", 0, c.unsupported(cmd, "calling a method")
+		}
+		expr, err := c.arg(cmd.Args[0])
+		return expr, precAtom, err
+	}
+
+	// the loosest binding argument each function can take
+	var prec, loosest int
+	switch ident.Ident {
+	case "not":
+		prec, loosest = precNot, precAtom
+	case "and", "or":
+		prec, loosest = precAndOr, precNot
+	default:
+		prec, loosest = precComparison, precAtom
+	}
+
+	args := make([]string, len(cmd.Args)-1)
+	for i, a := range cmd.Args[1:] {
+		pipe, ok := a.(*parse.PipeNode)
+		if !ok {
+			var err error
+			if args[i], err = c.arg(a); err != nil {
+				return "", 0, err
+			}
+			continue
+		}
+		if len(pipe.Cmds) != 1 {
+			return "", 0, c.unsupported(pipe, "a pipeline")
+		}
+		arg, argPrec, err := c.command(pipe.Cmds[0])
+		if err != nil {
+			return "", 0, err
+		}
+		if argPrec > loosest {
+			if prec == precAndOr && argPrec == precAndOr {
+				return "", 0, c.unsupported(cmd, "nesting and and or")
+			}
+			return "", 0, c.unsupported(cmd, fmt.Sprintf("passing (%s) to %s", pipe, ident.Ident))
+		}
+		args[i] = arg
+	}
+
+	switch ident.Ident {
+	case "not":
+		if len(args) != 1 {
+			return "", 0, c.unsupported(cmd, "not with more than one argument")
+		}
+		return "not " + args[0], prec, nil
+	case "and", "or":
+		return strings.Join(args, " "+ident.Ident+" "), prec, nil
+	}
+	op := operators[ident.Ident]
+	if len(args) != 2 {
+		return "", 0, c.unsupported(cmd, ident.Ident+" without exactly two arguments")
+	}
+	return args[0] + " " + op + " " + args[1], prec, nil
+}
+
+func (c *converter) arg(n parse.Node) (string, error) {
+	switch n := n.(type) {
+	case *parse.FieldNode:
+		return c.path(c.dot(), n.Ident), nil
+	case *parse.DotNode:
+		if len(c.loops) == 0 {
+			return "", c.unsupported(n, "printing . outside {{range}}")
+		}
+		return "loop_var", nil
+	case *parse.VariableNode:
+		return c.variable(n)
+	case *parse.StringNode:
+		if strings.Contains(n.Text, "'") {
+			if strings.Contains(n.Text, `"`) {
+				return "", c.unsupported(n, "a string containing both kinds of quote")
+			}
+			return `"` + n.Text + `"`, nil
+		}
+		return "'" + n.Text + "'", nil
+	case *parse.NumberNode:
+		return n.Text, nil
+	case *parse.BoolNode:
+		return n.String(), nil
+	}
+	return "", c.unsupported(n, "this argument")
+}
+
+// dot returns what . refers to.
+func (c *converter) dot() string {
+	if len(c.loops) > 0 {
+		return "loop_var"
+	}
+	return ""
+}
+
+// variable converts $, $.Field and variables declared by {{range}}.
+func (c *converter) variable(n *parse.VariableNode) (string, error) {
+	name := n.Ident[0]
+	if name == "$" {
+		if len(n.Ident) == 1 {
+			return "", c.unsupported(n, "printing $")
+		}
+		return c.path("", n.Ident[1:]), nil
+	}
+	for i := len(c.loops) - 1; i >= 0; i-- {
+		if c.loops[i].variable != name {
+			continue
+		}
+		root := "loop_var"
+		if i != len(c.loops)-1 {
+			root = "loop_vars." + c.loops[i].array
+		}
+		return c.path(root, n.Ident[1:]), nil
+	}
+	return "", c.unsupported(n, "variable "+name)
+}
+
+func (c *converter) path(root string, fields []string) string {
+	parts := make([]string, 0, len(fields)+1)
+	if root != "" {
+		parts = append(parts, root)
+	}
+	for _, f := range fields {
+		if c.FieldName != nil {
+			f = c.FieldName(f)
+		}
+		parts = append(parts, f)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,67 @@
+package gotmpl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SparkPost/gosparkpost/gotmpl"
+)
+
+func TestConvert(t *testing.T) {
+	for idx, test := range []struct {
+		in  string
+		out string
+	}{
+		{`Hi {{.User.Name}}!`, `Hi {{ User.Name }}!`},
+		{`{{if .Coupon}}Use {{.Coupon}}{{else if not .Member}}Join{{else}}Thanks{{end}}`,
+			`{{ if Coupon }}Use {{ Coupon }}{{ elseif not Member }}Join{{ else }}Thanks{{ end }}`},
+		{`{{if and (eq .Tier "gold") (gt .Points 100)}}VIP{{end}}`,
+			`{{ if Tier == 'gold' and Points > 100 }}VIP{{ end }}`},
+		{`{{if or (not .A) (ne .B 2)}}x{{end}}`,
+			`{{ if not A or B != 2 }}x{{ end }}`},
+		{`{{range .Items}}<li>{{.Name}}</li>{{else}}None{{end}}`,
+			`{{ if Items }}{{ each Items }}<li>{{ loop_var.Name }}</li>{{ end }}{{ else }}None{{ end }}`},
+		{`{{range $o := .Orders}}{{range .Lines}}{{.SKU}} {{$o.ID}} {{$.Currency}}{{end}}{{end}}`,
+			`{{ each Orders }}{{ each loop_var.Lines }}{{ loop_var.SKU }} {{ loop_vars.Orders.ID }} {{ Currency }}{{ end }}{{ end }}`},
+		{"{{/* dropped */}}a {{- .B -}} c", `a{{ B }}c`},
+	} {
+		out, err := gotmpl.Convert(test.in)
+		if err != nil {
+			t.Errorf("Convert[%d] => err %q", idx, err)
+		} else if out != test.out {
+			t.Errorf("Convert[%d] =>\n got %s\nwant %s", idx, out, test.out)
+		}
+	}
+
+	for idx, test := range []struct {
+		in  string
+		err string
+	}{
+		{`{{with .User}}{{.Name}}{{end}}`, "isn't supported"},
+		{`{{.Name | printf "%s"}}`, "function \"printf\" not defined"},
+		{`{{.Name | html}}`, "function \"html\" not defined"},
+		{`{{range $i, $e := .Items}}{{end}}`, "ranging with an index"},
+		{`{{if or (and .A .B) .C}}{{end}}`, "nesting and and or"},
+		{`{{if not (or .A .B)}}{{end}}`, "passing (or .A .B) to not"},
+		{`{{if not (eq .A 1)}}{{end}}`, "passing (eq .A 1) to not"},
+		{`{{if eq (and .A .B) true}}{{end}}`, "passing (and .A .B) to eq"},
+		{`{{if eq (not .A) .B}}{{end}}`, "passing (not .A) to eq"},
+		{`{{.}}`, "printing . outside"},
+	} {
+		_, err := gotmpl.Convert(test.in)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Convert[%d] => err %v, want %q", idx, err, test.err)
+		}
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	c := &gotmpl.Converter{FieldName: strings.ToLower}
+	out, err := c.Convert(`{{range .Items}}{{.Name}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != `{{ each items }}{{ loop_var.name }}{{ end }}` {
+		t.Errorf("got %s", out)
+	}
+}
@@ -0,0 +1,152 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var grantsPathFormat = "/api/v%d/authenticate/grants"
+
+// Grant is a permission given to an API key. It's a string, so the constants below can
+// be used in Subaccount.Grants directly.
+type Grant = string
+
+// Grants which may be given to Subaccount API keys.
+const (
+	GrantSMTPInject                Grant = "smtp/inject"
+	GrantSendingDomainsManage      Grant = "sending_domains/manage"
+	GrantTrackingDomainsView       Grant = "tracking_domains/view"
+	GrantTrackingDomainsManage     Grant = "tracking_domains/manage"
+	GrantMessageEventsView         Grant = "message_events/view"
+	GrantEventsSearch              Grant = "events/search"
+	GrantSuppressionListsManage    Grant = "suppression_lists/manage"
+	GrantTransmissionsView         Grant = "transmissions/view"
+	GrantTransmissionsModify       Grant = "transmissions/modify"
+	GrantTemplatesView             Grant = "templates/view"
+	GrantTemplatesModify           Grant = "templates/modify"
+	GrantRecipientListsManage      Grant = "recipient_lists/manage"
+	GrantWebhooksView              Grant = "webhooks/view"
+	GrantWebhooksModify            Grant = "webhooks/modify"
+	GrantMetricsView               Grant = "metrics/view"
+	GrantInboundDomainsManage      Grant = "inbound_domains/manage"
+	GrantRelayWebhooksView         Grant = "relay_webhooks/view"
+	GrantRelayWebhooksModify       Grant = "relay_webhooks/modify"
+	GrantABTestingManage           Grant = "ab_testing/manage"
+	GrantRecipientValidationManage Grant = "recipient_validation/manage"
+	GrantSignalsView               Grant = "signals/view"
+)
+
+// KnownGrants are the Grants this library knows about. The API may accept others;
+// see Client.Grants.
+var KnownGrants = []Grant{
+	GrantSMTPInject,
+	GrantSendingDomainsManage,
+	GrantTrackingDomainsView,
+	GrantTrackingDomainsManage,
+	GrantMessageEventsView,
+	GrantEventsSearch,
+	GrantSuppressionListsManage,
+	GrantTransmissionsView,
+	GrantTransmissionsModify,
+	GrantTemplatesView,
+	GrantTemplatesModify,
+	GrantRecipientListsManage,
+	GrantWebhooksView,
+	GrantWebhooksModify,
+	GrantMetricsView,
+	GrantInboundDomainsManage,
+	GrantRelayWebhooksView,
+	GrantRelayWebhooksModify,
+	GrantABTestingManage,
+	GrantRecipientValidationManage,
+	GrantSignalsView,
+}
+
+// Grants returns the grants the API currently accepts.
+func (c *Client) Grants() ([]Grant, *Response, error) {
+	path := fmt.Sprintf(grantsPathFormat, c.Config.ApiVersion)
+	res, err := c.HttpGet(c.Config.BaseUrl + path)
+	if err != nil {
+		return nil, res, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		if body, err = res.ReadBody(); err != nil {
+			return nil, res, err
+		}
+		// grants are listed either as strings, or objects with a key
+		var wrapper struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if err = json.Unmarshal(body, &wrapper); err != nil {
+			return nil, res, err
+		}
+		grants := make([]Grant, 0, len(wrapper.Results))
+		for _, raw := range wrapper.Results {
+			var g string
+			if json.Unmarshal(raw, &g) != nil {
+				var obj struct {
+					Key string `json:"key"`
+				}
+				if err = json.Unmarshal(raw, &obj); err != nil {
+					return nil, res, err
+				}
+				g = obj.Key
+			}
+			grants = append(grants, g)
+		}
+		return grants, res, nil
+	}
+
+	if err = res.ParseResponse(); err != nil {
+		return nil, res, err
+	}
+	if err = res.PrettyError("Grants", "list"); err != nil {
+		return nil, res, err
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// validateGrants checks each of grants is known, asking the API about any which aren't,
+// in case they're newer than this library.
+func (c *Client) validateGrants(grants []Grant) error {
+	var unknown []Grant
+	for _, g := range grants {
+		if !containsGrant(KnownGrants, g) {
+			unknown = append(unknown, g)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	valid, _, err := c.Grants()
+	if err != nil {
+		return fmt.Errorf("Checking grants [%s]: %s", strings.Join(unknown, ", "), err)
+	}
+	var invalid []Grant
+	for _, g := range unknown {
+		if !containsGrant(valid, g) {
+			invalid = append(invalid, g)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("Invalid grants [%s]", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func containsGrant(grants []Grant, g Grant) bool {
+	for _, v := range grants {
+		if v == g {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// Actions recommended by HygieneReport.
+const (
+	// HygieneSuppress means the recipient should be added to the suppression list: for all mail
+	// if it hard bounced, and for non-transactional mail if it's chronically unengaged.
+	HygieneSuppress = "suppress"
+	// HygieneSegment means never-engaged recipients at a sending domain should be split
+	// into a separate, lower-volume segment or a re-engagement campaign.
+	HygieneSegment = "segment"
+	// HygieneReview means mail to a recipient domain is bouncing enough that the list source should be reviewed.
+	HygieneReview = "review"
+)
+
+// Bounce classes which mean the address will never accept mail.
+// https://www.sparkpost.com/docs/deliverability/bounce-classification-codes/
+var hardBounceClasses = "10,30,90"
+
+// HygienePolicy holds the thresholds used to build hygiene recommendations.
+type HygienePolicy struct {
+	// From and To limit the time window examined. To defaults to now, From to 30 days before To.
+	From time.Time
+	To   time.Time
+	// NeverEngagedShare is the fraction of never-engaged recipients at a sending domain
+	// above which segmenting is recommended. Defaults to 0.2.
+	NeverEngagedShare float64
+	// HardBounceRate is the fraction of targeted messages hard-bouncing at a recipient
+	// domain above which a review is recommended. Defaults to 0.02.
+	HardBounceRate float64
+	// UnengagedDeliveries is the number of messages delivered to a recipient in the window,
+	// without any of them being opened or clicked, after which suppressing non-transactional
+	// mail to the recipient is recommended. Defaults to 5.
+	UnengagedDeliveries int
+	// DryRun, if set, stops Apply from changing anything.
+	DryRun bool
+}
+
+// HygieneRecommendation describes a single suggested change.
+type HygieneRecommendation struct {
+	Action string
+	// Target is a recipient email address or a domain, depending on Action.
+	Target string
+	Reason string
+	// Transactional is set when a suppression should include transactional mail.
+	Transactional bool
+}
+
+// HygieneReport collects the recommendations for an account.
+type HygieneReport struct {
+	Policy          HygienePolicy
+	Recommendations []HygieneRecommendation
+}
+
+func (p *HygienePolicy) defaults() {
+	if p.To.IsZero() {
+		p.To = time.Now()
+	}
+	if p.From.IsZero() {
+		p.From = p.To.AddDate(0, 0, -30)
+	}
+	if p.NeverEngagedShare == 0 {
+		p.NeverEngagedShare = 0.2
+	}
+	if p.HardBounceRate == 0 {
+		p.HardBounceRate = 0.02
+	}
+	if p.UnengagedDeliveries == 0 {
+		p.UnengagedDeliveries = 5
+	}
+}
+
+// HygieneReport combines engagement recency cohorts, bounce metrics, hard bounce and
+// engagement events, and the suppression list, into recommendations following SparkPost's
+// list hygiene best practices. Recipients which are already suppressed are left out.
+// Nothing is changed until the report is passed to ApplyHygiene.
+func (c *Client) HygieneReport(policy HygienePolicy) (*HygieneReport, error) {
+	policy.defaults()
+	report := &HygieneReport{Policy: policy}
+
+	// sending domains with too many recipients who have never engaged
+	cohorts, _, err := c.EngagementCohorts(SignalsFacetSendingDomain,
+		&SignalsParams{From: policy.From, To: policy.To})
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range cohorts {
+		if len(result.History) == 0 {
+			continue
+		}
+		latest := result.History[len(result.History)-1]
+		if latest.Total == 0 {
+			continue
+		}
+		share := float64(latest.NeverEngaged) / float64(latest.Total)
+		if share > policy.NeverEngagedShare {
+			report.Recommendations = append(report.Recommendations, HygieneRecommendation{
+				Action: HygieneSegment,
+				Target: result.Facet,
+				Reason: fmt.Sprintf("%.1f%% of recipients have never engaged", share*100),
+			})
+		}
+	}
+
+	// recipient domains with a high hard bounce rate
+	metrics, err := c.QueryDeliverabilityMetrics("domain", map[string]string{
+		"from":    policy.From.UTC().Format(QueryTimeFormat),
+		"to":      policy.To.UTC().Format(QueryTimeFormat),
+		"metrics": "count_targeted,count_hard_bounce",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range metrics.Results {
+		if m.CountTargeted == 0 {
+			continue
+		}
+		rate := float64(m.CountHardBounce) / float64(m.CountTargeted)
+		if rate > policy.HardBounceRate {
+			report.Recommendations = append(report.Recommendations, HygieneRecommendation{
+				Action: HygieneReview,
+				Target: m.Domain,
+				Reason: fmt.Sprintf("%.1f%% of messages hard bounced", rate*100),
+			})
+		}
+	}
+
+	// individual recipients which hard bounced
+	bounced := map[string]string{}
+	err = c.eachHygieneEvent(policy, map[string]string{
+		"events":         "bounce",
+		"bounce_classes": hardBounceClasses,
+	}, func(ev events.Event) {
+		if b, ok := ev.(*events.Bounce); ok {
+			bounced[b.Recipient] = b.Reason
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// individual recipients which are delivered mail but never open or click any of it
+	delivered := map[string]int{}
+	engaged := map[string]bool{}
+	err = c.eachHygieneEvent(policy, map[string]string{
+		"events": "delivery,open,initial_open,click,amp_open,amp_initial_open,amp_click",
+	}, func(ev events.Event) {
+		switch e := ev.(type) {
+		case *events.Delivery:
+			delivered[e.Recipient]++
+		case *events.Open:
+			engaged[e.Recipient] = true
+		case *events.InitialOpen:
+			engaged[e.Recipient] = true
+		case *events.Click:
+			engaged[e.Recipient] = true
+		case *events.AMPOpen:
+			engaged[e.Recipient] = true
+		case *events.AMPInitialOpen:
+			engaged[e.Recipient] = true
+		case *events.AMPClick:
+			engaged[e.Recipient] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	unengaged := map[string]int{}
+	for r, n := range delivered {
+		if _, ok := bounced[r]; !ok && !engaged[r] && n >= policy.UnengagedDeliveries {
+			unengaged[r] = n
+		}
+	}
+
+	// recipients which are already suppressed don't need recommending
+	if len(bounced) > 0 || len(unengaged) > 0 {
+		err = c.SuppressionEach(nil, func(entry *SuppressionEntry) error {
+			r := entry.address()
+			if entry.NonTransactional || entry.Type == SuppressionNonTransactional {
+				delete(unengaged, r)
+			}
+			if entry.Transactional || entry.Type == SuppressionTransactional {
+				delete(bounced, r)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recipients := make([]string, 0, len(bounced))
+	for r := range bounced {
+		recipients = append(recipients, r)
+	}
+	sort.Strings(recipients)
+	for _, r := range recipients {
+		report.Recommendations = append(report.Recommendations, HygieneRecommendation{
+			Action:        HygieneSuppress,
+			Target:        r,
+			Reason:        fmt.Sprintf("hard bounce: %s", bounced[r]),
+			Transactional: true,
+		})
+	}
+	recipients = make([]string, 0, len(unengaged))
+	for r := range unengaged {
+		recipients = append(recipients, r)
+	}
+	sort.Strings(recipients)
+	for _, r := range recipients {
+		report.Recommendations = append(report.Recommendations, HygieneRecommendation{
+			Action: HygieneSuppress,
+			Target: r,
+			Reason: fmt.Sprintf("%d messages delivered without an open or click", unengaged[r]),
+		})
+	}
+
+	return report, nil
+}
+
+// eachHygieneEvent passes each message event matching params within the policy's window to fn.
+func (c *Client) eachHygieneEvent(policy HygienePolicy, params map[string]string, fn func(events.Event)) error {
+	params["from"] = policy.From.UTC().Format(QueryTimeFormat)
+	params["to"] = policy.To.UTC().Format(QueryTimeFormat)
+	page, err := c.MessageEvents(params)
+	for err == nil {
+		for _, ev := range page.Events {
+			fn(ev)
+		}
+		page, err = page.Next()
+	}
+	if err != ErrEmptyPage {
+		return err
+	}
+	return nil
+}
+
+// ApplyHygiene enacts the recommendations in the report which can be automated,
+// which currently means adding recipients to the suppression list.
+// The entries that were (or, with DryRun, would have been) added are returned.
+func (c *Client) ApplyHygiene(report *HygieneReport) ([]SuppressionEntry, error) {
+	var entries []SuppressionEntry
+	for _, rec := range report.Recommendations {
+		if rec.Action != HygieneSuppress {
+			continue
+		}
+		entries = append(entries, SuppressionEntry{
+			Email:            rec.Target,
+			Transactional:    rec.Transactional,
+			NonTransactional: true,
+			Description:      rec.Reason,
+		})
+	}
+
+	if len(entries) == 0 || report.Policy.DryRun {
+		return entries, nil
+	}
+	return entries, c.SuppressionInsertOrUpdate(entries)
+}
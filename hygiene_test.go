@@ -0,0 +1,87 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestHygieneReport(t *testing.T) {
+	var suppressed string
+	responses := map[string]string{
+		"/api/v1/signals/cohort-engagement/sending-domain": `{"results":[
+			{"sending_domain":"news.example.com","history":[{"dt":"2018-07-01","c_total":100,"c_uneng":50}]},
+			{"sending_domain":"mail.example.com","history":[{"dt":"2018-07-01","c_total":100,"c_uneng":5}]}]}`,
+		"/api/v1/metrics/deliverability/domain": `{"results":[
+			{"domain":"bouncy.com","count_targeted":100,"count_hard_bounce":10},
+			{"domain":"gmail.com","count_targeted":100,"count_hard_bounce":1}]}`,
+		"/api/v1/message-events": `{"results":[
+			{"type":"bounce","rcpt_to":"gone@bouncy.com","reason":"550 no such user","bounce_class":"10"}]}`,
+		"/api/v1/suppression-list": `{"results":[{"recipient":"known@example.com","type":"non_transactional"}]}`,
+	}
+	// five deliveries each, of which only reader@ opened one, and known@ is already suppressed
+	var engagement []string
+	for _, r := range []string{"quiet@example.com", "reader@example.com", "known@example.com"} {
+		for i := 0; i < 5; i++ {
+			engagement = append(engagement, `{"type":"delivery","rcpt_to":"`+r+`"}`)
+		}
+	}
+	engagement = append(engagement, `{"type":"delivery","rcpt_to":"new@example.com"}`,
+		`{"type":"open","rcpt_to":"reader@example.com"}`)
+
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "PUT" {
+			suppressed = r.URL.Path
+			w.Write([]byte(`{"results":{}}`))
+			return
+		}
+		if r.URL.Path == "/api/v1/message-events" && strings.Contains(r.URL.Query().Get("events"), "delivery") {
+			w.Write([]byte(`{"results":[` + strings.Join(engagement, ",") + `]}`))
+			return
+		}
+		w.Write([]byte(responses[r.URL.Path]))
+	})
+	defer done()
+
+	report, err := client.HygieneReport(sp.HygienePolicy{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []sp.HygieneRecommendation{
+		{Action: sp.HygieneSegment, Target: "news.example.com"},
+		{Action: sp.HygieneReview, Target: "bouncy.com"},
+		{Action: sp.HygieneSuppress, Target: "gone@bouncy.com", Transactional: true},
+		{Action: sp.HygieneSuppress, Target: "quiet@example.com"},
+	}
+	if len(report.Recommendations) != len(want) {
+		t.Fatalf("expected %d recommendations, got %+v", len(want), report.Recommendations)
+	}
+	for i, rec := range report.Recommendations {
+		if rec.Action != want[i].Action || rec.Target != want[i].Target || rec.Transactional != want[i].Transactional {
+			t.Errorf("recommendation %d: expected %s %s, got %+v", i, want[i].Action, want[i].Target, rec)
+		}
+	}
+
+	entries, err := client.ApplyHygiene(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || suppressed != "" {
+		t.Fatalf("dry run: expected two entries and no request, got %+v / %q", entries, suppressed)
+	}
+	if !entries[0].Transactional || entries[1].Transactional || !entries[1].NonTransactional {
+		t.Errorf("unexpected suppression types %+v", entries)
+	}
+
+	report.Policy.DryRun = false
+	if _, err = client.ApplyHygiene(report); err != nil {
+		t.Fatal(err)
+	}
+	if suppressed != "/api/v1/suppression-list" {
+		t.Errorf("expected suppression list update, got %q", suppressed)
+	}
+}
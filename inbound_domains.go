@@ -0,0 +1,193 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/inbound-domains/
+var inboundDomainsPathFormat = "/api/v%d/inbound-domains"
+
+// InboundDomain is a domain SparkPost accepts email for, which RelayWebhooks can then match.
+// Its MX records must point at SparkPost.
+type InboundDomain struct {
+	Domain string `json:"domain"`
+}
+
+// InboundDomainCreate registers the provided InboundDomain.
+func (c *Client) InboundDomainCreate(d *InboundDomain) (res *Response, err error) {
+	if d == nil {
+		err = fmt.Errorf("Create called with nil InboundDomain")
+		return
+	} else if d.Domain == "" {
+		err = fmt.Errorf("InboundDomain requires a non-empty Domain")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(inboundDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("InboundDomain", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// InboundDomains returns all InboundDomains.
+func (c *Client) InboundDomains() ([]InboundDomain, *Response, error) {
+	path := fmt.Sprintf(inboundDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		dlist := map[string][]InboundDomain{}
+		if err = json.Unmarshal(body, &dlist); err != nil {
+			return nil, res, err
+		} else if list, ok := dlist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to InboundDomain list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("InboundDomain", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// InboundDomain retrieves the InboundDomain with the specified name.
+func (c *Client) InboundDomain(domain string) (*InboundDomain, *Response, error) {
+	if domain == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank domain")
+	}
+
+	path := fmt.Sprintf(inboundDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, domain)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*InboundDomain{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if d, ok := tmp["results"]; ok && d != nil {
+			return d, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to InboundDomain retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("InboundDomain", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// InboundDomainDelete removes the InboundDomain with the specified name.
+func (c *Client) InboundDomainDelete(domain string) (res *Response, err error) {
+	if domain == "" {
+		err = fmt.Errorf("Delete called with blank domain")
+		return
+	}
+
+	path := fmt.Sprintf(inboundDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, domain)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	// success is a 204, with no body
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("InboundDomain", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
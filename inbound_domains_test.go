@@ -0,0 +1,63 @@
+package gosparkpost_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestInboundDomains(t *testing.T) {
+	var calls []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/inbound-domains":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"results":[{"domain":"inbound.example.com"},{"domain":"replies.example.com"}]}`))
+		case "GET /api/v1/inbound-domains/inbound.example.com":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"results":{"domain":"inbound.example.com"}}`))
+		case "POST /api/v1/inbound-domains":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(409)
+			w.Write([]byte(`{"errors":[{"message":"resource conflict","description":"Inbound domain already exists","code":"1602"}]}`))
+		case "DELETE /api/v1/inbound-domains/inbound.example.com":
+			w.WriteHeader(204)
+		}
+	})
+	defer done()
+
+	if _, err := client.InboundDomainCreate(&sp.InboundDomain{}); err == nil {
+		t.Error("expected an error for a blank domain")
+	}
+	if _, err := client.InboundDomainCreate(&sp.InboundDomain{Domain: "inbound.example.com"}); err == nil {
+		t.Error("expected the conflict to be reported")
+	}
+
+	list, _, err := client.InboundDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].Domain != "replies.example.com" {
+		t.Errorf("unexpected list %v", list)
+	}
+
+	d, _, err := client.InboundDomain("inbound.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Domain != "inbound.example.com" {
+		t.Errorf("unexpected domain %v", d)
+	}
+
+	if _, err = client.InboundDomainDelete("inbound.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls[0] != `POST /api/v1/inbound-domains {"domain":"inbound.example.com"}` {
+		t.Errorf("unexpected create request %s", calls[0])
+	}
+}
@@ -0,0 +1,173 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of InjectionWarning.
+const (
+	// InjectionBraces is a substitution data value containing {{ or }}, which may be
+	// content from a user trying to add tags of their own. See EscapeSubstitution.
+	InjectionBraces = "braces"
+	// InjectionDynamicContent is tags in dynamic_html or dynamic_plain substitution data,
+	// which render_dynamic_content evaluates as part of the template.
+	InjectionDynamicContent = "dynamic_content"
+	// InjectionUnescapedHTML is markup in a value the HTML part includes with {{{ }}},
+	// which skips HTML escaping.
+	InjectionUnescapedHTML = "unescaped_html"
+)
+
+// InjectionWarning describes substitution data which could change what a template does.
+type InjectionWarning struct {
+	Kind string
+	// Path locates the value, for example "recipients[2].substitution_data.comment".
+	Path string
+}
+
+func (w InjectionWarning) String() string {
+	return fmt.Sprintf("%s at %s", w.Kind, w.Path)
+}
+
+// InjectionError is returned by Send when an InjectionPolicy blocks a Transmission.
+type InjectionError struct {
+	Warnings []InjectionWarning
+}
+
+func (e *InjectionError) Error() string {
+	parts := make([]string, len(e.Warnings))
+	for i, w := range e.Warnings {
+		parts[i] = w.String()
+	}
+	return "Possible template injection: " + strings.Join(parts, ", ")
+}
+
+// InjectionPolicy decides what Send does with the warnings from ScanTransmission.
+type InjectionPolicy struct {
+	// Block lists the kinds of warning which stop a Transmission being sent.
+	Block []string
+	// Warn, if set, is passed the warnings of every Transmission which has any, blocked or not.
+	Warn func(t *Transmission, warnings []InjectionWarning)
+}
+
+// check applies the policy to a Transmission, returning an *InjectionError if it's blocked.
+func (p *InjectionPolicy) check(t *Transmission) error {
+	warnings, err := ScanTransmission(t)
+	if err != nil || len(warnings) == 0 {
+		return err
+	}
+	if p.Warn != nil {
+		p.Warn(t, warnings)
+	}
+
+	var blocked []InjectionWarning
+	for _, w := range warnings {
+		for _, kind := range p.Block {
+			if w.Kind == kind {
+				blocked = append(blocked, w)
+				break
+			}
+		}
+	}
+	if len(blocked) > 0 {
+		return &InjectionError{Warnings: blocked}
+	}
+	return nil
+}
+
+var tripleBraceTag = regexp.MustCompile(`\{\{\{\s*([A-Za-z_][A-Za-z0-9_.\[\]]*)\s*\}\}\}`)
+
+// escapedBraces are the macros EscapeSubstitution uses, which are safe.
+var escapedBraces = strings.NewReplacer("{{opening_double_curly()}}", "", "{{closing_double_curly()}}", "")
+
+// ScanTransmission looks through the substitution data of a Transmission and its inline
+// Recipients for values which could inject template language into the message, or markup
+// into its HTML part. Values escaped with EscapeSubstitution don't raise warnings.
+// Stored templates and recipient lists aren't examined.
+func ScanTransmission(t *Transmission) ([]InjectionWarning, error) {
+	if t == nil {
+		return nil, fmt.Errorf("Can't scan a nil Transmission")
+	}
+
+	unescaped := map[string]bool{}
+	var html string
+	switch c := t.Content.(type) {
+	case Content:
+		html = c.HTML
+	case RawContent:
+		html = c.HTML
+	}
+	for _, m := range tripleBraceTag.FindAllStringSubmatch(html, -1) {
+		unescaped[m[1]] = true
+	}
+
+	var warnings []InjectionWarning
+	scan := func(prefix string, data interface{}) error {
+		if data == nil {
+			return nil
+		}
+		jsonBytes, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		var v interface{}
+		if err = json.Unmarshal(jsonBytes, &v); err != nil {
+			return err
+		}
+		scanValue(prefix, "", v, unescaped, &warnings)
+		return nil
+	}
+
+	if err := scan("substitution_data", t.SubstitutionData); err != nil {
+		return nil, err
+	}
+	recips, err := inlineRecipients(t.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range recips {
+		if err = scan(fmt.Sprintf("recipients[%d].substitution_data", i), r.SubstitutionData); err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
+}
+
+// scanValue checks a decoded JSON value, found at key within the substitution data at prefix.
+func scanValue(prefix, key string, v interface{}, unescaped map[string]bool, warnings *[]InjectionWarning) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub := k
+			if key != "" {
+				sub = key + "." + k
+			}
+			scanValue(prefix, sub, val[k], unescaped, warnings)
+		}
+	case []interface{}:
+		for i, elem := range val {
+			scanValue(prefix, fmt.Sprintf("%s[%d]", key, i), elem, unescaped, warnings)
+		}
+	case string:
+		path := prefix + "." + key
+		s := escapedBraces.Replace(val)
+		if strings.Contains(s, "{{") || strings.Contains(s, "}}") {
+			kind := InjectionBraces
+			if strings.HasPrefix(key, "dynamic_html.") || strings.HasPrefix(key, "dynamic_plain.") {
+				kind = InjectionDynamicContent
+			}
+			*warnings = append(*warnings, InjectionWarning{Kind: kind, Path: path})
+		}
+		if unescaped[key] && strings.ContainsAny(val, "<>") {
+			*warnings = append(*warnings, InjectionWarning{Kind: InjectionUnescapedHTML, Path: path})
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestScanTransmission(t *testing.T) {
+	tx := &sp.Transmission{
+		Recipients: []sp.Recipient{{
+			Address: "a@example.com",
+			SubstitutionData: map[string]interface{}{
+				"dynamic_html": map[string]string{"promo": "<p>{{ secret }}</p>"},
+				"safe":         sp.EscapeSubstitution("{{ x }}"),
+			},
+		}},
+		Content: sp.Content{
+			From:    "test@example.com",
+			Subject: "Hi",
+			HTML:    "{{{ bio }}} {{ comment }} {{{ user.sig }}}",
+		},
+		SubstitutionData: map[string]interface{}{
+			"bio":     "<script>alert(1)</script>",
+			"comment": "<b>fine, escaped</b> {{ other }}",
+			"user":    map[string]string{"sig": "plain"},
+		},
+	}
+	warnings, err := sp.ScanTransmission(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []sp.InjectionWarning{
+		{Kind: sp.InjectionUnescapedHTML, Path: "substitution_data.bio"},
+		{Kind: sp.InjectionBraces, Path: "substitution_data.comment"},
+		{Kind: sp.InjectionDynamicContent, Path: "recipients[0].substitution_data.dynamic_html.promo"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected %v, got %v", expected, warnings)
+	}
+}
+
+func TestInjectionPolicy(t *testing.T) {
+	sent := 0
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+
+	var warned []sp.InjectionWarning
+	client.Injection = &sp.InjectionPolicy{
+		Block: []string{sp.InjectionDynamicContent},
+		Warn:  func(_ *sp.Transmission, w []sp.InjectionWarning) { warned = append(warned, w...) },
+	}
+	tx := func(data map[string]interface{}) *sp.Transmission {
+		return &sp.Transmission{
+			Recipients:       []string{"a@example.com"},
+			Content:          sp.Content{From: "test@example.com", Subject: "Hi", Text: "{{ name }}"},
+			SubstitutionData: data,
+		}
+	}
+
+	if _, _, err := client.Send(tx(map[string]interface{}{"name": "{{ x }}"})); err != nil {
+		t.Fatalf("warning shouldn't block: %s", err)
+	}
+	if sent != 1 || len(warned) != 1 {
+		t.Fatalf("expected 1 send and 1 warning, got %d and %d", sent, len(warned))
+	}
+
+	_, _, err := client.Send(tx(map[string]interface{}{
+		"dynamic_plain": map[string]string{"a": "{{ x }}"},
+	}))
+	if _, ok := err.(*sp.InjectionError); !ok {
+		t.Fatalf("expected *InjectionError, got %v", err)
+	}
+	if sent != 1 {
+		t.Error("blocked transmission was sent")
+	}
+}
+
+func TestInjectionPolicyPrepared(t *testing.T) {
+	sent := 0
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+	client.Injection = &sp.InjectionPolicy{Block: []string{sp.InjectionDynamicContent}}
+
+	p, err := client.Prepare(&sp.Transmission{
+		Content: sp.Content{From: "test@example.com", Subject: "Hi", Text: "{{ name }}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = p.Send([]sp.Recipient{{Address: "a@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = p.Send([]sp.Recipient{{
+		Address:          "b@example.com",
+		SubstitutionData: map[string]interface{}{"dynamic_plain": map[string]string{"a": "{{ x }}"}},
+	}})
+	if _, ok := err.(*sp.InjectionError); !ok {
+		t.Fatalf("expected *InjectionError, got %v", err)
+	}
+	if sent != 1 {
+		t.Error("blocked prepared transmission was sent")
+	}
+}
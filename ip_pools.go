@@ -0,0 +1,262 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/ip-pools/
+var ipPoolsPathFormat = "/api/v%d/ip-pools"
+
+// IPPool is a group of dedicated sending IPs. Transmissions select one with Options.IPPool.
+type IPPool struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// SigningDomain, if set, is used to DKIM sign mail sent from this pool's IPs.
+	SigningDomain    string `json:"signing_domain,omitempty"`
+	FBLSigningDomain string `json:"fbl_signing_domain,omitempty"`
+	// AutoWarmupOverflowPool is the pool used for mail which IPs in this pool can't
+	// send while they're warming up.
+	AutoWarmupOverflowPool string `json:"auto_warmup_overflow_pool,omitempty"`
+	// IPs is only set on retrieval; use SendingIPMove to change which pool an IP is in.
+	IPs []SendingIP `json:"ips,omitempty"`
+}
+
+func (p *IPPool) String() string {
+	return fmt.Sprintf("%s %q (%d IPs)", p.ID, p.Name, len(p.IPs))
+}
+
+// IPPoolCreate accepts a populated IPPool and creates it, returning its ID.
+func (c *Client) IPPoolCreate(p *IPPool) (id string, res *Response, err error) {
+	if p == nil {
+		err = fmt.Errorf("Create called with nil IPPool")
+		return
+	} else if p.Name == "" {
+		err = fmt.Errorf("IPPool requires a non-empty Name")
+		return
+	}
+
+	tmp := *p
+	tmp.IPs = nil
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(ipPoolsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var ok bool
+		id, ok = res.Results["id"].(string)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to IPPool creation")
+		}
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("IPPool", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// IPPools returns all IPPools, including the IPs in each.
+func (c *Client) IPPools() ([]IPPool, *Response, error) {
+	path := fmt.Sprintf(ipPoolsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		plist := map[string][]IPPool{}
+		if err = json.Unmarshal(body, &plist); err != nil {
+			return nil, res, err
+		} else if list, ok := plist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to IPPool list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("IPPool", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// IPPool retrieves the IPPool with the specified id.
+func (c *Client) IPPool(id string) (*IPPool, *Response, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank id")
+	}
+
+	path := fmt.Sprintf(ipPoolsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*IPPool{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if p, ok := tmp["results"]; ok && p != nil {
+			return p, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to IPPool retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("IPPool", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// IPPoolUpdate replaces the settings of the IPPool with the ID of the one provided.
+// Its IPs are ignored.
+func (c *Client) IPPoolUpdate(p *IPPool) (res *Response, err error) {
+	if p == nil {
+		err = fmt.Errorf("Update called with nil IPPool")
+		return
+	} else if p.ID == "" {
+		err = fmt.Errorf("Update called with blank id")
+		return
+	}
+
+	tmp := *p
+	tmp.ID = ""
+	tmp.IPs = nil
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(ipPoolsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, p.ID)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("IPPool", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// IPPoolDelete removes the IPPool with the specified id.
+// Any IPs in the pool are moved to the default pool.
+func (c *Client) IPPoolDelete(id string) (res *Response, err error) {
+	if id == "" {
+		err = fmt.Errorf("Delete called with blank id")
+		return
+	}
+
+	path := fmt.Sprintf(ipPoolsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("IPPool", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
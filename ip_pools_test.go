@@ -0,0 +1,97 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestIPPools(t *testing.T) {
+	var calls []string
+	bodies := map[string]map[string]interface{}{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		calls = append(calls, call)
+		if r.Method == "POST" || r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+			var m map[string]interface{}
+			json.Unmarshal(body, &m)
+			bodies[call] = m
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(204)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "POST /api/v1/ip-pools":
+			w.Write([]byte(`{"results":{"id":"marketing"}}`))
+		case "GET /api/v1/ip-pools":
+			w.Write([]byte(`{"results":[{"id":"default","name":"Default","ips":[{"external_ip":"54.244.54.135","hostname":"mta1.example.com","auto_warmup_enabled":true,"auto_warmup_stage":5}]},{"id":"marketing","name":"Marketing","ips":[]}]}`))
+		case "GET /api/v1/ip-pools/marketing":
+			w.Write([]byte(`{"results":{"id":"marketing","name":"Marketing","signing_domain":"example.com","ips":[{"external_ip":"54.244.54.136","hostname":"mta2.example.com"}]}}`))
+		case "GET /api/v1/ip-pools/missing":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, _, err := client.IPPoolCreate(&sp.IPPool{}); err == nil {
+		t.Error("expected an error without a name")
+	}
+
+	id, _, err := client.IPPoolCreate(&sp.IPPool{Name: "Marketing", SigningDomain: "example.com", IPs: []sp.SendingIP{{ExternalIP: "1.2.3.4"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "marketing" {
+		t.Errorf("unexpected id %q", id)
+	}
+	created := bodies["POST /api/v1/ip-pools"]
+	if created["name"] != "Marketing" || created["signing_domain"] != "example.com" || created["ips"] != nil {
+		t.Errorf("unexpected request body %v", created)
+	}
+
+	pools, _, err := client.IPPools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 2 || len(pools[0].IPs) != 1 || pools[0].IPs[0].Hostname != "mta1.example.com" ||
+		!pools[0].IPs[0].AutoWarmupEnabled || pools[0].IPs[0].AutoWarmupStage != 5 {
+		t.Errorf("unexpected pools %+v", pools)
+	}
+
+	pool, _, err := client.IPPool("marketing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.Name != "Marketing" || pool.SigningDomain != "example.com" || pool.IPs[0].ExternalIP != "54.244.54.136" {
+		t.Errorf("unexpected pool %+v", pool)
+	}
+
+	if _, _, err = client.IPPool("missing"); err == nil {
+		t.Error("expected an error for a missing pool")
+	}
+
+	pool.FBLSigningDomain = "fbl.example.com"
+	if _, err = client.IPPoolUpdate(pool); err != nil {
+		t.Fatal(err)
+	}
+	updated := bodies["PUT /api/v1/ip-pools/marketing"]
+	if updated["fbl_signing_domain"] != "fbl.example.com" || updated["id"] != nil || updated["ips"] != nil {
+		t.Errorf("unexpected update body %v", updated)
+	}
+
+	if _, err = client.IPPoolDelete("marketing"); err != nil {
+		t.Fatal(err)
+	}
+	if last := calls[len(calls)-1]; last != "DELETE /api/v1/ip-pools/marketing" {
+		t.Errorf("unexpected call %s", last)
+	}
+}
@@ -0,0 +1,116 @@
+package gosparkpost
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Component is implemented by helpers in this package which do work in the background.
+// Start returns once the work is running; it keeps going until Stop is called or ctx is done.
+// Stop asks the work to finish what it's doing and waits for it, giving up when ctx is done.
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Health() Health
+}
+
+// Health is a point-in-time report on a Component.
+type Health struct {
+	Running     bool
+	StartedAt   time.Time
+	LastError   error
+	LastErrorAt time.Time
+}
+
+// runner implements the bookkeeping common to all Components.
+// The zero value is ready to use.
+type runner struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	health Health
+}
+
+// start runs fn in a new goroutine with a context that's cancelled by stop.
+func (r *runner) start(ctx context.Context, fn func(ctx context.Context)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.health.Running {
+		return fmt.Errorf("already running")
+	}
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	ctx = r.ctx
+	r.done = make(chan struct{})
+	r.health = Health{Running: true, StartedAt: time.Now()}
+
+	go func(done chan struct{}) {
+		defer close(done)
+		fn(ctx)
+		r.mu.Lock()
+		r.health.Running = false
+		r.mu.Unlock()
+	}(r.done)
+	return nil
+}
+
+// stop cancels the running fn and waits for it to return, or for ctx to be done.
+func (r *runner) stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for shutdown: %s", ctx.Err())
+	}
+}
+
+// drain waits for the running fn to return by itself, cancelling it if ctx is done first.
+func (r *runner) drain(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		return fmt.Errorf("timed out waiting for shutdown: %s", ctx.Err())
+	}
+}
+
+// workContext returns the context passed to the running fn, for work it hands out later.
+// It's nil if start hasn't been called.
+func (r *runner) workContext() context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ctx
+}
+
+// fail records an error for reporting via Health.
+func (r *runner) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health.LastError = err
+	r.health.LastErrorAt = time.Now()
+}
+
+func (r *runner) report() Health {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.health
+}
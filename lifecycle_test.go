@@ -0,0 +1,99 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestComponents(t *testing.T) {
+	for _, c := range []sp.Component{
+		&sp.Scheduler{Tick: time.Hour},
+		&sp.SMTPPool{},
+		&sp.SLAMonitor{},
+	} {
+		name := fmt.Sprintf("%T", c)
+		if c.Health().Running {
+			t.Errorf("%s: expected not to be running before Start", name)
+		}
+		if err := c.Stop(context.Background()); err != nil {
+			t.Errorf("%s: unexpected error stopping before Start: %s", name, err)
+		}
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := c.Start(context.Background()); err == nil {
+			t.Errorf("%s: expected an error starting twice", name)
+		}
+		if h := c.Health(); !h.Running || h.StartedAt.IsZero() {
+			t.Errorf("%s: unexpected health %+v", name, h)
+		}
+		if err := c.Stop(context.Background()); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+		if c.Health().Running {
+			t.Errorf("%s: expected not to be running after Stop", name)
+		}
+	}
+}
+
+func TestComponentCancel(t *testing.T) {
+	s := &sp.SLAMonitor{}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	// Stop waits for the work to notice ctx is done
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s.Health().Running {
+		t.Error("expected not to be running once ctx is done")
+	}
+	// and it may be started again
+	if err := s.Start(context.Background()); err != nil {
+		t.Error(err)
+	}
+	s.Stop(context.Background())
+}
+
+func TestSchedulerStop(t *testing.T) {
+	fetching, release := make(chan struct{}, 1), make(chan struct{})
+	s := &sp.Scheduler{
+		Tick: time.Millisecond,
+		Campaigns: []*sp.RecurringCampaign{{
+			Name:     "digest",
+			Schedule: sp.Every(time.Millisecond),
+			Recipients: func(ctx context.Context, at time.Time) (interface{}, error) {
+				select {
+				case fetching <- struct{}{}:
+				default:
+				}
+				<-release
+				return nil, fmt.Errorf("no recipients")
+			},
+		}},
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-fetching
+
+	// Stop waits for the run in progress, giving up when its ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); err == nil {
+		t.Error("expected Stop to time out")
+	}
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if h := s.Health(); h.Running || h.LastError == nil || h.LastError.Error() != "no recipients" {
+		t.Errorf("unexpected health %+v", h)
+	}
+}
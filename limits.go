@@ -0,0 +1,51 @@
+package gosparkpost
+
+import "fmt"
+
+// Size limits documented by SparkPost. Payloads over these limits are
+// rejected by the API with a 413 or 422, so they're enforced client-side too.
+// https://developers.sparkpost.com/api/transmissions/#header-request-body
+const (
+	// MaxTemplateContentBytes limits the combined size of a Template's html, text and subject.
+	MaxTemplateContentBytes = 15 * 1024 * 1024
+	// MaxSnippetContentBytes limits the combined size of a Snippet's html and text.
+	MaxSnippetContentBytes = 100 * 1024
+	// MaxAttachmentBytes limits the total (base64-encoded) size of all attachments and inline images.
+	MaxAttachmentBytes = 20 * 1024 * 1024
+	// MaxTransmissionBytes limits the size of the JSON body POSTed to the Transmissions API.
+	MaxTransmissionBytes = 20 * 1024 * 1024
+)
+
+// SizeError is returned when a payload exceeds one of the documented size limits.
+type SizeError struct {
+	What  string
+	Size  int
+	Limit int
+}
+
+func (e SizeError) Error() string {
+	return fmt.Sprintf("%s is %d bytes, which exceeds the limit of %d bytes", e.What, e.Size, e.Limit)
+}
+
+func checkSize(what string, size, limit int) error {
+	if size > limit {
+		return SizeError{What: what, Size: size, Limit: limit}
+	}
+	return nil
+}
+
+// contentSize returns the number of bytes of html, text and subject in Content.
+func (c Content) contentSize() int {
+	return len(c.HTML) + len(c.Text) + len(c.Subject)
+}
+
+// attachmentSize returns the total encoded size of attachments and inline images in Content.
+func (c Content) attachmentSize() (n int) {
+	for _, att := range c.Attachments {
+		n += len(att.B64Data)
+	}
+	for _, img := range c.InlineImages {
+		n += len(img.B64Data)
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package gosparkpost_test
+
+import (
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTemplateSizeLimits(t *testing.T) {
+	tmpl := &sp.Template{
+		Content: sp.Content{
+			Subject: "size limits",
+			From:    "test@example.com",
+			Text:    "ok",
+		},
+	}
+	if err := tmpl.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tmpl.Content.Attachments = []sp.Attachment{{
+		MIMEType: "application/octet-stream",
+		Filename: "big.bin",
+		B64Data:  strings.Repeat("A", sp.MaxAttachmentBytes+1),
+	}}
+	err := tmpl.Validate()
+	serr, ok := err.(sp.SizeError)
+	if !ok {
+		t.Fatalf("expected SizeError, got %T: %v", err, err)
+	}
+	if serr.Size != sp.MaxAttachmentBytes+1 || serr.Limit != sp.MaxAttachmentBytes {
+		t.Fatalf("unexpected sizes in error: %v", serr)
+	}
+}
@@ -0,0 +1,104 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LocalizedContent holds per-locale variants of Content.
+// Each Recipient gets the variant matching a locale stored in its Metadata.
+type LocalizedContent struct {
+	// Variants maps a locale such as "en" or "fr-CA" to its Content.
+	Variants map[string]Content
+	// Default names the variant used for Recipients without a usable locale.
+	Default string
+	// MetadataKey is the Recipient.Metadata field holding the locale; "locale" if empty.
+	MetadataKey string
+}
+
+// Locale returns the variant name that should be sent to the provided Recipient.
+// An exact match is preferred, followed by the base language ("fr" for "fr-CA"), then Default.
+func (lc *LocalizedContent) Locale(r Recipient) string {
+	key := lc.MetadataKey
+	if key == "" {
+		key = "locale"
+	}
+
+	var locale string
+	switch meta := r.Metadata.(type) {
+	case map[string]string:
+		locale = meta[key]
+	case map[string]interface{}:
+		locale, _ = meta[key].(string)
+	}
+
+	if _, ok := lc.Variants[locale]; ok && locale != "" {
+		return locale
+	}
+	if idx := strings.IndexAny(locale, "-_"); idx > 0 {
+		if _, ok := lc.Variants[locale[:idx]]; ok {
+			return locale[:idx]
+		}
+	}
+	return lc.Default
+}
+
+// Expand splits the provided Transmission into one Transmission per locale,
+// each with the matching Content and the subset of Recipients it applies to.
+// The Transmission's own Content is ignored. Locales without Recipients are skipped.
+func (lc *LocalizedContent) Expand(t *Transmission) ([]*Transmission, error) {
+	if t == nil {
+		return nil, fmt.Errorf("Expand called with nil Transmission")
+	}
+	if _, ok := lc.Variants[lc.Default]; !ok {
+		return nil, fmt.Errorf("LocalizedContent has no variant for default locale [%s]", lc.Default)
+	}
+
+	recips, err := ParseRecipients(t.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	var list []Recipient
+	if recips != nil {
+		list = *recips
+	} else if rl, ok := t.Recipients.([]Recipient); ok {
+		list = rl
+	} else if il, ok := t.Recipients.([]interface{}); ok {
+		for _, r := range il {
+			list = append(list, r.(Recipient))
+		}
+	} else {
+		return nil, fmt.Errorf("LocalizedContent requires inline Recipients, not a stored list")
+	}
+
+	byLocale := map[string][]Recipient{}
+	for _, r := range list {
+		locale := lc.Locale(r)
+		byLocale[locale] = append(byLocale[locale], r)
+	}
+
+	locales := make([]string, 0, len(byLocale))
+	for locale := range byLocale {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	txs := make([]*Transmission, 0, len(locales))
+	for _, locale := range locales {
+		tx := *t
+		tx.Content = lc.Variants[locale]
+		tx.Recipients = byLocale[locale]
+		txs = append(txs, &tx)
+	}
+	return txs, nil
+}
+
+// SendLocalized expands the Transmission using the provided LocalizedContent and sends each result.
+func (c *Client) SendLocalized(t *Transmission, lc *LocalizedContent) (ids []string, err error) {
+	txs, err := lc.Expand(t)
+	if err != nil {
+		return
+	}
+	return c.SendAll(txs)
+}
@@ -0,0 +1,46 @@
+package gosparkpost_test
+
+import (
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestLocalizedContentExpand(t *testing.T) {
+	lc := &sp.LocalizedContent{
+		Default: "en",
+		Variants: map[string]sp.Content{
+			"en": {Subject: "Hello", Text: "hello", From: "test@example.com"},
+			"fr": {Subject: "Bonjour", Text: "bonjour", From: "test@example.com"},
+		},
+	}
+	tx := &sp.Transmission{
+		CampaignID: "localized",
+		Recipients: []sp.Recipient{
+			{Address: "a@example.com", Metadata: map[string]string{"locale": "fr-CA"}},
+			{Address: "b@example.com", Metadata: map[string]interface{}{"locale": "de"}},
+			{Address: "c@example.com"},
+			{Address: "d@example.com", Metadata: map[string]string{"locale": "fr"}},
+		},
+	}
+
+	txs, err := lc.Expand(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transmissions, got %d", len(txs))
+	}
+
+	expected := map[string]int{"Hello": 2, "Bonjour": 2}
+	for _, tx := range txs {
+		content := tx.Content.(sp.Content)
+		recips := tx.Recipients.([]sp.Recipient)
+		if expected[content.Subject] != len(recips) {
+			t.Errorf("%s: expected %d recipients, got %d", content.Subject, expected[content.Subject], len(recips))
+		}
+		if tx.CampaignID != "localized" {
+			t.Errorf("expected campaign id to be preserved, got %q", tx.CampaignID)
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/url"
-	"strings"
 
 	"github.com/SparkPost/gosparkpost/events"
 )
@@ -26,25 +24,21 @@ type EventsPage struct {
 	prevPage   string
 	firstPage  string
 	lastPage   string
+
+	// set for pages read with MessageEventsAdaptive
+	sizer  *PageSizer
+	params map[string]string
+	offset int
 }
 
 // https://developers.sparkpost.com/api/#/reference/message-events/events-samples/search-for-message-events
+//
+// Deprecated: the Message Events API is being retired; use SearchEvents instead.
 func (c *Client) MessageEvents(params map[string]string) (*EventsPage, error) {
-	url, err := url.Parse(fmt.Sprintf(messageEventsPathFormat, c.Config.BaseUrl, c.Config.ApiVersion))
-	if err != nil {
-		return nil, err
-	}
-
-	if len(params) > 0 {
-		q := url.Query()
-		for k, v := range params {
-			q.Add(k, v)
-		}
-		url.RawQuery = q.Encode()
-	}
+	url := QueryBuilder{}.Params(params).URL(fmt.Sprintf(messageEventsPathFormat, c.Config.BaseUrl, c.Config.ApiVersion))
 
 	// Send off our request
-	res, err := c.HttpGet(url.String())
+	res, err := c.HttpGet(url)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +69,9 @@ func (events *EventsPage) Next() (*EventsPage, error) {
 	if events.nextPage == "" {
 		return nil, ErrEmptyPage
 	}
+	if events.sizer != nil {
+		return events.client.adaptiveEventsPage(events.params, events.sizer, events.offset+len(events.Events))
+	}
 
 	// Send off our request
 	res, err := events.client.HttpGet(events.client.Config.BaseUrl + events.nextPage)
@@ -147,10 +144,7 @@ func (ep *EventsPage) UnmarshalJSON(data []byte) error {
 
 // Samples requests a list of example event data.
 func (c *Client) EventSamples(types *[]string) (*events.Events, error) {
-	url, err := url.Parse(fmt.Sprintf(messageEventsSamplesPathFormat, c.Config.BaseUrl, c.Config.ApiVersion))
-	if err != nil {
-		return nil, err
-	}
+	q := QueryBuilder{}
 
 	// Filter out types.
 	if types != nil {
@@ -161,15 +155,11 @@ func (c *Client) EventSamples(types *[]string) (*events.Events, error) {
 			}
 		}
 
-		// get the query string object so we can modify it
-		q := url.Query()
-		// add the requested events and re-encode
-		q.Set("events", strings.Join(*types, ","))
-		url.RawQuery = q.Encode()
+		q.List("events", *types...)
 	}
 
 	// Send off our request
-	res, err := c.HttpGet(url.String())
+	res, err := c.HttpGet(q.URL(fmt.Sprintf(messageEventsSamplesPathFormat, c.Config.BaseUrl, c.Config.ApiVersion)))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,111 @@
+package gosparkpost
+
+import (
+	"sort"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// Delivery states of a message, as reported by MessageTimeline.
+const (
+	MessageInjected  = "injected"
+	MessageDelayed   = "delayed"
+	MessageDelivered = "delivered"
+	MessageBounced   = "bounced"
+	MessageRejected  = "rejected"
+)
+
+// EventRetention is how far back SparkPost keeps message events.
+const EventRetention = 10 * 24 * time.Hour
+
+// TimelineEntry is one event in a MessageTimeline.
+type TimelineEntry struct {
+	Time  time.Time
+	Type  string
+	Event events.Event
+}
+
+// MessageTimeline is every event for one message, in the order they happened.
+type MessageTimeline struct {
+	MessageID string
+	Entries   []TimelineEntry
+
+	// State is the latest delivery state, one of the Message* constants, or empty if
+	// no delivery events were found.
+	State string
+	// Opened and Clicked are the first open and click, or the zero time if there were none.
+	Opened  time.Time
+	Clicked time.Time
+}
+
+// timelinePhase orders events with the same timestamp, and maps them to a delivery state.
+var timelinePhase = map[string]struct {
+	order int
+	state string
+}{
+	"injection":            {0, MessageInjected},
+	"policy_rejection":     {1, MessageRejected},
+	"generation_failure":   {1, MessageRejected},
+	"generation_rejection": {1, MessageRejected},
+	"delay":                {2, MessageDelayed},
+	"delivery":             {3, MessageDelivered},
+	"bounce":               {3, MessageBounced},
+	"out_of_band":          {4, MessageBounced},
+	"initial_open":         {5, ""},
+	"open":                 {5, ""},
+	"amp_initial_open":     {5, ""},
+	"amp_open":             {5, ""},
+	"click":                {6, ""},
+	"amp_click":            {6, ""},
+}
+
+// MessageTimeline gathers every event recorded for a message, from injection through
+// delivery or bounce to opens and clicks, sorted chronologically. Events older than
+// EventRetention are no longer available.
+func (c *Client) MessageTimeline(messageID string) (*MessageTimeline, error) {
+	tl := &MessageTimeline{MessageID: messageID}
+	page, err := c.SearchEvents(&EventsParams{
+		Messages: []string{messageID},
+		From:     time.Now().Add(-EventRetention),
+		PerPage:  1000,
+	})
+	for err == nil {
+		for _, e := range page.Events {
+			tl.Entries = append(tl.Entries, TimelineEntry{
+				Time:  events.TimeOf(e),
+				Type:  e.EventType(),
+				Event: e,
+			})
+		}
+		page, err = page.Next()
+	}
+	if err != ErrEmptyPage {
+		return nil, err
+	}
+
+	sort.SliceStable(tl.Entries, func(i, j int) bool {
+		a, b := tl.Entries[i], tl.Entries[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return timelinePhase[a.Type].order < timelinePhase[b.Type].order
+	})
+
+	for _, entry := range tl.Entries {
+		if state := timelinePhase[entry.Type].state; state != "" {
+			tl.State = state
+		}
+		switch timelinePhase[entry.Type].order {
+		case 5:
+			if tl.Opened.IsZero() {
+				tl.Opened = entry.Time
+			}
+		case 6:
+			if tl.Clicked.IsZero() {
+				tl.Clicked = entry.Time
+			}
+		}
+	}
+	return tl, nil
+}
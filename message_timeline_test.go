@@ -0,0 +1,50 @@
+package gosparkpost_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestMessageTimeline(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("messages") != "" && r.URL.Query().Get("messages") != "msg1" {
+			t.Errorf("unexpected messages filter %q", r.URL.Query().Get("messages"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// newest first, as the API returns them, with a delay and delivery in the same second
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"results":[
+				{"type":"click","message_id":"msg1","timestamp":"1454443000"},
+				{"type":"open","message_id":"msg1","timestamp":"1454442900"},
+				{"type":"delivery","message_id":"msg1","timestamp":"1454442700"}
+			],"total_count":5,"links":{"next":"/api/v1/events/message?cursor=next"}}`)
+		} else {
+			fmt.Fprint(w, `{"results":[
+				{"type":"delay","message_id":"msg1","timestamp":"1454442700"},
+				{"type":"injection","message_id":"msg1","timestamp":"1454442600"}
+			],"total_count":5,"links":{}}`)
+		}
+	})
+	defer done()
+
+	tl, err := client.MessageTimeline("msg1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, e := range tl.Entries {
+		types = append(types, e.Type)
+	}
+	if fmt.Sprint(types) != "[injection delay delivery open click]" {
+		t.Errorf("unexpected order %v", types)
+	}
+	if tl.State != sp.MessageDelivered {
+		t.Errorf("expected delivered, got %q", tl.State)
+	}
+	if tl.Opened.Unix() != 1454442900 || tl.Clicked.Unix() != 1454443000 {
+		t.Errorf("unexpected engagement times %s %s", tl.Opened, tl.Clicked)
+	}
+}
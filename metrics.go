@@ -0,0 +1,205 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Groupings for DeliverabilityMetricsBy, each of which returns one row per value.
+const (
+	MetricsByDomain        = "domain"
+	MetricsByCampaign      = "campaign"
+	MetricsByTemplate      = "template"
+	MetricsBySendingIP     = "sending-ip"
+	MetricsByIPPool        = "ip-pool"
+	MetricsBySendingDomain = "sending-domain"
+	MetricsBySubaccount    = "subaccount"
+	MetricsByWatchedDomain = "watched-domain"
+	// MetricsByTime returns one row per MetricsParams.Precision, in DeliverabilityMetricItem.TimeStamp.
+	MetricsByTime = "time-series"
+)
+
+// Precisions for time series metrics.
+const (
+	PrecisionMinute         = "1min"
+	PrecisionFiveMinutes    = "5min"
+	PrecisionFifteenMinutes = "15min"
+	PrecisionHour           = "hour"
+	PrecisionTwelveHours    = "12hr"
+	PrecisionDay            = "day"
+	PrecisionWeek           = "week"
+	PrecisionMonth          = "month"
+)
+
+// DefaultMetrics are requested when MetricsParams.Metrics is empty.
+var DefaultMetrics = []string{
+	"count_targeted", "count_injected", "count_sent", "count_accepted", "count_delivered",
+	"count_bounce", "count_hard_bounce", "count_soft_bounce", "count_block_bounce",
+	"count_rejected", "count_delayed", "count_spam_complaint",
+	"count_unique_confirmed_opened", "count_unique_clicked",
+}
+
+// MetricsParams selects the messages, and the metrics, reported by DeliverabilityMetrics.
+type MetricsParams struct {
+	// From is required. To defaults to now.
+	From time.Time
+	To   time.Time
+
+	Domains        []string
+	Campaigns      []string
+	Templates      []string
+	SendingIPs     []string
+	IPPools        []string
+	SendingDomains []string
+	Subaccounts    []int
+
+	// Metrics are the columns to return, such as "count_sent". Defaults to DefaultMetrics.
+	Metrics []string
+	// Precision is the interval between points of a time series, one of the Precision* constants.
+	Precision string
+	// Timezone names the zone Precision is aligned to, such as "America/New_York". Defaults to UTC.
+	// From and To are sent as local times in this zone.
+	Timezone string
+}
+
+func (p *MetricsParams) query() (QueryBuilder, error) {
+	metrics := p.Metrics
+	if len(metrics) == 0 {
+		metrics = DefaultMetrics
+	}
+	q, err := p.filters()
+	if err != nil {
+		return nil, err
+	}
+	return q.List("metrics", metrics...), nil
+}
+
+// filters returns the query without the metrics list, which the reason endpoints don't take.
+func (p *MetricsParams) filters() (QueryBuilder, error) {
+	q := QueryBuilder{}
+	if p.Timezone == "" {
+		q = q.Time("from", p.From, QueryTimeFormat).
+			Time("to", p.To, QueryTimeFormat)
+	} else {
+		// the API reads from and to in the timezone, which has to be written out as its local time
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("Unknown MetricsParams.Timezone [%s]: %s", p.Timezone, err)
+		}
+		if !p.From.IsZero() {
+			q = q.Set("from", p.From.In(loc).Format(QueryTimeFormat))
+		}
+		if !p.To.IsZero() {
+			q = q.Set("to", p.To.In(loc).Format(QueryTimeFormat))
+		}
+	}
+	return q.List("domains", p.Domains...).
+		List("campaigns", p.Campaigns...).
+		List("templates", p.Templates...).
+		List("sending_ips", p.SendingIPs...).
+		List("ip_pools", p.IPPools...).
+		List("sending_domains", p.SendingDomains...).
+		Ints("subaccounts", p.Subaccounts...).
+		Set("precision", p.Precision).
+		Set("timezone", p.Timezone), nil
+}
+
+// DeliverabilityMetrics returns the metrics for all messages matching params, as a single row.
+// https://developers.sparkpost.com/api/metrics/#metrics-get-deliverability-metrics-summary
+func (c *Client) DeliverabilityMetrics(params *MetricsParams) (*DeliverabilityMetricItem, *Response, error) {
+	rows, res, err := c.DeliverabilityMetricsBy("", params)
+	if err != nil {
+		return nil, res, err
+	}
+	if len(rows) == 0 {
+		return &DeliverabilityMetricItem{}, res, nil
+	}
+	return rows[0], res, nil
+}
+
+// DeliverabilityMetricsBy returns the metrics for messages matching params, with one row
+// for each value of groupBy, which is one of the MetricsBy* constants.
+func (c *Client) DeliverabilityMetricsBy(groupBy string, params *MetricsParams) ([]*DeliverabilityMetricItem, *Response, error) {
+	if params == nil || params.From.IsZero() {
+		return nil, nil, fmt.Errorf("DeliverabilityMetrics requires MetricsParams.From")
+	}
+	if groupBy == MetricsByTime && params.Precision == "" {
+		return nil, nil, fmt.Errorf("Time series metrics require MetricsParams.Precision")
+	}
+
+	path := fmt.Sprintf(deliverabilityMetricPathFormat, c.Config.ApiVersion)
+	if groupBy != "" {
+		path = fmt.Sprintf("%s/%s", path, groupBy)
+	}
+	q, err := params.query()
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := c.HttpGet(q.URL(c.Config.BaseUrl + path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		var wrapper DeliverabilityMetricEventsWrapper
+		if err = json.Unmarshal(body, &wrapper); err != nil {
+			return nil, res, err
+		}
+		return wrapper.Results, res, nil
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Metrics", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// MetricPoint is the metrics for one interval of a time series.
+type MetricPoint struct {
+	Time time.Time
+	DeliverabilityMetricItem
+}
+
+// DeliverabilityTimeSeries returns the metrics for messages matching params at each interval
+// of params.Precision (which defaults to PrecisionDay) between From and To, oldest first.
+// https://developers.sparkpost.com/api/metrics/#metrics-get-time-series-metrics
+func (c *Client) DeliverabilityTimeSeries(params *MetricsParams) ([]MetricPoint, *Response, error) {
+	if params != nil && params.Precision == "" {
+		tmp := *params
+		tmp.Precision = PrecisionDay
+		params = &tmp
+	}
+	rows, res, err := c.DeliverabilityMetricsBy(MetricsByTime, params)
+	if err != nil {
+		return nil, res, err
+	}
+
+	points := make([]MetricPoint, len(rows))
+	for i, row := range rows {
+		points[i].DeliverabilityMetricItem = *row
+		points[i].Time, err = time.Parse(time.RFC3339, row.TimeStamp)
+		if err != nil {
+			return nil, res, fmt.Errorf("Unexpected time series timestamp [%s]", row.TimeStamp)
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, res, nil
+}
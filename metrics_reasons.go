@@ -0,0 +1,148 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BounceReason counts bounces with the same reason, as returned by BounceReasons.
+type BounceReason struct {
+	Reason string `json:"reason"`
+	// Domain is only set by BounceReasonsByDomain.
+	Domain string `json:"domain,omitempty"`
+
+	BounceClassName        string `json:"bounce_class_name"`
+	BounceClassDescription string `json:"bounce_class_description"`
+	BounceCategoryID       int    `json:"bounce_category_id"`
+	BounceCategoryName     string `json:"bounce_category_name"`
+	ClassificationID       int    `json:"classification_id"`
+
+	CountBounce          int `json:"count_bounce"`
+	CountInbandBounce    int `json:"count_inband_bounce"`
+	CountOutofbandBounce int `json:"count_outofband_bounce"`
+	CountAdminBounce     int `json:"count_admin_bounce"`
+}
+
+// BounceClassification counts bounces by classification, as returned by BounceClassifications.
+// https://www.sparkpost.com/docs/deliverability/bounce-classification-codes/
+type BounceClassification struct {
+	BounceClassName        string `json:"bounce_class_name"`
+	BounceClassDescription string `json:"bounce_class_description"`
+	BounceCategoryID       int    `json:"bounce_category_id"`
+	BounceCategoryName     string `json:"bounce_category_name"`
+	ClassificationID       int    `json:"classification_id"`
+
+	CountBounce          int `json:"count_bounce"`
+	CountInbandBounce    int `json:"count_inband_bounce"`
+	CountOutofbandBounce int `json:"count_outofband_bounce"`
+	CountAdminBounce     int `json:"count_admin_bounce"`
+}
+
+// DelayReason counts delays with the same reason, as returned by DelayReasons.
+type DelayReason struct {
+	Reason string `json:"reason"`
+	// Domain is only set by DelayReasonsByDomain.
+	Domain string `json:"domain,omitempty"`
+
+	CountDelayed      int `json:"count_delayed"`
+	CountDelayedFirst int `json:"count_delayed_first"`
+}
+
+// RejectionReason counts rejections with the same reason, as returned by RejectionReasons.
+type RejectionReason struct {
+	Reason string `json:"reason"`
+	// Domain is only set by RejectionReasonsByDomain.
+	Domain string `json:"domain,omitempty"`
+
+	RejectionCategoryID int    `json:"rejection_category_id"`
+	RejectionType       string `json:"rejection_type"`
+	CountRejected       int    `json:"count_rejected"`
+}
+
+// BounceReasons returns bounce counts by reason, for messages matching params.
+// https://developers.sparkpost.com/api/metrics/#metrics-get-bounce-reason-metrics
+func (c *Client) BounceReasons(params *MetricsParams) (rows []BounceReason, res *Response, err error) {
+	res, err = c.reasonMetrics("bounce-reason", params, &rows)
+	return
+}
+
+// BounceReasonsByDomain is like BounceReasons, with a row for each reason at each recipient domain.
+func (c *Client) BounceReasonsByDomain(params *MetricsParams) (rows []BounceReason, res *Response, err error) {
+	res, err = c.reasonMetrics("bounce-reason/domain", params, &rows)
+	return
+}
+
+// BounceClassifications returns bounce counts by classification, for messages matching params.
+func (c *Client) BounceClassifications(params *MetricsParams) (rows []BounceClassification, res *Response, err error) {
+	res, err = c.reasonMetrics("bounce-classification", params, &rows)
+	return
+}
+
+// DelayReasons returns delay counts by reason, for messages matching params.
+func (c *Client) DelayReasons(params *MetricsParams) (rows []DelayReason, res *Response, err error) {
+	res, err = c.reasonMetrics("delay-reason", params, &rows)
+	return
+}
+
+// DelayReasonsByDomain is like DelayReasons, with a row for each reason at each recipient domain.
+func (c *Client) DelayReasonsByDomain(params *MetricsParams) (rows []DelayReason, res *Response, err error) {
+	res, err = c.reasonMetrics("delay-reason/domain", params, &rows)
+	return
+}
+
+// RejectionReasons returns rejection counts by reason, for messages matching params.
+func (c *Client) RejectionReasons(params *MetricsParams) (rows []RejectionReason, res *Response, err error) {
+	res, err = c.reasonMetrics("rejection-reason", params, &rows)
+	return
+}
+
+// RejectionReasonsByDomain is like RejectionReasons, with a row for each reason at each recipient domain.
+func (c *Client) RejectionReasonsByDomain(params *MetricsParams) (rows []RejectionReason, res *Response, err error) {
+	res, err = c.reasonMetrics("rejection-reason/domain", params, &rows)
+	return
+}
+
+// reasonMetrics fetches one of the reason breakdowns, decoding its results into rows.
+func (c *Client) reasonMetrics(endpoint string, params *MetricsParams, rows interface{}) (*Response, error) {
+	if params == nil || params.From.IsZero() {
+		return nil, fmt.Errorf("Reason metrics require MetricsParams.From")
+	}
+
+	path := fmt.Sprintf(deliverabilityMetricPathFormat, c.Config.ApiVersion)
+	q, err := params.filters()
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.HttpGet(q.URL(fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return res, err
+		}
+		wrapper := struct {
+			Results interface{} `json:"results"`
+		}{rows}
+		return res, json.Unmarshal(body, &wrapper)
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Metrics", "retrieve")
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
@@ -0,0 +1,186 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestDeliverabilityMetrics(t *testing.T) {
+	var uris []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		uris = append(uris, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/metrics/deliverability":
+			w.Write([]byte(`{"results":[{"count_sent":100,"count_accepted":95,"count_bounce":5}]}`))
+		case "/api/v1/metrics/deliverability/domain":
+			w.Write([]byte(`{"results":[{"domain":"gmail.com","count_sent":60},{"domain":"yahoo.com","count_sent":40}]}`))
+		default:
+			w.WriteHeader(400)
+			w.Write([]byte(`{"errors":[{"message":"invalid params","description":"precision must be one of ...","code":"1200"}]}`))
+		}
+	})
+	defer done()
+
+	from := time.Date(2017, 6, 1, 8, 0, 0, 0, time.UTC)
+	if _, _, err := client.DeliverabilityMetrics(&sp.MetricsParams{}); err == nil {
+		t.Error("expected an error without From")
+	}
+
+	summary, _, err := client.DeliverabilityMetrics(&sp.MetricsParams{
+		From:      from,
+		Campaigns: []string{"spring", "summer"},
+		Metrics:   []string{"count_sent", "count_accepted", "count_bounce"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.CountSent != 100 || summary.CountAccepted != 95 || summary.CountBounce != 5 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if uris[0] != "/api/v1/metrics/deliverability?campaigns=spring%2Csummer&from=2017-06-01T08%3A00&metrics=count_sent%2Ccount_accepted%2Ccount_bounce" {
+		t.Errorf("unexpected request %s", uris[0])
+	}
+
+	rows, _, err := client.DeliverabilityMetricsBy(sp.MetricsByDomain, &sp.MetricsParams{From: from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1].Domain != "yahoo.com" || rows[1].CountSent != 40 {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+
+	if _, _, err = client.DeliverabilityMetricsBy(sp.MetricsByTime, &sp.MetricsParams{From: from}); err == nil {
+		t.Error("expected an error without Precision")
+	}
+	if _, _, err = client.DeliverabilityMetricsBy(sp.MetricsByTime, &sp.MetricsParams{From: from, Precision: "fortnight"}); err == nil {
+		t.Error("expected the API error to be returned")
+	}
+}
+
+func TestDeliverabilityMetricsTimezone(t *testing.T) {
+	var query string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[]}`))
+	})
+	defer done()
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no zoneinfo: %v", err)
+	}
+	// midnight in New York, given in UTC, is sent as New York's local time
+	_, _, err = client.DeliverabilityMetrics(&sp.MetricsParams{
+		From:     time.Date(2017, 6, 1, 0, 0, 0, 0, ny).UTC(),
+		Metrics:  []string{"count_sent"},
+		Timezone: "America/New_York",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "from=2017-06-01T00%3A00&metrics=count_sent&timezone=America%2FNew_York" {
+		t.Errorf("unexpected query string %q", query)
+	}
+
+	if _, _, err = client.DeliverabilityMetrics(&sp.MetricsParams{From: time.Now(), Timezone: "Mars/Olympus"}); err == nil {
+		t.Error("expected an unknown timezone to be rejected")
+	}
+}
+
+func TestDeliverabilityTimeSeries(t *testing.T) {
+	var precision string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/metrics/deliverability/time-series" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		precision = r.URL.Query().Get("precision")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[
+			{"ts":"2017-06-02T00:00:00+00:00","count_sent":20},
+			{"ts":"2017-06-01T00:00:00.000Z","count_sent":10}
+		]}`))
+	})
+	defer done()
+
+	points, _, err := client.DeliverabilityTimeSeries(&sp.MetricsParams{
+		From: time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2017, 6, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if precision != sp.PrecisionDay {
+		t.Errorf("expected day precision by default, got %q", precision)
+	}
+	if len(points) != 2 || !points[0].Time.Equal(time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)) ||
+		points[0].CountSent != 10 || points[1].CountSent != 20 {
+		t.Errorf("unexpected points %+v", points)
+	}
+}
+
+func TestReasonMetrics(t *testing.T) {
+	var uris []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		uris = append(uris, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/metrics/deliverability/bounce-reason/domain":
+			w.Write([]byte(`{"results":[{"reason":"550 5.1.1 User unknown","domain":"gmail.com","bounce_class_name":"Invalid Recipient",
+				"bounce_category_id":1,"bounce_category_name":"Hard","classification_id":10,"count_bounce":4,"count_inband_bounce":4}]}`))
+		case "/api/v1/metrics/deliverability/bounce-classification":
+			w.Write([]byte(`{"results":[{"bounce_class_name":"Mailbox Full","classification_id":22,"count_bounce":2}]}`))
+		case "/api/v1/metrics/deliverability/delay-reason":
+			w.Write([]byte(`{"results":[{"reason":"421 Try again later","count_delayed":7,"count_delayed_first":3}]}`))
+		case "/api/v1/metrics/deliverability/rejection-reason/domain":
+			w.Write([]byte(`{"results":[{"reason":"550 Policy","domain":"yahoo.com","rejection_category_id":1,"rejection_type":"Policy Rejection","count_rejected":9}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer done()
+
+	params := &sp.MetricsParams{From: time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC), Domains: []string{"gmail.com"}}
+	bounces, _, err := client.BounceReasonsByDomain(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bounces) != 1 || bounces[0].Domain != "gmail.com" || bounces[0].ClassificationID != 10 || bounces[0].CountBounce != 4 {
+		t.Errorf("unexpected bounce reasons %+v", bounces)
+	}
+	if uris[0] != "/api/v1/metrics/deliverability/bounce-reason/domain?domains=gmail.com&from=2017-06-01T00%3A00" {
+		t.Errorf("unexpected request %s", uris[0])
+	}
+
+	classes, _, err := client.BounceClassifications(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 1 || classes[0].BounceClassName != "Mailbox Full" {
+		t.Errorf("unexpected classifications %+v", classes)
+	}
+
+	delays, _, err := client.DelayReasons(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 1 || delays[0].CountDelayed != 7 || delays[0].CountDelayedFirst != 3 {
+		t.Errorf("unexpected delay reasons %+v", delays)
+	}
+
+	rejections, _, err := client.RejectionReasonsByDomain(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rejections) != 1 || rejections[0].RejectionType != "Policy Rejection" || rejections[0].CountRejected != 9 {
+		t.Errorf("unexpected rejection reasons %+v", rejections)
+	}
+
+	if _, _, err = client.RejectionReasons(nil); err == nil {
+		t.Error("expected an error without params")
+	}
+}
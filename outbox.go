@@ -0,0 +1,240 @@
+package gosparkpost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrOutboxFull is returned by Outbox.Enqueue when the Message's lane is full.
+var ErrOutboxFull = errors.New("outbox lane full")
+
+// Priority is the class of a Message queued in an Outbox.
+type Priority int
+
+const (
+	// PriorityTransactional is for mail a person is waiting on, like password resets.
+	PriorityTransactional Priority = iota
+	// PriorityTriggered is for mail sent in response to activity, like abandoned cart reminders.
+	PriorityTriggered
+	// PriorityBulk is for marketing sends.
+	PriorityBulk
+
+	numPriorities = 3
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityTransactional:
+		return "transactional"
+	case PriorityTriggered:
+		return "triggered"
+	case PriorityBulk:
+		return "bulk"
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+var defaultOutboxWeights = map[Priority]int{
+	PriorityTriggered: 4,
+	PriorityBulk:      1,
+}
+
+// Outbox queues Messages and sends them in the background, with a lane for each Priority.
+// Transactional mail is always sent first, so a big marketing blast delays a password reset
+// by at most the sends already in progress, rather than sitting in front of it. Otherwise
+// workers take from the lanes by weight, so triggered mail isn't stuck behind bulk mail either.
+type Outbox struct {
+	Sender MessageSender
+	// Workers is how many Messages are sent at once. Defaults to 1.
+	Workers int
+	// QueueSize is how many Messages may be waiting in each lane. Defaults to 1000.
+	QueueSize int
+	// Weights are the relative shares of sends the triggered and bulk lanes get while both
+	// have mail waiting. They default to 4 for triggered and 1 for bulk.
+	Weights map[Priority]int
+	// OnError is passed errors returned by Sender.
+	OnError func(m *Message, p Priority, err error)
+
+	runner
+	mu      sync.Mutex
+	cond    *sync.Cond
+	lanes   [numPriorities][]*Message
+	credit  [numPriorities]int
+	started bool
+	closed  bool
+}
+
+var _ Component = &Outbox{}
+
+// Enqueue queues m to be sent. It fails if p's lane is full, or the Outbox is closed.
+func (o *Outbox) Enqueue(p Priority, m *Message) error {
+	if p < 0 || p >= numPriorities {
+		return fmt.Errorf("Unknown priority %d", int(p))
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return fmt.Errorf("Outbox is closed")
+	}
+
+	size := o.QueueSize
+	if size <= 0 {
+		size = 1000
+	}
+	if len(o.lanes[p]) >= size {
+		return ErrOutboxFull
+	}
+	o.lanes[p] = append(o.lanes[p], m)
+
+	if !o.started {
+		if err := o.startLocked(context.Background()); err != nil {
+			return err
+		}
+	}
+	o.cond.Signal()
+	return nil
+}
+
+// Start starts the workers, which send with ctx: cancelling it abandons the sends in progress
+// and any Messages still queued. If Start isn't called, the first Enqueue starts the workers
+// with context.Background().
+func (o *Outbox) Start(ctx context.Context) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return fmt.Errorf("Outbox is closed")
+	} else if o.started {
+		return fmt.Errorf("already running")
+	}
+	return o.startLocked(ctx)
+}
+
+// startLocked starts the workers. It must be called with mu held.
+func (o *Outbox) startLocked(ctx context.Context) error {
+	o.started = true
+	if o.cond == nil {
+		o.cond = sync.NewCond(&o.mu)
+	}
+	workers := o.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	return o.start(ctx, func(ctx context.Context) {
+		// wake idle workers when ctx is done, so they can return
+		wake := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				o.mu.Lock()
+				o.cond.Broadcast()
+				o.mu.Unlock()
+			case <-wake:
+			}
+		}()
+
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				o.work(ctx)
+			}()
+		}
+		wg.Wait()
+		close(wake)
+	})
+}
+
+// Pending returns how many Messages are waiting in p's lane.
+func (o *Outbox) Pending(p Priority) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if p < 0 || p >= numPriorities {
+		return 0
+	}
+	return len(o.lanes[p])
+}
+
+func (o *Outbox) work(ctx context.Context) {
+	for {
+		o.mu.Lock()
+		p, ok := o.next()
+		for !ok && !o.closed && ctx.Err() == nil {
+			o.cond.Wait()
+			p, ok = o.next()
+		}
+		if !ok || ctx.Err() != nil {
+			o.mu.Unlock()
+			return
+		}
+		m := o.lanes[p][0]
+		o.lanes[p][0] = nil
+		o.lanes[p] = o.lanes[p][1:]
+		o.mu.Unlock()
+
+		if err := o.Sender.SendMessage(ctx, m); err != nil {
+			o.fail(err)
+			if o.OnError != nil {
+				o.OnError(m, p, err)
+			}
+		}
+	}
+}
+
+// next picks the lane to send from: transactional if it has Messages waiting, or else using
+// smooth weighted round robin over the other lanes with Messages waiting, so each lane's
+// sends are spread out rather than bunched together. It must be called with mu held.
+func (o *Outbox) next() (Priority, bool) {
+	if len(o.lanes[PriorityTransactional]) > 0 {
+		return PriorityTransactional, true
+	}
+	best, total := Priority(-1), 0
+	for p := PriorityTriggered; p < numPriorities; p++ {
+		if len(o.lanes[p]) == 0 {
+			o.credit[p] = 0
+			continue
+		}
+		w := o.Weights[p]
+		if w <= 0 {
+			w = defaultOutboxWeights[p]
+		}
+		o.credit[p] += w
+		total += w
+		if best < 0 || o.credit[p] > o.credit[best] {
+			best = p
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	o.credit[best] -= total
+	return best, true
+}
+
+// Close stops accepting Messages and waits for those already queued to be sent. When ctx
+// is done it gives up, cancelling the sends in progress.
+func (o *Outbox) Close(ctx context.Context) error {
+	o.mu.Lock()
+	o.closed = true
+	if o.cond != nil {
+		o.cond.Broadcast()
+	}
+	o.mu.Unlock()
+
+	if err := o.drain(ctx); err != nil {
+		return fmt.Errorf("timed out waiting for the outbox to drain: %s", ctx.Err())
+	}
+	return nil
+}
+
+// Stop is Close.
+func (o *Outbox) Stop(ctx context.Context) error {
+	return o.Close(ctx)
+}
+
+// Health reports whether the workers are running, and the last error returned by Sender.
+func (o *Outbox) Health() Health {
+	return o.report()
+}
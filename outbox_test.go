@@ -0,0 +1,138 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+type recordingSender struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *recordingSender) SendMessage(ctx context.Context, m *sp.Message) error {
+	<-s.gate
+	s.mu.Lock()
+	s.sent = append(s.sent, m.CampaignID)
+	s.mu.Unlock()
+	return nil
+}
+
+func TestOutboxPriority(t *testing.T) {
+	sender := &recordingSender{gate: make(chan struct{})}
+	o := &sp.Outbox{Sender: sender, QueueSize: 50}
+
+	// the first bulk message holds up the only worker while the rest are queued
+	if err := o.Enqueue(sp.PriorityBulk, &sp.Message{CampaignID: "bulk"}); err != nil {
+		t.Fatal(err)
+	}
+	for o.Pending(sp.PriorityBulk) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 29; i++ {
+		if err := o.Enqueue(sp.PriorityBulk, &sp.Message{CampaignID: "bulk"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := o.Enqueue(sp.PriorityTriggered, &sp.Message{CampaignID: "cart"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := o.Enqueue(sp.PriorityTransactional, &sp.Message{CampaignID: "reset"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(sender.gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := o.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 51 {
+		t.Fatalf("expected 51 sends, got %d", len(sender.sent))
+	}
+	// all the transactional mail goes next, however much there is
+	for i, id := range sender.sent[1:21] {
+		if id != "reset" {
+			t.Fatalf("expected transactional mail to go next, got %s at %d", id, i+1)
+		}
+	}
+	if sender.sent[21] != "cart" {
+		t.Errorf("expected triggered mail to go before bulk, got %v", sender.sent[21:24])
+	}
+
+	if err := o.Enqueue(sp.PriorityBulk, &sp.Message{}); err == nil {
+		t.Error("expected an error enqueueing on a closed Outbox")
+	}
+}
+
+// ctxSender fails with the error of the context it's passed, once it's done.
+type ctxSender struct {
+	sending chan struct{}
+}
+
+func (s *ctxSender) SendMessage(ctx context.Context, m *sp.Message) error {
+	s.sending <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestOutboxComponent(t *testing.T) {
+	sender := &ctxSender{sending: make(chan struct{}, 1)}
+	var errs []error
+	o := &sp.Outbox{Sender: sender, OnError: func(m *sp.Message, p sp.Priority, err error) {
+		errs = append(errs, err)
+	}}
+	var _ sp.Component = o
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := o.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Start(ctx); err == nil {
+		t.Error("expected an error starting twice")
+	}
+	if !o.Health().Running {
+		t.Error("expected the Outbox to be running")
+	}
+	if err := o.Enqueue(sp.PriorityBulk, &sp.Message{}); err != nil {
+		t.Fatal(err)
+	}
+	<-sender.sending
+
+	// sends are made with Start's context
+	cancel()
+	if err := o.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if h := o.Health(); h.Running || h.LastError != context.Canceled || len(errs) != 1 {
+		t.Errorf("unexpected health %+v, errors %v", h, errs)
+	}
+}
+
+func TestOutboxFull(t *testing.T) {
+	sender := &recordingSender{gate: make(chan struct{})}
+	o := &sp.Outbox{Sender: sender, QueueSize: 2}
+	defer func() {
+		close(sender.gate)
+		o.Close(context.Background())
+	}()
+
+	var err error
+	for i := 0; i < 4 && err == nil; i++ {
+		err = o.Enqueue(sp.PriorityBulk, &sp.Message{})
+	}
+	if err != sp.ErrOutboxFull {
+		t.Errorf("expected ErrOutboxFull, got %v", err)
+	}
+	// a full bulk lane doesn't stop transactional mail being queued
+	if err = o.Enqueue(sp.PriorityTransactional, &sp.Message{}); err != nil {
+		t.Error(err)
+	}
+}
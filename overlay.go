@@ -0,0 +1,114 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EnvironmentProduction is the Environment in which Transmissions are sent as defined.
+const EnvironmentProduction = "production"
+
+// Overlay is a set of overrides applied to every Transmission sent in an environment,
+// so one Transmission definition can be used everywhere without staging mailing customers.
+type Overlay struct {
+	// Sink, if set, redirects every Recipient to this sink domain, see SinkRecipients.
+	Sink string
+	// Sandbox, if set, sends using the SparkPost sandbox domain.
+	Sandbox bool
+	// Sample, if non-zero, sends to at most this many Recipients, the first ones listed.
+	Sample int
+}
+
+// DefaultOverlays are used when an Environment is set without Overlays: production sends
+// as normal, staging sends to the sink, and development sends a few messages to the sink.
+func DefaultOverlays() map[string]*Overlay {
+	return map[string]*Overlay{
+		EnvironmentProduction: {},
+		"staging":             {Sink: SinkDomain},
+		"development":         {Sink: SinkDomain, Sample: 10},
+	}
+}
+
+// overlay returns the Overlay for the configured Environment, or nil if it isn't set. An
+// Environment other than production with no Overlay is an error, so a typo in a config file
+// can't turn off the overrides.
+func (cfg *Config) overlay() (*Overlay, error) {
+	if cfg.Environment == "" {
+		return nil, nil
+	}
+	overlays := cfg.Overlays
+	if overlays == nil {
+		overlays = DefaultOverlays()
+	}
+	o, ok := overlays[cfg.Environment]
+	if !ok && cfg.Environment != EnvironmentProduction {
+		return nil, fmt.Errorf("No overlay for environment [%s]", cfg.Environment)
+	}
+	return o, nil
+}
+
+// sinkDomain returns Config.Sink, or failing that, the Sink of the environment's Overlay.
+func (c *Client) sinkDomain() (string, error) {
+	if c.Config.Sink != "" {
+		return c.Config.Sink, nil
+	}
+	o, err := c.Config.overlay()
+	if err != nil || o == nil {
+		return "", err
+	}
+	return o.Sink, nil
+}
+
+// Apply returns a copy of t with the overrides applied. Send applies the Overlay for the
+// configured Environment itself.
+func (o *Overlay) Apply(t *Transmission) (*Transmission, error) {
+	tx, err := o.applyOptions(t)
+	if err != nil || o.Sink == "" {
+		return tx, err
+	}
+	if tx.Recipients, err = SinkRecipients(tx.Recipients, o.Sink); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// applyOptions applies everything but Sink, which Send applies after any archive copies
+// have been added, so they're redirected too.
+func (o *Overlay) applyOptions(t *Transmission) (*Transmission, error) {
+	tx := o.sandbox(t)
+	if o.Sample > 0 {
+		var err error
+		if tx.Recipients, err = o.sample(t.Recipients); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// sandbox returns a copy of t, using the sandbox domain if the Overlay says to.
+func (o *Overlay) sandbox(t *Transmission) *Transmission {
+	tx := *t
+	if o.Sandbox {
+		opts := TxOptions{}
+		if t.Options != nil {
+			opts = *t.Options
+		}
+		opts.Sandbox = "true"
+		tx.Options = &opts
+	}
+	return &tx
+}
+
+// sample returns at most Sample of the provided inline Recipients.
+func (o *Overlay) sample(recips interface{}) ([]Recipient, error) {
+	list, err := inlineRecipients(recips)
+	if err != nil {
+		return nil, err
+	} else if list == nil {
+		return nil, fmt.Errorf("Can't sample [%s] Recipients, only inline Recipients", reflect.TypeOf(recips))
+	}
+	if len(list) > o.Sample {
+		list = list[:o.Sample]
+	}
+	return list, nil
+}
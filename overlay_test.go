@@ -0,0 +1,81 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestOverlayApply(t *testing.T) {
+	tx := &sp.Transmission{
+		Recipients: []string{"a@example.com", "b@example.com", "c@example.com"},
+		Options:    &sp.TxOptions{InlineCSS: true},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+	}
+	got, err := (&sp.Overlay{Sink: sp.SinkDomain, Sandbox: true, Sample: 2}).Apply(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recips := got.Recipients.([]sp.Recipient)
+	if len(recips) != 2 || recips[1].Address.(sp.Address).Email != "b@example.com."+sp.SinkDomain {
+		t.Errorf("unexpected recipients %v", recips)
+	}
+	if got.Options.Sandbox != "true" || !got.Options.InlineCSS || tx.Options.Sandbox != "" {
+		t.Errorf("unexpected options %+v, original %+v", got.Options, tx.Options)
+	}
+	if len(tx.Recipients.([]string)) != 3 {
+		t.Error("Apply modified the original Transmission")
+	}
+
+	stored := &sp.Transmission{Recipients: map[string]string{"list_id": "all"}}
+	if _, err = (&sp.Overlay{Sample: 5}).Apply(stored); err == nil {
+		t.Error("expected sampling a stored list to fail")
+	}
+}
+
+func TestClientEnvironment(t *testing.T) {
+	if err := (&sp.Client{}).Init(&sp.Config{ApiKey: "testkey", Environment: "stagign"}); err == nil {
+		t.Error("expected an unknown environment to be rejected")
+	}
+
+	var body struct {
+		Options    map[string]interface{} `json:"options"`
+		Recipients []struct {
+			Address sp.Address `json:"address"`
+		} `json:"recipients"`
+	}
+	for _, env := range []string{"staging", "development", sp.EnvironmentProduction} {
+		client, done := newTestClient(t, &sp.Config{ApiKey: "testkey", Environment: env}, func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(b, &body)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"results":{"id":"1"}}`))
+		})
+		var recips []string
+		for i := 0; i < 12; i++ {
+			recips = append(recips, "user@example.com")
+		}
+		_, _, err := client.Send(&sp.Transmission{
+			Recipients: recips,
+			Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+		})
+		done()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, email := 12, "user@example.com."+sp.SinkDomain
+		switch env {
+		case "development":
+			want = 10
+		case sp.EnvironmentProduction:
+			email = "user@example.com"
+		}
+		if len(body.Recipients) != want || body.Recipients[0].Address.Email != email {
+			t.Errorf("%s: sent to %d recipients, first %s", env, len(body.Recipients), body.Recipients[0].Address.Email)
+		}
+	}
+}
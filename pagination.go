@@ -0,0 +1,163 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// MaxEventsPerPage is the largest page the Message Events API will return.
+const MaxEventsPerPage = 10000
+
+// PageSizer adjusts the page size of paginated reads to how the API is coping:
+// the size is halved after a timeout, a 413 or a slow response, and doubled after
+// a fast one, within Min and Max. A 413 also stops the size growing back to the
+// size that failed. Page sizes are always Min times a power of two,
+// so the number of results already read is a multiple of every smaller size.
+// A PageSizer may be shared between concurrent reads of the same endpoint.
+type PageSizer struct {
+	// Min and Max cap the page size. They default to 100 and MaxEventsPerPage.
+	Min int
+	Max int
+	// Fast and Slow are the response times below which the page size grows,
+	// and above which it shrinks. They default to 2 and 15 seconds.
+	Fast time.Duration
+	Slow time.Duration
+
+	mu      sync.Mutex
+	size    int
+	ceiling int
+}
+
+func (p *PageSizer) init() {
+	if p.Min <= 0 {
+		p.Min = 100
+	}
+	if p.Max < p.Min {
+		p.Max = MaxEventsPerPage
+		if p.Max < p.Min {
+			p.Max = p.Min
+		}
+	}
+	if p.Fast <= 0 {
+		p.Fast = 2 * time.Second
+	}
+	if p.Slow <= 0 {
+		p.Slow = 15 * time.Second
+	}
+	if p.size == 0 {
+		// start as large as possible
+		p.size = p.Min
+		for p.size*2 <= p.Max {
+			p.size *= 2
+		}
+	}
+}
+
+// Size returns the page size the next request will use.
+func (p *PageSizer) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+	return p.size
+}
+
+// shrink sets the page size to half of the size which failed, returning false if
+// that's below Min. If hard is set, the page size won't grow past the new size again.
+func (p *PageSizer) shrink(failed int, hard bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+	if failed/2 < p.Min {
+		return false
+	}
+	p.size = failed / 2
+	if hard {
+		p.ceiling = p.size
+	}
+	return true
+}
+
+// observe adjusts the page size after a successful request.
+func (p *PageSizer) observe(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+	if d > p.Slow && p.size/2 >= p.Min {
+		p.size /= 2
+	} else if d < p.Fast && p.size*2 <= p.Max && (p.ceiling == 0 || p.size*2 <= p.ceiling) {
+		p.size *= 2
+	}
+}
+
+// sizeFor returns the largest size not above the current one which evenly divides offset.
+func (p *PageSizer) sizeFor(offset int) int {
+	size := p.Size()
+	for size > p.Min && offset%size != 0 {
+		size /= 2
+	}
+	return size
+}
+
+// MessageEventsAdaptive is like MessageEvents, with the page size chosen (and adapted
+// as further pages are requested with Next) by sizer. Any "page" or "per_page" params are ignored.
+func (c *Client) MessageEventsAdaptive(params map[string]string, sizer *PageSizer) (*EventsPage, error) {
+	copied := make(map[string]string, len(params))
+	for k, v := range params {
+		if k != "page" && k != "per_page" {
+			copied[k] = v
+		}
+	}
+	return c.adaptiveEventsPage(copied, sizer, 0)
+}
+
+// adaptiveEventsPage fetches the page of events starting offset results in.
+func (c *Client) adaptiveEventsPage(params map[string]string, sizer *PageSizer, offset int) (*EventsPage, error) {
+	base := fmt.Sprintf(messageEventsPathFormat, c.Config.BaseUrl, c.Config.ApiVersion)
+	for {
+		size := sizer.sizeFor(offset)
+		u := QueryBuilder{}.Params(params).
+			Int("per_page", size).
+			Int("page", offset/size+1).
+			URL(base)
+
+		start := time.Now()
+		res, err := c.HttpGet(u)
+		if err == nil && (res.HTTP.StatusCode == 413 || res.HTTP.StatusCode == 504) {
+			res.ReadBody()
+			err = fmt.Errorf("%d: page of %d events failed", res.HTTP.StatusCode, size)
+			if sizer.shrink(size, res.HTTP.StatusCode == 413) {
+				continue
+			}
+			return nil, err
+		}
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() && sizer.shrink(size, false) {
+				continue
+			}
+			return nil, err
+		}
+		elapsed := time.Since(start)
+
+		if err = res.AssertJson(); err != nil {
+			return nil, err
+		}
+		bodyBytes, err := res.ReadBody()
+		if err != nil {
+			return nil, err
+		}
+		var page EventsPage
+		if err = json.Unmarshal(bodyBytes, &page); err != nil {
+			return nil, err
+		}
+		sizer.observe(elapsed)
+
+		page.client = c
+		page.sizer = sizer
+		page.params = params
+		page.offset = offset
+		return &page, nil
+	}
+}
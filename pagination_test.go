@@ -0,0 +1,60 @@
+package gosparkpost_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+func TestMessageEventsAdaptive(t *testing.T) {
+	const total = 700
+	var sizes []int
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		sizes = append(sizes, perPage)
+		if perPage > 200 {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		var evs []string
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			evs = append(evs, fmt.Sprintf(`{"type":"delivery","rcpt_to":"%d@example.com"}`, i))
+		}
+		links := ""
+		if page*perPage < total {
+			links = `,"links":[{"rel":"next","href":"/api/v1/message-events?page=next"}]`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"results":[%s],"total_count":%d%s}`, strings.Join(evs, ","), total, links)
+	})
+	defer done()
+
+	sizer := &sp.PageSizer{Min: 50, Max: 800}
+	page, err := client.MessageEventsAdaptive(map[string]string{"events": "delivery", "per_page": "5"}, sizer)
+	n := 0
+	for err == nil {
+		for _, ev := range page.Events {
+			if d, ok := ev.(*events.Delivery); !ok || d.Recipient != fmt.Sprintf("%d@example.com", n) {
+				t.Fatalf("event %d: unexpected %v", n, ev)
+			}
+			n++
+		}
+		page, err = page.Next()
+	}
+	if err != sp.ErrEmptyPage {
+		t.Fatal(err)
+	}
+	if n != total {
+		t.Errorf("expected %d events, got %d", total, n)
+	}
+	if fmt.Sprint(sizes) != "[800 400 200 200 200 200]" {
+		t.Errorf("expected to start at 800 and stay at 200 after 413s, got %v", sizes)
+	}
+}
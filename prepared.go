@@ -0,0 +1,101 @@
+package gosparkpost
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// PreparedTransmission is a Transmission which has been validated and encoded once,
+// so it can be sent to many batches of Recipients without repeating that work.
+// Changes to the original Transmission after Prepare has returned have no effect.
+// A PreparedTransmission may be sent from multiple goroutines at once.
+type PreparedTransmission struct {
+	client *Client
+	// base is a copy of the Transmission, without Recipients
+	base *Transmission
+	// fields holds the encoded fields of the Transmission, other than Recipients, without the leading "{"
+	fields []byte
+}
+
+// Prepare validates and encodes everything but the Recipients of the provided Transmission.
+func (c *Client) Prepare(t *Transmission) (*PreparedTransmission, error) {
+	if t == nil {
+		return nil, fmt.Errorf("Prepare called with nil Transmission")
+	}
+
+	base := t.Clone()
+	// Validate requires Recipients; they're supplied to each Send instead
+	base.Recipients = []Recipient{}
+	if err := base.Validate(); err != nil {
+		return nil, err
+	}
+	base.Recipients = nil
+	overlay, err := c.Config.overlay()
+	if err != nil {
+		return nil, err
+	} else if overlay != nil {
+		base = overlay.sandbox(base)
+	}
+	if c.Config.EscapeSubstitutionData {
+		if base, err = escapeTransmission(base); err != nil {
+			return nil, err
+		}
+	}
+
+	jsonBytes, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(jsonBytes, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "recipients")
+	if jsonBytes, err = json.Marshal(fields); err != nil {
+		return nil, err
+	}
+
+	return &PreparedTransmission{client: c, base: base, fields: jsonBytes[1:]}, nil
+}
+
+// Send sends the prepared Transmission to the provided Recipients.
+// Only the Recipients are encoded; the rest of the request is reused.
+func (p *PreparedTransmission) Send(recips []Recipient) (id string, res *Response, err error) {
+	if len(recips) == 0 {
+		err = fmt.Errorf("Transmission requires Recipients")
+		return
+	}
+	if _, err = ParseRecipients(recips); err != nil {
+		return
+	}
+
+	c := p.client
+	tx := *p.base
+	tx.Recipients = recips
+	o, err := c.sendPipeline(&tx, false)
+	if err != nil {
+		return
+	}
+	// the base was escaped and sandboxed by Prepare, so only the Recipients are taken
+	recips = o.t.Recipients.([]Recipient)
+
+	recipBytes, err := json.Marshal(recips)
+	if err != nil {
+		err = o.finish("", err)
+		return
+	}
+
+	var body bytes.Buffer
+	body.Grow(len(`{"recipients":,`) + len(recipBytes) + len(p.fields))
+	body.WriteString(`{"recipients":`)
+	body.Write(recipBytes)
+	if len(p.fields) > 1 {
+		body.WriteByte(',')
+	}
+	body.Write(p.fields)
+
+	id, res, err = c.postTransmission(body.Bytes(), p.base.numRcptErrors(), len(recips))
+	err = o.finish(id, err)
+	return
+}
@@ -0,0 +1,76 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestPreparedTransmission(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var tx struct {
+			CampaignID string `json:"campaign_id"`
+			Recipients []struct {
+				Address sp.Address `json:"address"`
+			} `json:"recipients"`
+			Content sp.Content `json:"content"`
+		}
+		if err := json.Unmarshal(body, &tx); err != nil {
+			t.Errorf("invalid request body: %s\n%s", err, body)
+		}
+		mu.Lock()
+		for _, r := range tx.Recipients {
+			got = append(got, fmt.Sprintf("%s %s %s", r.Address.Email, tx.CampaignID, tx.Content.Subject))
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+
+	tx := &sp.Transmission{
+		CampaignID: "prepared",
+		Content:    sp.Content{Subject: "Hi", Text: "Hi", From: "test@example.com"},
+	}
+	prepared, err := client.Prepare(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// changes after Prepare are ignored
+	tx.CampaignID = "changed"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recips := []sp.Recipient{{Address: sp.Address{Email: fmt.Sprintf("%d@example.com", i)}}}
+			if _, _, err := prepared.Send(recips); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Strings(got)
+	expected := "[0@example.com prepared Hi 1@example.com prepared Hi 2@example.com prepared Hi 3@example.com prepared Hi]"
+	if fmt.Sprint(got) != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	if _, _, err = prepared.Send(nil); err == nil {
+		t.Error("expected an error sending to no Recipients")
+	}
+	if _, err = client.Prepare(&sp.Transmission{Content: sp.Content{}}); err == nil {
+		t.Error("expected an error preparing invalid Content")
+	}
+}
@@ -0,0 +1,84 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Proofs sent by SendProof are marked with these.
+const (
+	// ProofMetadataKey is set to true in the metadata of a proof.
+	ProofMetadataKey = "proof"
+	// ProofCampaignSuffix is appended to the campaign id of a proof, so it's reported separately.
+	ProofCampaignSuffix = "-proof"
+	// ProofTag is added to the tags of each proof Recipient.
+	ProofTag = "proof"
+)
+
+// SendProof sends a copy of t to proofRecipients instead of its own Recipients: the standard
+// "send me a test" before a campaign goes out. The proof is sent straight away, even if t is
+// scheduled. If t has inline Recipients, the first one's substitution data and metadata are
+// used for every proof, so it renders as real recipients will see it. t isn't modified.
+// Proofs aren't subject to the Client's FrequencyCap, DuplicateGuard or Environment Overlay.
+func (c *Client) SendProof(t *Transmission, proofRecipients []string) (id string, res *Response, err error) {
+	if t == nil {
+		err = fmt.Errorf("SendProof called with nil Transmission")
+		return
+	} else if len(proofRecipients) == 0 {
+		err = fmt.Errorf("SendProof requires proof Recipients")
+		return
+	}
+
+	proof := t.Clone()
+	var sample Recipient
+	if recips, _ := inlineRecipients(t.Recipients); len(recips) > 0 {
+		sample = recips[0].Clone()
+	}
+	recips := make([]Recipient, len(proofRecipients))
+	for i, addr := range proofRecipients {
+		r := sample.Clone()
+		r.Address = addr
+		r.ReturnPath = ""
+		r.Tags = append(r.Tags, ProofTag)
+		recips[i] = r
+	}
+	proof.Recipients = recips
+
+	if proof.Metadata, err = withMetadata(proof.Metadata, ProofMetadataKey, true); err != nil {
+		return
+	}
+	if proof.CampaignID != "" {
+		if n := MaxCampaignIDLength - len(ProofCampaignSuffix); len(proof.CampaignID) > n {
+			// cut on a rune boundary, so the id stays valid UTF-8
+			for n > 0 && !utf8.RuneStart(proof.CampaignID[n]) {
+				n--
+			}
+			proof.CampaignID = proof.CampaignID[:n]
+		}
+		proof.CampaignID += ProofCampaignSuffix
+	}
+	proof.ScheduleAt(time.Time{})
+
+	return c.sendTransmission(proof, true)
+}
+
+// withMetadata returns metadata, which must encode as a JSON object, with key set to value.
+func withMetadata(metadata interface{}, key string, value interface{}) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if metadata != nil {
+		jsonBytes, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(jsonBytes, &m); err != nil {
+			return nil, fmt.Errorf("Metadata must be a JSON object: %s", err)
+		}
+		if m == nil {
+			m = map[string]interface{}{}
+		}
+	}
+	m[key] = value
+	return m, nil
+}
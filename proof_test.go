@@ -0,0 +1,124 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSendProof(t *testing.T) {
+	var body map[string]interface{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+
+	tx := &sp.Transmission{
+		CampaignID: "spring",
+		Metadata:   map[string]string{"team": "growth"},
+		Recipients: []sp.Recipient{
+			{Address: "customer@example.com", SubstitutionData: map[string]interface{}{"name": "Alex"}, Tags: []string{"vip"}},
+			{Address: "other@example.com"},
+		},
+		Content: sp.Content{From: "test@example.com", Subject: "Hi {{name}}", Text: "Hello"},
+	}
+	tx.ScheduleAt(time.Now().Add(time.Hour))
+
+	if _, _, err := client.SendProof(tx, nil); err == nil {
+		t.Error("expected an error without proof recipients")
+	}
+	if _, _, err := client.SendProof(tx, []string{"me@corp.example.com", "qa@corp.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	recips := body["recipients"].([]interface{})
+	if len(recips) != 2 {
+		t.Fatalf("expected 2 proof recipients, got %v", recips)
+	}
+	first := recips[0].(map[string]interface{})
+	if first["address"] != "me@corp.example.com" ||
+		first["substitution_data"].(map[string]interface{})["name"] != "Alex" ||
+		len(first["tags"].([]interface{})) != 2 {
+		t.Errorf("unexpected proof recipient %v", first)
+	}
+	meta := body["metadata"].(map[string]interface{})
+	if meta["proof"] != true || meta["team"] != "growth" {
+		t.Errorf("unexpected metadata %v", meta)
+	}
+	if body["campaign_id"] != "spring-proof" {
+		t.Errorf("unexpected campaign %v", body["campaign_id"])
+	}
+	if opts, ok := body["options"].(map[string]interface{}); ok && opts["start_time"] != nil {
+		t.Errorf("proof shouldn't be scheduled: %v", opts)
+	}
+
+	// the original is untouched
+	if tx.CampaignID != "spring" || tx.Options.StartTime == nil || len(tx.Recipients.([]sp.Recipient)[0].Tags) != 1 {
+		t.Errorf("SendProof modified the Transmission: %+v", tx)
+	}
+
+	tx.CampaignID = strings.Repeat("x", 64)
+	if _, _, err := client.SendProof(tx, []string{"me@corp.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if id := body["campaign_id"].(string); len(id) != 64 || !strings.HasSuffix(id, "-proof") {
+		t.Errorf("unexpected campaign %q", id)
+	}
+
+	// truncation doesn't split a multi-byte character
+	tx.CampaignID = strings.Repeat("x", 57) + "éé"
+	if _, _, err := client.SendProof(tx, []string{"me@corp.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if id := body["campaign_id"].(string); id != strings.Repeat("x", 57)+"-proof" || !utf8.ValidString(id) {
+		t.Errorf("unexpected campaign %q", id)
+	}
+}
+
+func TestSendProofSkipsStages(t *testing.T) {
+	var body struct {
+		Recipients []sp.Recipient `json:"recipients"`
+	}
+	client, done := newTestClient(t, &sp.Config{ApiKey: "testkey", Environment: "staging"}, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+	client.Dedupe = &sp.DuplicateGuard{Store: &sp.MemoryDedupeStore{}, Window: time.Hour}
+	client.FrequencyCap = &sp.FrequencyCap{Store: &sp.MemoryFrequencyStore{}, Max: 1, Window: time.Hour}
+
+	tx := &sp.Transmission{
+		Recipients: []string{"customer@example.com"},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+	}
+	// repeated proofs aren't capped or deduplicated, nor sent to staging's sink
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.SendProof(tx, []string{"me@corp.example.com"}); err != nil {
+			t.Fatalf("proof %d: %v", i, err)
+		}
+		if len(body.Recipients) != 1 || body.Recipients[0].Address != "me@corp.example.com" {
+			t.Fatalf("proof %d: unexpected recipients %+v", i, body.Recipients)
+		}
+	}
+
+	// but an explicit sink still applies
+	client.Config.Sink = sp.SinkDomain
+	if _, _, err := client.SendProof(tx, []string{"me@corp.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Recipients) != 1 || !strings.Contains(fmt.Sprint(body.Recipients[0].Address), sp.SinkDomain) {
+		t.Errorf("expected the proof to be sunk, got %+v", body.Recipients)
+	}
+}
@@ -0,0 +1,100 @@
+package gosparkpost
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time formats accepted in API query strings.
+const (
+	QueryTimeFormat = "2006-01-02T15:04"
+	QueryDateFormat = "2006-01-02"
+)
+
+// QueryBuilder builds API query strings. Empty and zero values are left out,
+// so optional parameters can be added unconditionally. Methods return the
+// QueryBuilder to allow chaining, for example:
+//
+//	QueryBuilder{}.Time("from", from, QueryTimeFormat).List("events", "bounce", "delay").URL(u)
+type QueryBuilder url.Values
+
+// Set adds a parameter, unless v is empty.
+func (q QueryBuilder) Set(k, v string) QueryBuilder {
+	if v != "" {
+		url.Values(q).Set(k, v)
+	}
+	return q
+}
+
+// SetPtr adds a parameter unless v is nil. Unlike Set, a pointer to an empty string
+// adds the parameter with an empty value, which the API treats differently from omitting it.
+func (q QueryBuilder) SetPtr(k string, v *string) QueryBuilder {
+	if v != nil {
+		url.Values(q).Set(k, *v)
+	}
+	return q
+}
+
+// Params adds each of the provided parameters. Like SetPtr, and as the map parameters of
+// older methods always have, empty values are kept.
+func (q QueryBuilder) Params(params map[string]string) QueryBuilder {
+	for k, v := range params {
+		url.Values(q).Set(k, v)
+	}
+	return q
+}
+
+// List adds a comma-separated list, skipping empty values, unless there's nothing in it.
+func (q QueryBuilder) List(k string, vs ...string) QueryBuilder {
+	list := make([]string, 0, len(vs))
+	for _, v := range vs {
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	return q.Set(k, strings.Join(list, ","))
+}
+
+// Ints adds a comma-separated list of integers, unless there's nothing in it.
+func (q QueryBuilder) Ints(k string, ns ...int) QueryBuilder {
+	list := make([]string, len(ns))
+	for i, n := range ns {
+		list[i] = strconv.Itoa(n)
+	}
+	return q.List(k, list...)
+}
+
+// Int adds a parameter, unless n is zero or negative.
+func (q QueryBuilder) Int(k string, n int) QueryBuilder {
+	if n > 0 {
+		url.Values(q).Set(k, strconv.Itoa(n))
+	}
+	return q
+}
+
+// Time adds a time formatted with the provided layout, unless t is the zero time.
+// Times are converted to UTC first, since the API has no way to express time zones.
+func (q QueryBuilder) Time(k string, t time.Time, layout string) QueryBuilder {
+	if !t.IsZero() {
+		url.Values(q).Set(k, t.UTC().Format(layout))
+	}
+	return q
+}
+
+// Encode returns the escaped query string, with parameters sorted by key.
+func (q QueryBuilder) Encode() string {
+	return url.Values(q).Encode()
+}
+
+// URL appends the query string to u, if there is one.
+func (q QueryBuilder) URL(u string) string {
+	if len(q) == 0 {
+		return u
+	}
+	if strings.Contains(u, "?") {
+		return u + "&" + q.Encode()
+	}
+	return u + "?" + q.Encode()
+}
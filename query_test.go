@@ -0,0 +1,31 @@
+package gosparkpost_test
+
+import (
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	empty := ""
+	from := time.Date(2018, 7, 1, 12, 30, 0, 0, time.FixedZone("EST", -5*3600))
+
+	for idx, test := range []struct {
+		q   sp.QueryBuilder
+		url string
+		out string
+	}{
+		{sp.QueryBuilder{}, "/x", "/x"},
+		{sp.QueryBuilder{}.Set("a", "").Int("b", 0).Time("c", time.Time{}, sp.QueryDateFormat).List("d", "", ""), "/x", "/x"},
+		{sp.QueryBuilder{}.SetPtr("campaign_id", &empty).SetPtr("template_id", nil), "/x", "/x?campaign_id="},
+		{sp.QueryBuilder{}.Set("b", "a&b").Set("a", "1"), "/x", "/x?a=1&b=a%26b"},
+		{sp.QueryBuilder{}.List("events", "bounce", "", "delay").Ints("subaccounts", 0, 12), "/x", "/x?events=bounce%2Cdelay&subaccounts=0%2C12"},
+		{sp.QueryBuilder{}.Time("from", from, sp.QueryTimeFormat).Time("to", from, sp.QueryDateFormat), "/x", "/x?from=2018-07-01T17%3A30&to=2018-07-01"},
+		{sp.QueryBuilder{}.Params(map[string]string{"limit": "5", "skip": ""}), "/x?page=2", "/x?page=2&limit=5&skip="},
+	} {
+		if out := test.q.URL(test.url); out != test.out {
+			t.Errorf("QueryBuilder[%d] => got %q, expected %q", idx, out, test.out)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package gosparkpost
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how many messages are sent per second, across every path which shares it,
+// so an account's throughput limit is respected whether mail goes through the REST API
+// (Client.Limiter) or SMTP (SMTPPool, which uses its Client's Limiter). Each recipient counts
+// as one message. Transmissions to stored recipient lists count as one, as their size isn't known.
+type RateLimiter struct {
+	// Rate is the number of messages allowed per second.
+	Rate float64
+	// Burst is how many messages may be sent at once after a quiet period. It defaults to Rate.
+	Burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// Wait blocks until n more messages may be sent, or ctx is done. Batches larger than
+// Burst are allowed through, and later callers wait for the debt to be repaid.
+func (l *RateLimiter) Wait(ctx context.Context, n int) error {
+	if l == nil || l.Rate <= 0 || n <= 0 {
+		return nil
+	}
+	burst := float64(l.Burst)
+	if burst <= 0 {
+		burst = l.Rate
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * l.Rate
+		if l.tokens > burst {
+			l.tokens = burst
+		}
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	wait := time.Duration(-l.tokens / l.Rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		// give back what wasn't used
+		l.mu.Lock()
+		l.tokens += float64(n)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// RateLimit is the state of the API's rate limit, as reported with a Response.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Reset is when the limit next resets. It's zero if the API didn't say.
+	Reset time.Time
+}
+
+// parseRateLimit reads the X-RateLimit-* headers, and Retry-After on a 429, returning nil
+// if there's no rate limit information in the response.
+func parseRateLimit(res *http.Response, now time.Time) *RateLimit {
+	h := res.Header
+	limit, errLimit := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, errReset := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if errLimit != nil && errRemaining != nil && errReset != nil && res.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	rl := &RateLimit{Limit: limit, Remaining: remaining}
+	if errReset == nil {
+		// some APIs send a unix timestamp, others the number of seconds to wait
+		if reset > 1e9 {
+			rl.Reset = time.Unix(reset, 0)
+		} else {
+			rl.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		rl.Remaining = 0
+		if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs >= 0 {
+			if at := now.Add(time.Duration(secs) * time.Second); at.After(rl.Reset) {
+				rl.Reset = at
+			}
+		}
+	} else if errRemaining != nil {
+		// without a count, don't take the missing header to mean nothing's left
+		rl.Remaining = -1
+	}
+	return rl
+}
+
+// observeRateLimit has later requests wait for rl to reset, if it's used up.
+func (c *Client) observeRateLimit(rl *RateLimit) {
+	if rl == nil || rl.Remaining != 0 || rl.Reset.IsZero() {
+		return
+	}
+	c.throttleMu.Lock()
+	if rl.Reset.After(c.throttleUntil) {
+		c.throttleUntil = rl.Reset
+	}
+	c.throttleMu.Unlock()
+}
+
+// waitRateLimit blocks until the API's rate limit has reset, or ctx is done.
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	c.throttleMu.Lock()
+	wait := time.Until(c.throttleUntil)
+	c.throttleMu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
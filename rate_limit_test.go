@@ -0,0 +1,132 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := &sp.RateLimiter{Rate: 100, Burst: 2}
+	ctx := context.Background()
+
+	start := time.Now()
+	if err := l.Wait(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("burst shouldn't wait, took %s", elapsed)
+	}
+	if err := l.Wait(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected to wait about 50ms, took %s", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Wait(cancelled, 100); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientLimiter(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+	client.Limiter = &sp.RateLimiter{Rate: 100, Burst: 1}
+
+	tx := &sp.Transmission{
+		Recipients: []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+	}
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Send(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the first send leaves a debt of 4 messages, which the second waits for
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected sends to be limited, took %s", elapsed)
+	}
+}
+
+func TestResponseRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	var status int32 = http.StatusOK
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.LoadInt32(&status) == http.StatusOK {
+			w.Header().Set("X-RateLimit-Limit", "300")
+			w.Header().Set("X-RateLimit-Remaining", "299")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		} else {
+			w.Header().Set("Retry-After", "30")
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte(`{"results":[]}`))
+	})
+	defer done()
+
+	res, err := client.HttpGet(client.Config.BaseUrl + "/api/v1/templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rl := res.RateLimit; rl == nil || rl.Limit != 300 || rl.Remaining != 299 || !rl.Reset.Equal(reset) {
+		t.Fatalf("unexpected rate limit %+v", rl)
+	}
+
+	atomic.StoreInt32(&status, http.StatusTooManyRequests)
+	before := time.Now()
+	if res, err = client.HttpGet(client.Config.BaseUrl + "/api/v1/templates"); err != nil {
+		t.Fatal(err)
+	}
+	if rl := res.RateLimit; rl == nil || rl.Remaining != 0 || rl.Reset.Before(before.Add(29*time.Second)) {
+		t.Fatalf("unexpected rate limit %+v", rl)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	var calls int32
+	var last time.Time
+	var gap time.Duration
+	client, done := newTestClient(t, &sp.Config{ApiKey: "testkey", Throttle: true}, func(w http.ResponseWriter, r *http.Request) {
+		if n := atomic.AddInt32(&calls, 1); n == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1")
+		} else if n == 2 {
+			gap = time.Since(last)
+		}
+		last = time.Now()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[]}`))
+	})
+	defer done()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.HttpGet(client.Config.BaseUrl + "/api/v1/templates"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gap < 900*time.Millisecond {
+		t.Errorf("expected the second request to wait for the reset, it came after %s", gap)
+	}
+
+	// a used up limit which resets later still lets a request give up
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	atomic.StoreInt32(&calls, 0)
+	client.HttpGet(client.Config.BaseUrl + "/api/v1/templates")
+	if _, err := client.DoRequestContext(ctx, "GET", client.Config.BaseUrl+"/api/v1/templates", nil, nil); err != context.DeadlineExceeded {
+		t.Errorf("expected the throttled request to time out, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RawContent is inline Content which SparkPost sends exactly as provided. Substitutions are turned
+// off (with options.perform_substitutions), so user-generated content that happens to contain
+// braces reaches recipients intact. Use it as Transmission.Content in place of Content;
+// the Transmission is encoded with TxOptions.PerformSubstitutions to match.
+type RawContent Content
+
+func (c RawContent) MarshalJSON() ([]byte, error) {
+	return Content(c).MarshalJSON()
+}
+
+// MarshalJSON turns substitutions off for a Transmission with RawContent, without modifying
+// its Options.
+func (t Transmission) MarshalJSON() ([]byte, error) {
+	// a distinct type, which doesn't have this method
+	type transmission Transmission
+	if _, ok := t.Content.(RawContent); ok {
+		var opts TxOptions
+		if t.Options != nil {
+			opts = *t.Options
+		}
+		off := false
+		opts.PerformSubstitutions = &off
+		t.Options = &opts
+	}
+	return json.Marshal(transmission(t))
+}
+
+// validateRaw checks a Transmission with RawContent, which is sent with substitutions off.
+// Since tags like {{ name }} are sent literally, it's an error for them to name substitution
+// data provided with the Transmission, which suggests the content was meant to be a template.
+func (t *Transmission) validateRaw(c RawContent) error {
+	if t.Options != nil && t.Options.PerformSubstitutions != nil && *t.Options.PerformSubstitutions {
+		return fmt.Errorf("RawContent can't be sent with PerformSubstitutions")
+	}
+
+	provided := map[string]bool{}
+	if err := substitutionKeys(t.SubstitutionData, provided); err != nil {
+		return err
+	}
+	recips, err := inlineRecipients(t.Recipients)
+	if err != nil {
+		return err
+	}
+	for _, r := range recips {
+		if err = substitutionKeys(r.SubstitutionData, provided); err != nil {
+			return err
+		}
+	}
+	if len(provided) == 0 {
+		return nil
+	}
+
+	// content which doesn't parse as a template can't be mistaken for one
+	vars, err := TemplateVars(Content(c))
+	if err != nil {
+		return nil
+	}
+	for _, v := range vars {
+		if provided[v.Name] {
+			return fmt.Errorf("RawContent would send the tag for [%s] literally, since substitutions are off", v.Name)
+		}
+	}
+	return nil
+}
+
+// substitutionKeys adds the top-level keys of substitution data to keys.
+func substitutionKeys(data interface{}, keys map[string]bool) error {
+	if data == nil {
+		return nil
+	}
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var m map[string]json.RawMessage
+	if err = json.Unmarshal(jsonBytes, &m); err != nil {
+		return fmt.Errorf("SubstitutionData must be an object: %s", err)
+	}
+	for k := range m {
+		keys[k] = true
+	}
+	return nil
+}
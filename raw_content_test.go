@@ -0,0 +1,63 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestRawContent(t *testing.T) {
+	content := sp.RawContent{
+		From:    "test@example.com",
+		Subject: "New comment",
+		Text:    "Try {{ name }} or {{#each}} in your template!",
+	}
+	tx := &sp.Transmission{Recipients: []string{"a@example.com"}, Content: content}
+	if err := tx.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Options != nil {
+		t.Fatalf("expected the Transmission's Options to be left alone, got %+v", tx.Options)
+	}
+
+	jsonBytes, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Options map[string]interface{} `json:"options"`
+		Content map[string]interface{} `json:"content"`
+	}
+	json.Unmarshal(jsonBytes, &body)
+	if body.Options["perform_substitutions"] != false || body.Content["text"] != content.Text {
+		t.Errorf("unexpected body %s", jsonBytes)
+	}
+
+	// other options are kept, and the caller's aren't modified
+	opts := &sp.TxOptions{Sandbox: "true"}
+	tx = &sp.Transmission{Recipients: []string{"a@example.com"}, Content: content, Options: opts}
+	if jsonBytes, err = json.Marshal(tx); err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(jsonBytes, &body)
+	if body.Options["perform_substitutions"] != false || body.Options["sandbox"] != "true" || opts.PerformSubstitutions != nil {
+		t.Errorf("unexpected body %s, options %+v", jsonBytes, opts)
+	}
+
+	// the tag names substitution data, so the content was probably meant to be a template
+	tx = &sp.Transmission{
+		Recipients: []sp.Recipient{{Address: "a@example.com", SubstitutionData: map[string]string{"name": "Alice"}}},
+		Content:    content,
+	}
+	if err = tx.Validate(); err == nil || !strings.Contains(err.Error(), "[name]") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	on := true
+	tx = &sp.Transmission{Recipients: []string{"a@example.com"}, Content: content, Options: &sp.TxOptions{PerformSubstitutions: &on}}
+	if err = tx.Validate(); err == nil {
+		t.Error("expected an error with PerformSubstitutions set")
+	}
+}
@@ -20,6 +20,10 @@ type RecipientList struct {
 	Recipients  *[]Recipient `json:"recipients"`
 
 	Accepted *int `json:"total_accepted_recipients,omitempty"`
+
+	// NumRcptErrors, if set, asks RecipientListCreate for up to this many errors for rejected
+	// recipients, see Response.SendResults. It's sent as a query parameter, not in the body.
+	NumRcptErrors int `json:"-"`
 }
 
 func (rl *RecipientList) String() string {
@@ -40,6 +44,10 @@ type Recipient struct {
 	Tags             []string    `json:"tags,omitempty"`
 	Metadata         interface{} `json:"metadata,omitempty"`
 	SubstitutionData interface{} `json:"substitution_data,omitempty"`
+
+	// Attachments aren't supported per-recipient by the API.
+	// See ExpandAttachments for how to send them anyway.
+	Attachments []Attachment `json:"-"`
 }
 
 // Address describes the nested object way of specifying the Recipient's email address.
@@ -141,6 +149,7 @@ func (r Recipient) Validate() error {
 
 // Create accepts a populated RecipientList object, validates it,
 // and performs an API call against the configured endpoint.
+// Counts of accepted and rejected recipients are available from res.SendResults.
 func (c *Client) RecipientListCreate(rl *RecipientList) (id string, res *Response, err error) {
 	if rl == nil {
 		err = fmt.Errorf("Create called with nil RecipientList")
@@ -158,7 +167,7 @@ func (c *Client) RecipientListCreate(rl *RecipientList) (id string, res *Respons
 	}
 
 	path := fmt.Sprintf(recipListsPathFormat, c.Config.ApiVersion)
-	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	url := QueryBuilder{}.Int("num_rcpt_errors", rl.NumRcptErrors).URL(c.Config.BaseUrl + path)
 	res, err = c.HttpPost(url, jsonBytes)
 	if err != nil {
 		return
@@ -199,6 +208,7 @@ func (c *Client) RecipientListCreate(rl *RecipientList) (id string, res *Respons
 	return
 }
 
+// RecipientLists returns the metadata of all RecipientLists, without their Recipients.
 func (c *Client) RecipientLists() (*[]RecipientList, *Response, error) {
 	path := fmt.Sprintf(recipListsPathFormat, c.Config.ApiVersion)
 	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
@@ -241,3 +251,153 @@ func (c *Client) RecipientLists() (*[]RecipientList, *Response, error) {
 
 	return nil, res, err
 }
+
+// RecipientList retrieves the RecipientList with the specified id.
+// Its Recipients are only included if showRecipients is set.
+func (c *Client) RecipientList(id string, showRecipients bool) (*RecipientList, *Response, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank id")
+	}
+
+	path := fmt.Sprintf(recipListsPathFormat, c.Config.ApiVersion)
+	q := QueryBuilder{}
+	if showRecipients {
+		q = q.Set("show_recipients", "true")
+	}
+	url := q.URL(fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id))
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*RecipientList{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if rl, ok := tmp["results"]; ok && rl != nil {
+			return rl, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to RecipientList retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("RecipientList", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// RecipientListUpdate replaces the RecipientList with the ID of the one provided,
+// including all of its Recipients.
+func (c *Client) RecipientListUpdate(rl *RecipientList) (res *Response, err error) {
+	if rl == nil {
+		err = fmt.Errorf("Update called with nil RecipientList")
+		return
+	} else if rl.ID == "" {
+		err = fmt.Errorf("Update called with blank id")
+		return
+	}
+
+	err = rl.Validate()
+	if err != nil {
+		return
+	}
+
+	tmp := *rl
+	tmp.ID = ""
+	tmp.Accepted = nil
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(recipListsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, rl.ID)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RecipientList", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// RecipientListDelete removes the RecipientList with the specified id.
+func (c *Client) RecipientListDelete(id string) (res *Response, err error) {
+	if id == "" {
+		err = fmt.Errorf("Delete called with blank id")
+		return
+	}
+
+	path := fmt.Sprintf(recipListsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RecipientList", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
@@ -1,6 +1,9 @@
 package gosparkpost_test
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -44,3 +47,85 @@ func TestRecipients(t *testing.T) {
 	}
 	t.Errorf("%s\n", strings.Join(strs, "\n"))
 }
+
+func TestRecipientListCRUD(t *testing.T) {
+	var calls []string
+	bodies := map[string]map[string]interface{}{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.RequestURI()
+		calls = append(calls, call)
+		if r.Method == "POST" || r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+			var m map[string]interface{}
+			json.Unmarshal(body, &m)
+			bodies[call] = m
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(204)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "POST /api/v1/recipient-lists?num_rcpt_errors=3":
+			w.Write([]byte(`{"results":{"id":"vips","name":"VIPs","total_accepted_recipients":1,"total_rejected_recipients":1,"rcpt_to_errors":[{"message":"invalid address","code":"1100"}]}}`))
+		case "GET /api/v1/recipient-lists/vips?show_recipients=true":
+			w.Write([]byte(`{"results":{"id":"vips","name":"VIPs","total_accepted_recipients":1,"recipients":[{"address":{"email":"a@example.com"}}]}}`))
+		case "GET /api/v1/recipient-lists/vips":
+			w.Write([]byte(`{"results":{"id":"vips","name":"VIPs","total_accepted_recipients":1}}`))
+		case "GET /api/v1/recipient-lists/missing":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{"id":"vips"}}`))
+		}
+	})
+	defer done()
+
+	recips := []sp.Recipient{{Address: "a@example.com"}, {Address: "b@example.com"}}
+	id, res, err := client.RecipientListCreate(&sp.RecipientList{Name: "VIPs", Recipients: &recips, NumRcptErrors: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "vips" {
+		t.Errorf("unexpected id %q", id)
+	}
+	results, err := res.SendResults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.TotalRejectedRecipients != 1 || len(results.RecipientErrors) != 1 {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	rl, _, err := client.RecipientList("vips", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rl.Recipients == nil || len(*rl.Recipients) != 1 {
+		t.Errorf("expected recipients, got %v", rl)
+	}
+	if rl, _, err = client.RecipientList("vips", false); err != nil {
+		t.Fatal(err)
+	} else if rl.Recipients != nil || *rl.Accepted != 1 {
+		t.Errorf("unexpected list %v", rl)
+	}
+	if _, _, err = client.RecipientList("missing", false); err == nil {
+		t.Error("expected an error for a missing list")
+	}
+
+	rl.Recipients = &recips
+	if _, err = client.RecipientListUpdate(rl); err != nil {
+		t.Fatal(err)
+	}
+	updated := bodies["PUT /api/v1/recipient-lists/vips"]
+	if _, ok := updated["id"]; ok || len(updated["recipients"].([]interface{})) != 2 {
+		t.Errorf("unexpected update body %v", updated)
+	}
+
+	if _, err = client.RecipientListDelete("vips"); err != nil {
+		t.Fatal(err)
+	}
+	if calls[len(calls)-1] != "DELETE /api/v1/recipient-lists/vips" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
@@ -0,0 +1,250 @@
+package gosparkpost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// https://developers.sparkpost.com/api/recipient-validation/
+var recipientValidationPathFormat = "/api/v%d/recipient-validation"
+
+// Values of RecipientValidation.Result.
+const (
+	ValidationValid         = "valid"
+	ValidationUndeliverable = "undeliverable"
+	ValidationRisky         = "risky"
+	ValidationNeutral       = "neutral"
+	ValidationTypo          = "typo"
+)
+
+// Values of RecipientValidationJob.Status.
+const (
+	ValidationJobQueued     = "queued_for_batch"
+	ValidationJobProcessing = "batch_triggered"
+	ValidationJobSuccess    = "success"
+	ValidationJobError      = "error"
+)
+
+// RecipientValidation is the verdict on a single email address.
+type RecipientValidation struct {
+	Valid bool `json:"valid"`
+	// Result is one of the Validation* constants.
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+
+	IsRole       bool `json:"is_role"`
+	IsDisposable bool `json:"is_disposable"`
+	IsFree       bool `json:"is_free"`
+	// DeliveryConfidence is a score from 0 to 100.
+	DeliveryConfidence int `json:"delivery_confidence"`
+	// DidYouMean suggests a correction for addresses with a likely typo.
+	DidYouMean string `json:"did_you_mean,omitempty"`
+}
+
+// RecipientValidationJob is the progress of validating a list uploaded with RecipientValidationUpload.
+type RecipientValidationJob struct {
+	ListID string `json:"list_id"`
+	// Status is one of the ValidationJob* constants.
+	Status       string `json:"batch_status"`
+	Complete     bool   `json:"complete"`
+	AddressCount int    `json:"address_count"`
+	// UploadTimestamp and CompleteTimestamp are Unix times.
+	UploadTimestamp   int64 `json:"upload_timestamp,omitempty"`
+	CompleteTimestamp int64 `json:"complete_timestamp,omitempty"`
+}
+
+// RecipientValidate checks a single email address.
+func (c *Client) RecipientValidate(email string) (*RecipientValidation, *Response, error) {
+	if email == "" {
+		return nil, nil, fmt.Errorf("RecipientValidate called with blank email")
+	}
+
+	path := fmt.Sprintf(recipientValidationPathFormat, c.Config.ApiVersion)
+	u := fmt.Sprintf("%s%s/single/%s", c.Config.BaseUrl, path, url.PathEscape(email))
+	v := &RecipientValidation{}
+	res, err := c.recipientValidationGet(u, "validate", v)
+	if err != nil {
+		return nil, res, err
+	}
+	return v, res, nil
+}
+
+// RecipientValidationUpload uploads a list of addresses to be validated in the background,
+// returning the id of the list. Validation begins once RecipientValidationStart is called.
+func (c *Client) RecipientValidationUpload(emails []string) (listID string, res *Response, err error) {
+	if len(emails) == 0 {
+		err = fmt.Errorf("RecipientValidationUpload requires emails")
+		return
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("myupload", "recipients.csv")
+	if err != nil {
+		return
+	}
+	part.Write([]byte(strings.Join(emails, "\n") + "\n"))
+	if err = form.Close(); err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(recipientValidationPathFormat, c.Config.ApiVersion)
+	u := fmt.Sprintf("%s%s/upload", c.Config.BaseUrl, path)
+	res, err = c.DoRequestWithHeaders("POST", u, body.Bytes(), map[string]string{
+		"Content-Type": form.FormDataContentType(),
+	})
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var ok bool
+		listID, ok = res.Results["list_id"].(string)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to RecipientValidation upload")
+		}
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RecipientValidation", "upload")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// RecipientValidationStart begins validating an uploaded list.
+func (c *Client) RecipientValidationStart(listID string) (res *Response, err error) {
+	if listID == "" {
+		err = fmt.Errorf("RecipientValidationStart called with blank list id")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(map[string]string{"status": ValidationJobQueued})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(recipientValidationPathFormat, c.Config.ApiVersion)
+	u := fmt.Sprintf("%s%s/job/%s", c.Config.BaseUrl, path, listID)
+	res, err = c.HttpPut(u, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RecipientValidation", "start")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// RecipientValidationJob retrieves the progress of validating an uploaded list.
+func (c *Client) RecipientValidationJob(listID string) (*RecipientValidationJob, *Response, error) {
+	if listID == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank list id")
+	}
+
+	path := fmt.Sprintf(recipientValidationPathFormat, c.Config.ApiVersion)
+	u := fmt.Sprintf("%s%s/job/%s", c.Config.BaseUrl, path, listID)
+	job := &RecipientValidationJob{}
+	res, err := c.recipientValidationGet(u, "retrieve", job)
+	if err != nil {
+		return nil, res, err
+	}
+	return job, res, nil
+}
+
+// WaitForRecipientValidation polls an uploaded list every interval (defaulting to 30 seconds)
+// until validation completes or fails, returning the final progress.
+// An error is only returned if polling fails, or ctx is done first.
+func (c *Client) WaitForRecipientValidation(ctx context.Context, listID string, interval time.Duration) (*RecipientValidationJob, error) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	for {
+		job, _, err := c.RecipientValidationJob(listID)
+		if err != nil || job.Complete || job.Status == ValidationJobSuccess || job.Status == ValidationJobError {
+			return job, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// recipientValidationGet decodes the results of a GET request into v.
+func (c *Client) recipientValidationGet(u, verb string, v interface{}) (*Response, error) {
+	res, err := c.HttpGet(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return res, err
+		}
+		wrapper := struct {
+			Results interface{} `json:"results"`
+		}{v}
+		return res, json.Unmarshal(body, &wrapper)
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("RecipientValidation", verb)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
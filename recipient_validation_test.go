@@ -0,0 +1,77 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestRecipientValidation(t *testing.T) {
+	var uploaded, started string
+	polls := 0
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /api/v1/recipient-validation/single/jane+test@gmial.com":
+			w.Write([]byte(`{"results":{"valid":false,"result":"typo","reason":"Typo detected","is_role":false,"is_disposable":false,"is_free":true,"delivery_confidence":5,"did_you_mean":"jane+test@gmail.com"}}`))
+		case "POST /api/v1/recipient-validation/upload":
+			f, _, err := r.FormFile("myupload")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			b, _ := ioutil.ReadAll(f)
+			uploaded = string(b)
+			w.Write([]byte(`{"results":{"list_id":"4b2d3b4e-de0a-4f9d-8a7a-d8e7e5e1a8c3"}}`))
+		case "PUT /api/v1/recipient-validation/job/4b2d3b4e-de0a-4f9d-8a7a-d8e7e5e1a8c3":
+			b, _ := ioutil.ReadAll(r.Body)
+			started = string(b)
+			w.Write([]byte(`{"results":{}}`))
+		case "GET /api/v1/recipient-validation/job/4b2d3b4e-de0a-4f9d-8a7a-d8e7e5e1a8c3":
+			polls++
+			if polls < 2 {
+				w.Write([]byte(`{"results":{"list_id":"4b2d3b4e-de0a-4f9d-8a7a-d8e7e5e1a8c3","batch_status":"batch_triggered","complete":false,"address_count":2}}`))
+				return
+			}
+			w.Write([]byte(`{"results":{"list_id":"4b2d3b4e-de0a-4f9d-8a7a-d8e7e5e1a8c3","batch_status":"success","complete":true,"address_count":2,"complete_timestamp":1496275200}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+	})
+	defer done()
+
+	v, _, err := client.RecipientValidate("jane+test@gmial.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Valid || v.Result != sp.ValidationTypo || v.DidYouMean != "jane+test@gmail.com" || !v.IsFree || v.DeliveryConfidence != 5 {
+		t.Errorf("unexpected validation %+v", v)
+	}
+
+	listID, _, err := client.RecipientValidationUpload([]string{"a@example.com", "b@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != "a@example.com\nb@example.com\n" {
+		t.Errorf("unexpected upload %q", uploaded)
+	}
+	if _, err = client.RecipientValidationStart(listID); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(started, `"queued_for_batch"`) {
+		t.Errorf("unexpected start body %s", started)
+	}
+
+	job, err := client.WaitForRecipientValidation(context.Background(), listID, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 2 || !job.Complete || job.Status != sp.ValidationJobSuccess || job.AddressCount != 2 {
+		t.Errorf("unexpected job %+v after %d polls", job, polls)
+	}
+}
@@ -0,0 +1,93 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Regions a SparkPost account can be in, for Config.Region.
+const (
+	RegionUS = "us"
+	RegionEU = "eu"
+)
+
+// RegionBaseUrls maps each region to its API's base URL.
+var RegionBaseUrls = map[string]string{
+	RegionUS: "https://api.sparkpost.com",
+	RegionEU: "https://api.eu.sparkpost.com",
+}
+
+// resolveRegion sets BaseUrl from Region, or checks that they agree if both are set.
+func (cfg *Config) resolveRegion() error {
+	if cfg.Region == "" {
+		return nil
+	}
+	url, ok := RegionBaseUrls[strings.ToLower(cfg.Region)]
+	if !ok {
+		regions := make([]string, 0, len(RegionBaseUrls))
+		for r := range RegionBaseUrls {
+			regions = append(regions, r)
+		}
+		sort.Strings(regions)
+		return fmt.Errorf("Unknown region [%s], expected one of %s", cfg.Region, strings.Join(regions, ", "))
+	}
+	if cfg.BaseUrl == "" {
+		cfg.BaseUrl = url
+		return nil
+	}
+	// a custom BaseUrl (a proxy, say) is fine, but not another region's
+	if region := regionOf(cfg.BaseUrl); region != "" && url != RegionBaseUrls[region] {
+		return fmt.Errorf("Config.BaseUrl [%s] is for region [%s], not [%s]", cfg.BaseUrl, region, cfg.Region)
+	}
+	return nil
+}
+
+// regionOf returns the region whose API is at baseUrl, if any.
+func regionOf(baseUrl string) string {
+	for region, url := range RegionBaseUrls {
+		if strings.TrimRight(baseUrl, "/") == url {
+			return region
+		}
+	}
+	return ""
+}
+
+// RegionMismatchError is returned by VerifyRegion when the API key belongs to an account
+// in another region.
+type RegionMismatchError struct {
+	Configured string
+	KeyRegion  string
+}
+
+func (e *RegionMismatchError) Error() string {
+	return fmt.Sprintf("API key belongs to a SparkPost account in region [%s], not [%s]", e.KeyRegion, e.Configured)
+}
+
+// VerifyRegion checks that the API key is accepted in the Client's region. If it isn't,
+// and the key is accepted in another region, a RegionMismatchError says which.
+// Keys for either region look alike, so this takes a request or two to find out.
+func (c *Client) VerifyRegion() error {
+	_, res, err := c.Account()
+	if err == nil || res == nil || res.HTTP == nil || res.HTTP.StatusCode != 401 {
+		return err
+	}
+	configured := regionOf(c.Config.BaseUrl)
+	if configured == "" {
+		return err
+	}
+	for region, url := range RegionBaseUrls {
+		if region == configured {
+			continue
+		}
+		probe := &Client{Client: c.Client}
+		cfg := &Config{ApiKey: c.Config.ApiKey, BaseUrl: url, ApiVersion: c.Config.ApiVersion}
+		if probe.Init(cfg) != nil {
+			continue
+		}
+		if _, _, perr := probe.Account(); perr == nil {
+			return &RegionMismatchError{Configured: configured, KeyRegion: region}
+		}
+	}
+	return err
+}
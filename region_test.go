@@ -0,0 +1,85 @@
+package gosparkpost_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestConfigRegion(t *testing.T) {
+	for _, test := range []struct {
+		cfg     sp.Config
+		baseUrl string
+		ok      bool
+	}{
+		{sp.Config{ApiKey: "k"}, "https://api.sparkpost.com", true},
+		{sp.Config{ApiKey: "k", Region: sp.RegionEU}, "https://api.eu.sparkpost.com", true},
+		{sp.Config{ApiKey: "k", Region: "EU"}, "https://api.eu.sparkpost.com", true},
+		{sp.Config{ApiKey: "k", Region: sp.RegionEU, BaseUrl: "https://proxy.example.com"}, "https://proxy.example.com", true},
+		{sp.Config{ApiKey: "k", Region: sp.RegionEU, BaseUrl: "https://api.sparkpost.com"}, "", false},
+		{sp.Config{ApiKey: "k", Region: "ap"}, "", false},
+	} {
+		cfg := test.cfg
+		var client sp.Client
+		err := client.Init(&cfg)
+		if (err == nil) != test.ok || (test.ok && cfg.BaseUrl != test.baseUrl) {
+			t.Errorf("%+v: got base url %q, %v", test.cfg, cfg.BaseUrl, err)
+		}
+	}
+
+	cfg, err := sp.NewConfig(map[string]string{"apikey": "k", "region": "eu"})
+	if err != nil || cfg.BaseUrl != "https://api.eu.sparkpost.com" {
+		t.Errorf("unexpected config %+v, %v", cfg, err)
+	}
+	client, err := sp.NewClient(sp.WithAPIKey("k"), sp.WithRegion(sp.RegionEU))
+	if err != nil || client.Config.BaseUrl != "https://api.eu.sparkpost.com" {
+		t.Errorf("unexpected client %+v, %v", client, err)
+	}
+	if _, err = sp.NewClient(sp.WithAPIKey("k"), sp.WithRegion("mars")); err == nil {
+		t.Error("expected an error for an unknown region")
+	}
+}
+
+func regionServer(key string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != key {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errors":[{"message":"Unauthorized."}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"results":{"customer_id":1}}`)
+	}))
+}
+
+func TestVerifyRegion(t *testing.T) {
+	us, eu := regionServer("us-key"), regionServer("eu-key")
+	defer us.Close()
+	defer eu.Close()
+	saved := sp.RegionBaseUrls
+	sp.RegionBaseUrls = map[string]string{sp.RegionUS: us.URL, sp.RegionEU: eu.URL}
+	defer func() { sp.RegionBaseUrls = saved }()
+
+	verify := func(key string) error {
+		client := &sp.Client{Client: us.Client()}
+		if err := client.Init(&sp.Config{ApiKey: key, Region: sp.RegionUS}); err != nil {
+			t.Fatal(err)
+		}
+		return client.VerifyRegion()
+	}
+	if err := verify("us-key"); err != nil {
+		t.Errorf("expected the US key to verify, got %v", err)
+	}
+	err := verify("eu-key")
+	if mismatch, ok := err.(*sp.RegionMismatchError); !ok || mismatch.KeyRegion != sp.RegionEU || mismatch.Configured != sp.RegionUS {
+		t.Errorf("expected a RegionMismatchError, got %v", err)
+	}
+	if err = verify("bad-key"); err == nil {
+		t.Error("expected an error for a key from neither region")
+	} else if _, ok := err.(*sp.RegionMismatchError); ok {
+		t.Errorf("unexpected RegionMismatchError %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/relay-webhooks/
+var relayWebhooksPathFormat = "/api/v%d/relay-webhooks"
+
+// RelayWebhook forwards email received at an inbound domain to Target, as JSON.
+type RelayWebhook struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Target string `json:"target,omitempty"`
+	// AuthToken is sent to Target in the X-MessageSystems-Webhook-Token header.
+	AuthToken string     `json:"auth_token,omitempty"`
+	Match     RelayMatch `json:"match"`
+}
+
+// RelayMatch selects the inbound messages a RelayWebhook receives.
+type RelayMatch struct {
+	// Protocol is "SMTP" (the default) or "SMPP".
+	Protocol    string `json:"protocol,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	EsmeAddress string `json:"esme_address,omitempty"`
+}
+
+func (r *RelayWebhook) String() string {
+	return fmt.Sprintf("%s %q %s:%s -> %s", r.ID, r.Name, r.Match.Protocol, r.Match.Domain, r.Target)
+}
+
+// RelayWebhookCreate accepts a populated RelayWebhook and creates it, returning its ID.
+func (c *Client) RelayWebhookCreate(r *RelayWebhook) (id string, res *Response, err error) {
+	if r == nil {
+		err = fmt.Errorf("Create called with nil RelayWebhook")
+		return
+	} else if r.Target == "" || (r.Match.Domain == "" && r.Match.EsmeAddress == "") {
+		err = fmt.Errorf("RelayWebhook requires a non-empty Target and Match.Domain")
+		return
+	}
+
+	tmp := *r
+	tmp.ID = ""
+	if tmp.Match.Protocol == "" {
+		tmp.Match.Protocol = "SMTP"
+	}
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(relayWebhooksPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var ok bool
+		id, ok = res.Results["id"].(string)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to RelayWebhook creation")
+		}
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RelayWebhook", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// RelayWebhooks returns all RelayWebhooks.
+func (c *Client) RelayWebhooks() ([]RelayWebhook, *Response, error) {
+	path := fmt.Sprintf(relayWebhooksPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		rlist := map[string][]RelayWebhook{}
+		if err = json.Unmarshal(body, &rlist); err != nil {
+			return nil, res, err
+		} else if list, ok := rlist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to RelayWebhook list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("RelayWebhook", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// RelayWebhook retrieves the RelayWebhook with the specified id.
+func (c *Client) RelayWebhook(id string) (*RelayWebhook, *Response, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank id")
+	}
+
+	path := fmt.Sprintf(relayWebhooksPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*RelayWebhook{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if r, ok := tmp["results"]; ok && r != nil {
+			// the id isn't repeated in the results
+			r.ID = id
+			return r, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to RelayWebhook retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("RelayWebhook", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// RelayWebhookUpdate replaces the settings of the RelayWebhook with the ID of the one provided.
+func (c *Client) RelayWebhookUpdate(r *RelayWebhook) (res *Response, err error) {
+	if r == nil {
+		err = fmt.Errorf("Update called with nil RelayWebhook")
+		return
+	} else if r.ID == "" {
+		err = fmt.Errorf("Update called with blank id")
+		return
+	}
+
+	tmp := *r
+	tmp.ID = ""
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(relayWebhooksPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, r.ID)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RelayWebhook", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// RelayWebhookDelete removes the RelayWebhook with the specified id.
+func (c *Client) RelayWebhookDelete(id string) (res *Response, err error) {
+	if id == "" {
+		err = fmt.Errorf("Delete called with blank id")
+		return
+	}
+
+	path := fmt.Sprintf(relayWebhooksPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("RelayWebhook", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
@@ -0,0 +1,101 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestRelayWebhooks(t *testing.T) {
+	var calls []string
+	var created map[string]interface{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/relay-webhooks":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &created)
+			w.Write([]byte(`{"results":{"id":"12013026328707075"}}`))
+		case "GET /api/v1/relay-webhooks":
+			w.Write([]byte(`{"results":[{"id":"12013026328707075","name":"Replies","target":"https://example.com/inbound","match":{"protocol":"SMTP","domain":"replies.example.com"}}]}`))
+		case "GET /api/v1/relay-webhooks/12013026328707075":
+			w.Write([]byte(`{"results":{"name":"Replies","target":"https://example.com/inbound","match":{"protocol":"SMTP","domain":"replies.example.com"}}}`))
+		case "GET /api/v1/relay-webhooks/missing":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, _, err := client.RelayWebhookCreate(&sp.RelayWebhook{Name: "No target"}); err == nil {
+		t.Error("expected an error without a target")
+	}
+
+	rw := &sp.RelayWebhook{
+		Name:      "Replies",
+		Target:    "https://example.com/inbound",
+		AuthToken: "secret",
+		Match:     sp.RelayMatch{Domain: "replies.example.com"},
+	}
+	id, _, err := client.RelayWebhookCreate(rw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "12013026328707075" {
+		t.Errorf("unexpected id %q", id)
+	}
+	match, _ := created["match"].(map[string]interface{})
+	if match["protocol"] != "SMTP" || match["domain"] != "replies.example.com" || created["auth_token"] != "secret" {
+		t.Errorf("unexpected request body %v", created)
+	}
+
+	list, _, err := client.RelayWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Match.Domain != "replies.example.com" {
+		t.Errorf("unexpected list %v", list)
+	}
+
+	got, _, err := client.RelayWebhook(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != id || got.Target != rw.Target {
+		t.Errorf("unexpected relay webhook %s", got)
+	}
+	if _, _, err = client.RelayWebhook("missing"); err == nil {
+		t.Error("expected an error for an unknown relay webhook")
+	}
+
+	got.Target = "https://example.com/v2/inbound"
+	if _, err = client.RelayWebhookUpdate(got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.RelayWebhookDelete(id); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST /api/v1/relay-webhooks",
+		"GET /api/v1/relay-webhooks",
+		"GET /api/v1/relay-webhooks/12013026328707075",
+		"GET /api/v1/relay-webhooks/missing",
+		"PUT /api/v1/relay-webhooks/12013026328707075",
+		"DELETE /api/v1/relay-webhooks/12013026328707075",
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("call %d: expected %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
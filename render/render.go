@@ -0,0 +1,169 @@
+// Package render prints SparkPost API results for command-line tools, as aligned tables,
+// JSON or YAML.
+//
+// Templates, Subaccounts, Webhooks and suppression list entries have table layouts,
+// as returned by Client.Templates, Client.Subaccounts, Client.ListWebhooks and
+// Client.SuppressionSearch. Anything can be written as JSON or YAML.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// Format selects how Write renders results. It implements flag.Value, for an --output flag.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+func (f *Format) String() string {
+	return string(*f)
+}
+
+func (f *Format) Set(s string) error {
+	switch Format(strings.ToLower(s)) {
+	case FormatTable, FormatJSON, FormatYAML:
+		*f = Format(strings.ToLower(s))
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or yaml", s)
+}
+
+// Write renders v to w in the requested format. The empty Format is FormatTable.
+func Write(w io.Writer, format Format, v interface{}) error {
+	v = unwrap(v)
+	switch format {
+	case "", FormatTable:
+		t, err := NewTable(v)
+		if err != nil {
+			return err
+		}
+		return t.Write(w)
+	case FormatJSON:
+		return JSON(w, v)
+	case FormatYAML:
+		return YAML(w, v)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// unwrap returns the results held by the API's wrapper types.
+func unwrap(v interface{}) interface{} {
+	switch w := v.(type) {
+	case *sp.SuppressionListWrapper:
+		return w.Results
+	case *sp.WebhookListWrapper:
+		return w.Results
+	}
+	return v
+}
+
+// JSON writes v to w as indented JSON.
+func JSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(unwrap(v), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Table is rows of text printed in aligned columns.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// Write prints the table to w, with columns separated by at least two spaces.
+func (t *Table) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Headers, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// NewTable lays out results from the API as a Table.
+func NewTable(v interface{}) (*Table, error) {
+	t := &Table{}
+	switch items := unwrap(v).(type) {
+	case []sp.Template:
+		t.Headers = []string{"ID", "NAME", "PUBLISHED", "DRAFT", "LAST UPDATED"}
+		for _, tmpl := range items {
+			t.Rows = append(t.Rows, []string{tmpl.ID, tmpl.Name, yesNo(tmpl.Published || tmpl.HasPublished),
+				yesNo(tmpl.HasDraft), timestamp(tmpl.LastUpdate)})
+		}
+	case []sp.Subaccount:
+		t.Headers = []string{"ID", "NAME", "STATUS", "COMPLIANCE", "KEY LABEL"}
+		for _, s := range items {
+			t.Rows = append(t.Rows, []string{fmt.Sprint(s.ID), s.Name, s.Status, s.ComplianceStatus, s.KeyLabel})
+		}
+	case []*sp.WebhookItem:
+		t.Headers = []string{"ID", "NAME", "TARGET", "EVENTS", "AUTH"}
+		for _, wh := range items {
+			t.Rows = append(t.Rows, []string{wh.ID, wh.Name, wh.Target, strings.Join(wh.Events, ","), wh.AuthType})
+		}
+	case []*sp.SuppressionEntry:
+		t.Headers = []string{"RECIPIENT", "TYPE", "SOURCE", "DESCRIPTION", "UPDATED"}
+		for _, e := range items {
+			recipient := e.Recipient
+			if recipient == "" {
+				recipient = e.Email
+			}
+			t.Rows = append(t.Rows, []string{recipient, strings.Join(e.Types(), ","), e.Source,
+				oneLine(e.Description), e.Updated})
+		}
+	default:
+		return nil, fmt.Errorf("no table layout for %T, use json or yaml output", v)
+	}
+	return t, nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// oneLine keeps free text from breaking the table's rows and columns.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// YAML writes v to w as YAML, with fields named and ordered as they are in JSON.
+func YAML(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(unwrap(v))
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	node, err := decodeNode(dec)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	writeYAML(&out, node, 0, false)
+	_, err = w.Write(out.Bytes())
+	return err
+}
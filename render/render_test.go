@@ -0,0 +1,117 @@
+package render_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/render"
+)
+
+var templates = []sp.Template{
+	{ID: "welcome", Name: "Welcome", Published: true, LastUpdate: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+	{ID: "receipt-v2", Name: "Receipt: order #2", HasDraft: true},
+}
+
+func TestTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render.Write(&buf, render.FormatTable, templates); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"ID          NAME               PUBLISHED  DRAFT  LAST UPDATED\n" +
+		"welcome     Welcome            yes        no     2026-03-01T12:00:00Z\n" +
+		"receipt-v2  Receipt: order #2  no         yes    \n"
+	if buf.String() != want {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	wrapper := &sp.SuppressionListWrapper{Results: []*sp.SuppressionEntry{
+		{Recipient: "a@example.com", Type: sp.SuppressionTransactional, Description: "line\none"},
+	}}
+	if err := render.Write(&buf, "", wrapper); err != nil {
+		t.Fatal(err)
+	}
+	want = "" +
+		"RECIPIENT      TYPE           SOURCE  DESCRIPTION  UPDATED\n" +
+		"a@example.com  transactional          line one     \n"
+	if buf.String() != want {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+
+	if err := render.Write(&buf, render.FormatTable, map[string]int{}); err == nil {
+		t.Error("expected an error for a type without a table layout")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	subaccounts := []sp.Subaccount{{ID: 7, Name: "eu", Grants: []string{"smtp/inject"}}}
+	if err := render.Write(&buf, render.FormatJSON, subaccounts); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "subaccount_id": 7,
+    "name": "eu",
+    "key_grants": [
+      "smtp/inject"
+    ]
+  }
+]
+`
+	if buf.String() != want {
+		t.Errorf("unexpected JSON:\n%s", buf.String())
+	}
+}
+
+func TestYAML(t *testing.T) {
+	var buf bytes.Buffer
+	webhooks := &sp.WebhookListWrapper{Results: []*sp.WebhookItem{
+		{ID: "w1", Name: "yes", Target: "https://example.com/hook", Events: []string{"bounce", "delivery"}},
+		{ID: "w2", Name: "", Events: []string{}},
+	}}
+	if err := render.Write(&buf, render.FormatYAML, webhooks); err != nil {
+		t.Fatal(err)
+	}
+	want := `- id: w1
+  name: "yes"
+  target: "https://example.com/hook"
+  events:
+    - bounce
+    - delivery
+  auth_request_details:
+    body: {}
+  auth_credentials: {}
+- id: w2
+  auth_request_details:
+    body: {}
+  auth_credentials: {}
+`
+	if buf.String() != want {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := render.YAML(&buf, map[string]interface{}{"num": 1.5, "s": "", "x": nil, "l": [][]int{{1}}}); err != nil {
+		t.Fatal(err)
+	}
+	if want = "l:\n  - - 1\nnum: 1.5\ns: \"\"\nx: null\n"; buf.String() != want {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+}
+
+func TestFormatFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	format := render.FormatTable
+	fs.Var(&format, "output", "")
+	if err := fs.Parse([]string{"-output", "YAML"}); err != nil || format != render.FormatYAML {
+		t.Errorf("expected yaml, got %q, %v", format, err)
+	}
+	if err := format.Set("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
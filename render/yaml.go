@@ -0,0 +1,133 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// object is a JSON object with its keys kept in order.
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+// decodeNode reads the next JSON value from dec as an object, []interface{}, string,
+// json.Number, bool or nil.
+func decodeNode(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key.(string), value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// writeYAML writes node as a block at the given indent. If inline is set, the first line's
+// indent has already been written, after a "- ".
+func writeYAML(b *bytes.Buffer, node interface{}, indent int, inline bool) {
+	pad := strings.Repeat(" ", indent)
+	switch n := node.(type) {
+	case object:
+		if len(n) == 0 {
+			b.WriteString("{}\n")
+			return
+		}
+		for i, m := range n {
+			if i > 0 || !inline {
+				b.WriteString(pad)
+			}
+			b.WriteString(yamlScalar(m.key) + ":")
+			if isBlock(m.value) {
+				b.WriteString("\n")
+				writeYAML(b, m.value, indent+2, false)
+			} else {
+				b.WriteString(" ")
+				writeYAML(b, m.value, indent+2, true)
+			}
+		}
+	case []interface{}:
+		if len(n) == 0 {
+			b.WriteString("[]\n")
+			return
+		}
+		for i, item := range n {
+			if i > 0 || !inline {
+				b.WriteString(pad)
+			}
+			b.WriteString("- ")
+			writeYAML(b, item, indent+2, true)
+		}
+	default:
+		b.WriteString(yamlScalar(n) + "\n")
+	}
+}
+
+// isBlock reports whether node is written on the lines following its key.
+func isBlock(node interface{}) bool {
+	switch n := node.(type) {
+	case object:
+		return len(n) > 0
+	case []interface{}:
+		return len(n) > 0
+	}
+	return false
+}
+
+var plainYAML = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_./@+-]*( [A-Za-z0-9_./@+-]+)*$`)
+
+var yamlKeywords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+func yamlScalar(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(s)
+	case json.Number:
+		return s.String()
+	case string:
+		if plainYAML.MatchString(s) && !yamlKeywords[strings.ToLower(s)] {
+			return s
+		}
+		// JSON strings are valid YAML double-quoted scalars
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,220 @@
+package gosparkpost
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Report is a table of metrics for stakeholders, to be rendered by a ReportWriter.
+// Cells may be strings, ints, float64s or bools; anything else is formatted with fmt.Sprint.
+type Report struct {
+	Title   string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// ReportWriter renders a Report, for example to a file for a weekly cron job.
+type ReportWriter interface {
+	WriteReport(w io.Writer, r *Report) error
+}
+
+// ReportWriterFunc allows a function to be used as a ReportWriter.
+type ReportWriterFunc func(w io.Writer, r *Report) error
+
+func (f ReportWriterFunc) WriteReport(w io.Writer, r *Report) error {
+	return f(w, r)
+}
+
+// CSVReportWriter renders a Report as CSV, with the column names as its first row.
+type CSVReportWriter struct{}
+
+func (CSVReportWriter) WriteReport(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.Columns); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = formatReportCell(cell)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatReportCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// XLSXReportWriter renders a Report as a single-sheet Excel workbook, named after its Title.
+// It only writes values, with no styling; a ReportWriter wrapping a full spreadsheet library
+// can be used instead where formatting matters.
+type XLSXReportWriter struct{}
+
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+func (XLSXReportWriter) WriteReport(w io.Writer, r *Report) error {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create("xl/workbook.xml")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, `%s<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		xml.Header, xmlEscape(xlsxSheetName(r.Title)))
+
+	if f, err = zw.Create("xl/worksheets/sheet1.xml"); err != nil {
+		return err
+	}
+	var sheet bytes.Buffer
+	sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	header := make([]interface{}, len(r.Columns))
+	for i, c := range r.Columns {
+		header[i] = c
+	}
+	writeXLSXRow(&sheet, 1, header)
+	for i, row := range r.Rows {
+		writeXLSXRow(&sheet, i+2, row)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	if _, err = io.WriteString(f, sheet.String()); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func writeXLSXRow(b *bytes.Buffer, n int, row []interface{}) {
+	fmt.Fprintf(b, `<row r="%d">`, n)
+	for i, cell := range row {
+		ref := xlsxColumn(i) + strconv.Itoa(n)
+		switch v := cell.(type) {
+		case nil:
+		case int, int64, float64:
+			fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, formatReportCell(v))
+		case bool:
+			val := 0
+			if v {
+				val = 1
+			}
+			fmt.Fprintf(b, `<c r="%s" t="b"><v>%d</v></c>`, ref, val)
+		default:
+			fmt.Fprintf(b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xmlEscape(formatReportCell(v)))
+		}
+	}
+	b.WriteString(`</row>`)
+}
+
+// xlsxColumn returns the spreadsheet column name (A, B, ..., Z, AA, ...) for the zero-based index i.
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxSheetName makes title fit Excel's rules for sheet names.
+func xlsxSheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" {
+		return "Report"
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// CampaignMetricsReport tabulates deliverability metrics grouped by campaign,
+// as returned by DeliverabilityMetricsBy("campaign", ...).
+func CampaignMetricsReport(title string, items []*DeliverabilityMetricItem) *Report {
+	r := &Report{
+		Title: title,
+		Columns: []string{"Campaign", "Targeted", "Injected", "Delivered", "Bounced", "Hard Bounced",
+			"Spam Complaints", "Unique Opens", "Unique Clicks", "Delivery Rate", "Open Rate", "Click Rate"},
+	}
+	for _, m := range items {
+		r.Rows = append(r.Rows, []interface{}{
+			m.CampaignId, m.CountTargeted, m.CountInjected, m.CountDelivered, m.CountBounce, m.CountHardBounce,
+			m.CountSpamComplaint, m.CountUniqueConfirmedOpened, m.CountUniqueClicked,
+			rate(m.CountDelivered, m.CountInjected),
+			rate(m.CountUniqueConfirmedOpened, m.CountDelivered),
+			rate(m.CountUniqueClicked, m.CountDelivered),
+		})
+	}
+	return r
+}
+
+// DomainHealthReport tabulates the results of DomainReports. The health score is left blank
+// for domains without one.
+func DomainHealthReport(title string, reports []DomainReport) *Report {
+	r := &Report{
+		Title: title,
+		Columns: []string{"Sending Domain", "Verified", "Sent", "Bounce Rate", "Complaint Rate",
+			"Health Score", "Blocklist Incidents"},
+	}
+	for _, d := range reports {
+		var score interface{}
+		if d.HasHealthScore {
+			score = d.HealthScore
+		}
+		r.Rows = append(r.Rows, []interface{}{
+			d.Domain, d.Verified, d.Sent, d.BounceRate, d.ComplaintRate, score, len(d.Incidents),
+		})
+	}
+	return r
+}
@@ -0,0 +1,74 @@
+package gosparkpost_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestCSVReportWriter(t *testing.T) {
+	r := sp.CampaignMetricsReport("Weekly", []*sp.DeliverabilityMetricItem{
+		{CampaignId: "spring, sale", CountTargeted: 10, CountInjected: 10, CountDelivered: 8, CountBounce: 2,
+			CountUniqueConfirmedOpened: 4, CountUniqueClicked: 2},
+	})
+	var buf bytes.Buffer
+	if err := (sp.CSVReportWriter{}).WriteReport(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	want := "Campaign,Targeted,Injected,Delivered,Bounced,Hard Bounced,Spam Complaints,Unique Opens,Unique Clicks,Delivery Rate,Open Rate,Click Rate\n" +
+		"\"spring, sale\",10,10,8,2,0,0,4,2,0.8,0.5,0.25\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestXLSXReportWriter(t *testing.T) {
+	r := sp.DomainHealthReport("Domains: week 12/2026", []sp.DomainReport{
+		{Domain: "a&b.example.com", Verified: true, Sent: 100, BounceRate: 0.02, HealthScore: 0.9, HasHealthScore: true},
+		{Domain: "c.example.com", Sent: 5, Incidents: []sp.BlocklistIncident{{}}},
+	})
+	var buf bytes.Buffer
+	if err := (sp.XLSXReportWriter{}).WriteReport(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+		var v interface{}
+		if err = xml.Unmarshal(b, &v); err != nil {
+			t.Errorf("%s isn't valid XML: %s", f.Name, err)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Domains- week 12-2026"`) {
+		t.Errorf("unexpected workbook %s", parts["xl/workbook.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, cell := range []string{
+		`<c r="A1" t="inlineStr"><is><t>Sending Domain</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t>a&amp;b.example.com</t></is></c>`,
+		`<c r="B2" t="b"><v>1</v></c>`,
+		`<c r="D2"><v>0.02</v></c>`,
+		`<c r="F2"><v>0.9</v></c>`,
+		`<c r="E3"><v>0</v></c><c r="G3"><v>1</v></c>`,
+	} {
+		if !strings.Contains(sheet, cell) {
+			t.Errorf("expected sheet to contain %s", cell)
+		}
+	}
+}
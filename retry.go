@@ -0,0 +1,105 @@
+package gosparkpost
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests are retried after network errors and transient responses.
+// Set it as Config.Retry to apply it to every request the Client makes.
+//
+// A POST which fails with a 5xx or a dropped connection may have been acted on by the API,
+// so to avoid, for example, sending a Transmission twice, POSTs are only retried after a 429
+// unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts includes the first. It defaults to 3.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, which doubles for each one after,
+	// up to MaxDelay. They default to half a second and 10 seconds. A delay requested by
+	// a Retry-After header is used instead, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// StatusCodes which are retried. They default to 429, 500, 502, 503 and 504.
+	StatusCodes []int
+	// RetryNonIdempotent, if set, retries POSTs like other requests.
+	RetryNonIdempotent bool
+}
+
+var defaultRetryStatusCodes = []int{429, 500, 502, 503, 504}
+
+// retryDelay returns how long to wait before retrying a request, and whether it should be.
+func (p *RetryPolicy) retryDelay(attempt int, method string, res *Response, err error) (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	max := p.MaxAttempts
+	if max <= 0 {
+		max = 3
+	}
+	if attempt >= max {
+		return 0, false
+	}
+
+	idempotent := method != "POST" && method != "PATCH"
+	if err != nil {
+		// only errors making the request, not those from a Signer for example
+		if _, ok := err.(*url.Error); !ok || !(idempotent || p.RetryNonIdempotent) {
+			return 0, false
+		}
+	} else {
+		code := res.HTTP.StatusCode
+		if code != 429 && !idempotent && !p.RetryNonIdempotent {
+			return 0, false
+		}
+		codes := p.StatusCodes
+		if codes == nil {
+			codes = defaultRetryStatusCodes
+		}
+		retryable := false
+		for _, c := range codes {
+			retryable = retryable || c == code
+		}
+		if !retryable {
+			return 0, false
+		}
+	}
+
+	base, maxDelay := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = 10 * time.Second
+	}
+	if res != nil && res.HTTP != nil {
+		if secs, err := strconv.Atoi(res.HTTP.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return minDuration(time.Duration(secs)*time.Second, maxDelay), true
+		}
+	}
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = minDuration(delay, maxDelay)
+	// jitter, so clients which failed together don't retry together
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)), true
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// discardBody reads and closes the body of a response which won't be returned.
+func discardBody(res *Response) {
+	if res != nil && res.HTTP != nil && res.HTTP.Body != nil {
+		io.Copy(ioutil.Discard, res.HTTP.Body)
+		res.HTTP.Body.Close()
+	}
+}
@@ -0,0 +1,73 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestRetry(t *testing.T) {
+	for idx, test := range []struct {
+		method string
+		status int
+		policy sp.RetryPolicy
+		calls  int32
+	}{
+		{"GET", 503, sp.RetryPolicy{}, 3},
+		{"GET", 503, sp.RetryPolicy{MaxAttempts: 5}, 5},
+		{"GET", 400, sp.RetryPolicy{}, 1},
+		{"GET", 400, sp.RetryPolicy{StatusCodes: []int{400}}, 3},
+		{"POST", 503, sp.RetryPolicy{}, 1},
+		{"POST", 429, sp.RetryPolicy{}, 3},
+		{"POST", 503, sp.RetryPolicy{RetryNonIdempotent: true}, 3},
+		{"DELETE", 502, sp.RetryPolicy{}, 3},
+	} {
+		var calls int32
+		policy := test.policy
+		policy.BaseDelay = time.Millisecond
+		cfg := &sp.Config{ApiKey: "testkey", Retry: &policy}
+		client, done := newTestClient(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(test.status)
+			w.Write([]byte(`{"errors":[{"message":"nope"}]}`))
+		})
+		res, err := client.DoRequest(test.method, client.Config.BaseUrl+"/api/v1/thing", []byte("{}"))
+		done()
+		if err != nil {
+			t.Errorf("Retry[%d] => err %v", idx, err)
+			continue
+		}
+		if res.HTTP.StatusCode != test.status {
+			t.Errorf("Retry[%d] => status %d, want %d", idx, res.HTTP.StatusCode, test.status)
+		}
+		if calls != test.calls {
+			t.Errorf("Retry[%d] => %d calls, want %d", idx, calls, test.calls)
+		}
+	}
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	var calls int32
+	cfg := &sp.Config{ApiKey: "testkey", Retry: &sp.RetryPolicy{BaseDelay: time.Millisecond}}
+	client, done := newTestClient(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"results":[]}`))
+	})
+	defer done()
+
+	if _, _, err := client.Templates(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
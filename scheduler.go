@@ -0,0 +1,342 @@
+package gosparkpost
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule returns when something recurring next happens.
+type Schedule interface {
+	// Next returns the first time after the provided one, or the zero time if there isn't one.
+	Next(after time.Time) time.Time
+}
+
+// Every is a Schedule which happens at a fixed interval, aligned to the zero time.
+type Every time.Duration
+
+func (e Every) Next(after time.Time) time.Time {
+	if e <= 0 {
+		return time.Time{}
+	}
+	return after.Truncate(time.Duration(e)).Add(time.Duration(e))
+}
+
+// CronSchedule is a Schedule parsed from a crontab expression by ParseCron.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// as in cron, days match either field if both are restricted
+	domAny, dowAny bool
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a standard five field crontab expression ("minute hour day-of-month
+// month day-of-week"), where each field is *, a number, a range like 1-5 or a list like
+// 1,15, optionally with a step like */15. Sunday is 0 or 7. Times are in the location of
+// the time passed to Next.
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("Cron expression [%s] must have %d fields", spec, len(cronFields))
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s in cron expression [%s]: %s", cronFields[i].name, spec, err)
+		}
+		bits[i] = b
+	}
+	// Sunday may be written as 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &CronSchedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		// as in Vixie cron, a field starting with * (including */2) doesn't restrict the day
+		domAny: strings.HasPrefix(fields[2], "*"), dowAny: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("bad step [%s]", item[i+1:])
+			}
+			rng = item[:i]
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			parts := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(parts[0]); err != nil {
+				return 0, fmt.Errorf("bad value [%s]", parts[0])
+			}
+			hi = lo
+			if len(parts) == 2 {
+				if hi, err = strconv.Atoi(parts[1]); err != nil {
+					return 0, fmt.Errorf("bad value [%s]", parts[1])
+				}
+			} else if step > 1 {
+				// 5/15 means from 5 onwards
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("[%s] is out of range %d-%d", rng, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first matching minute after the provided time. It gives up, returning the
+// zero time, if there's none within five years, for example for "0 0 30 2 *".
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// RunStore remembers when each recurring campaign last ran.
+// Implementations backed by shared storage keep the bookkeeping across restarts.
+type RunStore interface {
+	LastRun(name string) (at time.Time, ok bool, err error)
+	SetLastRun(name string, at time.Time) error
+}
+
+// MemoryRunStore is a RunStore that lives in process memory.
+type MemoryRunStore struct {
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+func (m *MemoryRunStore) LastRun(name string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	at, ok := m.runs[name]
+	return at, ok, nil
+}
+
+func (m *MemoryRunStore) SetLastRun(name string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.runs == nil {
+		m.runs = map[string]time.Time{}
+	}
+	m.runs[name] = at
+	return nil
+}
+
+// RecurringCampaign is a template sent on a Schedule.
+type RecurringCampaign struct {
+	// Name identifies the campaign in the RunStore, and is the campaign id of its Transmissions.
+	Name       string
+	Schedule   Schedule
+	TemplateID string
+	// Recipients returns who to send the run scheduled at the provided time to:
+	// inline Recipients, or a stored recipient list like map[string]string{"list_id": "weekly"}.
+	Recipients       func(ctx context.Context, at time.Time) (interface{}, error)
+	SubstitutionData interface{}
+	Options          *TxOptions
+}
+
+// Scheduler sends RecurringCampaigns when they're due. A run which is still going when the
+// campaign is next due isn't overlapped; that run is skipped. If runs were missed, for example
+// while the process was down, only one is made to catch up.
+//
+// The last run is recorded before the Transmission is sent, so a send which fails, or a crash
+// part way through, isn't repeated: each run is sent at most once.
+type Scheduler struct {
+	Client    *Client
+	Campaigns []*RecurringCampaign
+	// Store defaults to a MemoryRunStore.
+	Store RunStore
+	// Tick is how often Run checks for due campaigns. Defaults to a minute.
+	Tick time.Duration
+	// OnRun, if set, is passed the outcome of each run, and errors reading or writing the Store.
+	OnRun func(c *RecurringCampaign, at time.Time, id string, err error)
+
+	runner
+	mu      sync.Mutex
+	running map[string]bool
+	started time.Time
+	wg      sync.WaitGroup
+}
+
+var _ Component = &Scheduler{}
+
+func (s *Scheduler) store() RunStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Store == nil {
+		s.Store = &MemoryRunStore{}
+	}
+	return s.Store
+}
+
+// Run checks for due campaigns every Tick until ctx is done, then waits for runs in progress.
+func (s *Scheduler) Run(ctx context.Context) error {
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Minute
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		s.RunDue(ctx, time.Now())
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			s.wg.Wait()
+			return ctx.Err()
+		}
+	}
+}
+
+// Start calls Run in the background until Stop is called or ctx is done.
+func (s *Scheduler) Start(ctx context.Context) error {
+	return s.start(ctx, func(ctx context.Context) {
+		s.Run(ctx)
+	})
+}
+
+// Stop stops checking for due campaigns and waits for runs in progress.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	return s.stop(ctx)
+}
+
+// Health reports whether the Scheduler was started and is running, and the last error
+// passed to OnRun.
+func (s *Scheduler) Health() Health {
+	return s.runner.report()
+}
+
+// RunDue starts every campaign which is due at now, without waiting for them to finish.
+// Campaigns which have never run are due once their Schedule comes round after the first
+// call to RunDue, rather than immediately.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) {
+	store := s.store()
+	s.mu.Lock()
+	if s.started.IsZero() {
+		s.started = now
+	}
+	started := s.started
+	s.mu.Unlock()
+
+	for _, c := range s.Campaigns {
+		last, ok, err := store.LastRun(c.Name)
+		if err != nil {
+			s.report(c, now, "", err)
+			continue
+		} else if !ok {
+			last = started
+		}
+		next := c.Schedule.Next(last)
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+
+		s.mu.Lock()
+		if s.running[c.Name] {
+			s.mu.Unlock()
+			continue
+		}
+		if s.running == nil {
+			s.running = map[string]bool{}
+		}
+		s.running[c.Name] = true
+		s.mu.Unlock()
+
+		if err = store.SetLastRun(c.Name, now); err != nil {
+			s.finish(c)
+			s.report(c, now, "", err)
+			continue
+		}
+		s.wg.Add(1)
+		go func(c *RecurringCampaign) {
+			defer s.wg.Done()
+			defer s.finish(c)
+			id, err := s.send(ctx, c, next)
+			s.report(c, next, id, err)
+		}(c)
+	}
+}
+
+// Wait blocks until every run in progress is finished.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) send(ctx context.Context, c *RecurringCampaign, at time.Time) (string, error) {
+	recips, err := c.Recipients(ctx, at)
+	if err != nil {
+		return "", err
+	}
+	id, _, err := s.Client.Send(&Transmission{
+		CampaignID:       c.Name,
+		Recipients:       recips,
+		Content:          map[string]string{"template_id": c.TemplateID},
+		SubstitutionData: c.SubstitutionData,
+		Options:          c.Options,
+	})
+	return id, err
+}
+
+func (s *Scheduler) finish(c *RecurringCampaign) {
+	s.mu.Lock()
+	delete(s.running, c.Name)
+	s.mu.Unlock()
+}
+
+func (s *Scheduler) report(c *RecurringCampaign, at time.Time, id string, err error) {
+	if err != nil {
+		s.fail(err)
+	}
+	if s.OnRun != nil {
+		s.OnRun(c, at, id, err)
+	}
+}
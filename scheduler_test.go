@@ -0,0 +1,130 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestParseCron(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2026, 3, 4, 10, 7, 30, 0, time.UTC)
+	for idx, test := range []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{"30 8 * * 7", time.Date(2026, 3, 8, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 6 *", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		// either day field matches when both are set
+		{"0 0 31 * 5", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		// but a stepped * doesn't count as set
+		{"0 0 */2 * 5", time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		s, err := sp.ParseCron(test.spec)
+		if err != nil {
+			t.Errorf("ParseCron[%d] => err %v", idx, err)
+			continue
+		}
+		if next := s.Next(from); !next.Equal(test.next) {
+			t.Errorf("ParseCron[%d] %q => next %s, want %s", idx, test.spec, next, test.next)
+		}
+	}
+
+	for _, bad := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := sp.ParseCron(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestParseCronLocalHours(t *testing.T) {
+	// Kolkata is UTC+5:30, so its hours don't start on UTC hours
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("no zoneinfo: %v", err)
+	}
+	s, err := sp.ParseCron("0 11 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 3, 4, 11, 0, 0, 0, loc)
+	if next := s.Next(time.Date(2026, 3, 4, 10, 45, 0, 0, loc)); !next.Equal(want) {
+		t.Errorf("next %s, want %s", next, want)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	var sent []map[string]interface{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var tx map[string]interface{}
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &tx)
+		sent = append(sent, tx)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1"}}`))
+	})
+	defer done()
+
+	release := make(chan struct{})
+	var fetches int32
+	campaign := &sp.RecurringCampaign{
+		Name:       "weekly-digest",
+		Schedule:   sp.Every(time.Hour),
+		TemplateID: "digest",
+		Recipients: func(ctx context.Context, at time.Time) (interface{}, error) {
+			atomic.AddInt32(&fetches, 1)
+			<-release
+			return map[string]string{"list_id": "weekly"}, nil
+		},
+	}
+	var runs []time.Time
+	store := &sp.MemoryRunStore{}
+	s := &sp.Scheduler{
+		Client:    client,
+		Campaigns: []*sp.RecurringCampaign{campaign},
+		Store:     store,
+		OnRun: func(c *sp.RecurringCampaign, at time.Time, id string, err error) {
+			if err != nil || id != "1" {
+				t.Errorf("unexpected run result %q, %v", id, err)
+			}
+			runs = append(runs, at)
+		},
+	}
+
+	start := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	ctx := context.Background()
+	s.RunDue(ctx, start)
+	s.RunDue(ctx, start.Add(31*time.Minute))
+	// still running when it's next due, so that run is skipped
+	s.RunDue(ctx, start.Add(95*time.Minute))
+	close(release)
+	s.Wait()
+
+	if fetches != 1 || len(runs) != 1 || !runs[0].Equal(start.Add(30*time.Minute)) {
+		t.Fatalf("expected one run at 11:00, got %d fetches, runs %v", fetches, runs)
+	}
+	if last, ok, _ := store.LastRun("weekly-digest"); !ok || !last.Equal(start.Add(31*time.Minute)) {
+		t.Errorf("unexpected last run %s", last)
+	}
+
+	// missed runs are caught up with a single run
+	s.RunDue(ctx, start.Add(10*time.Hour))
+	s.Wait()
+	if len(runs) != 2 || len(sent) != 2 {
+		t.Fatalf("expected a second run, got %v", runs)
+	}
+	if sent[1]["campaign_id"] != "weekly-digest" || sent[1]["content"].(map[string]interface{})["template_id"] != "digest" {
+		t.Errorf("unexpected transmission %v", sent[1])
+	}
+}
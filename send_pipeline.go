@@ -0,0 +1,118 @@
+package gosparkpost
+
+// outgoing is a Transmission which has been through the send pipeline, with what's needed
+// to record the send once it's made.
+type outgoing struct {
+	c      *Client
+	t      *Transmission
+	capped []Recipient
+	hash   string
+}
+
+// sendPipeline runs the stages every Transmission goes through before it's sent, whether by
+// Send or by a PreparedTransmission: the Environment's Overlay, the BouncePolicy, FrequencyCap,
+// Archive and Sink, escaping of Recipients' substitution data, the InjectionPolicy and the
+// DuplicateGuard, which reserves the payload. t must already be valid, and isn't modified.
+// The result's finish method must be called once the send has been attempted, or abandoned.
+//
+// A proof, from SendProof, skips the Overlay, the FrequencyCap and the DuplicateGuard, which
+// are for real sends: proofs go to whoever asked for them, unless Config.Sink is set.
+func (c *Client) sendPipeline(t *Transmission, proof bool) (*outgoing, error) {
+	o := &outgoing{c: c}
+	var err error
+	if !proof {
+		overlay, err := c.Config.overlay()
+		if err != nil {
+			return nil, err
+		} else if overlay != nil {
+			if t, err = overlay.applyOptions(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// each stage replaces the Recipients of a copy, so the caller's Transmission isn't modified
+	withRecipients := func(recips []Recipient) {
+		tx := *t
+		tx.Recipients = recips
+		t = &tx
+	}
+
+	if c.BouncePolicy != nil {
+		allowed, err := c.BouncePolicy.Filter(t.Recipients)
+		if err != nil {
+			return nil, err
+		} else if allowed != nil {
+			withRecipients(allowed)
+		}
+	}
+	if c.FrequencyCap != nil && !proof && !t.transactional() {
+		capped, err := c.FrequencyCap.Filter(t.Recipients)
+		if err != nil {
+			return nil, err
+		} else if capped != nil {
+			withRecipients(capped)
+			o.capped = capped
+		}
+	}
+	if c.Archive != nil {
+		recips, err := c.Archive.ArchiveRecipients(t.Recipients)
+		if err != nil {
+			return nil, err
+		}
+		withRecipients(recips)
+	}
+	sink := c.Config.Sink
+	if !proof {
+		sink, _ = c.sinkDomain()
+	}
+	if sink != "" {
+		recips, err := SinkRecipients(t.Recipients, sink)
+		if err != nil {
+			return nil, err
+		}
+		withRecipients(recips)
+	}
+	if c.Config.EscapeSubstitutionData {
+		recips, err := inlineRecipients(t.Recipients)
+		if err != nil {
+			return nil, err
+		} else if recips != nil {
+			if recips, err = escapeRecipients(recips); err != nil {
+				return nil, err
+			}
+			withRecipients(recips)
+		}
+	}
+
+	if c.Injection != nil {
+		if err = c.Injection.check(t); err != nil {
+			return nil, err
+		}
+	}
+	if c.Dedupe != nil && !proof {
+		if o.hash, err = c.Dedupe.Reserve(t); err != nil {
+			return nil, err
+		}
+	}
+	o.t = t
+	return o, nil
+}
+
+// finish releases the DuplicateGuard's reservation, and records a send which succeeded with
+// it and the FrequencyCap. It returns err, the result of the send, or else any error recording it.
+func (o *outgoing) finish(id string, err error) error {
+	sent := err == nil && id != ""
+	if o.c.Dedupe != nil && o.hash != "" {
+		if rerr := o.c.Dedupe.Release(o.hash, sent); rerr != nil {
+			return rerr
+		}
+	}
+	if !sent {
+		return err
+	}
+	if o.capped != nil {
+		err = o.c.FrequencyCap.Record(o.capped)
+	}
+	return err
+}
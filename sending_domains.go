@@ -0,0 +1,394 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/sending-domains/
+var sendingDomainsPathFormat = "/api/v%d/sending-domains"
+
+// SendingDomain is a domain which may be used in the From address of messages.
+type SendingDomain struct {
+	Domain         string `json:"domain,omitempty"`
+	TrackingDomain string `json:"tracking_domain,omitempty"`
+	// DKIM is the key pair used to sign messages. Leave it nil, and set GenerateDKIM,
+	// to have SparkPost create one.
+	DKIM *DKIM `json:"dkim,omitempty"`
+	// GenerateDKIM and DKIMKeyLength are only used on create.
+	GenerateDKIM  *bool `json:"generate_dkim,omitempty"`
+	DKIMKeyLength int   `json:"dkim_key_length,omitempty"`
+
+	// SharedWithSubaccounts and IsDefaultBounceDomain are pointers, so an update can turn
+	// them off; nil leaves them as they are.
+	SharedWithSubaccounts *bool `json:"shared_with_subaccounts,omitempty"`
+	IsDefaultBounceDomain *bool `json:"is_default_bounce_domain,omitempty"`
+
+	// Status is set by SparkPost, and ignored on create and update.
+	Status *SendingDomainStatus `json:"status,omitempty"`
+}
+
+// DKIM holds the key used to sign messages from a SendingDomain.
+type DKIM struct {
+	// Private is only ever sent, never returned.
+	Private  string `json:"private,omitempty"`
+	Public   string `json:"public,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	// Headers is a colon-separated list of headers to sign.
+	Headers       string `json:"headers,omitempty"`
+	SigningDomain string `json:"signing_domain,omitempty"`
+}
+
+// SendingDomainStatus reports which checks a SendingDomain has passed. Each status is
+// one of "valid", "invalid", "unverified" or "pending".
+type SendingDomainStatus struct {
+	OwnershipVerified         bool   `json:"ownership_verified"`
+	DKIMStatus                string `json:"dkim_status,omitempty"`
+	CNAMEStatus               string `json:"cname_status,omitempty"`
+	MXStatus                  string `json:"mx_status,omitempty"`
+	SPFStatus                 string `json:"spf_status,omitempty"`
+	ComplianceStatus          string `json:"compliance_status,omitempty"`
+	AbuseAtStatus             string `json:"abuse_at_status,omitempty"`
+	PostmasterAtStatus        string `json:"postmaster_at_status,omitempty"`
+	VerificationMailboxStatus string `json:"verification_mailbox_status,omitempty"`
+
+	// DNS is only returned by SendingDomainVerify.
+	DNS *SendingDomainDNS `json:"dns,omitempty"`
+}
+
+// SendingDomainDNS holds the records SparkPost expects, and any problems it found with them.
+type SendingDomainDNS struct {
+	DKIMRecord string `json:"dkim_record,omitempty"`
+	SPFRecord  string `json:"spf_record,omitempty"`
+	DKIMError  string `json:"dkim_error,omitempty"`
+	SPFError   string `json:"spf_error,omitempty"`
+	CNAMEError string `json:"cname_error,omitempty"`
+	MXError    string `json:"mx_error,omitempty"`
+}
+
+// SendingDomainVerification selects the checks SendingDomainVerify runs. Setting
+// AbuseAt or PostmasterAt sends a token to that mailbox; the token is then passed back
+// in AbuseAtToken or PostmasterAtToken to complete verification.
+type SendingDomainVerification struct {
+	DKIM         bool `json:"dkim_verify,omitempty"`
+	SPF          bool `json:"spf_verify,omitempty"`
+	CNAME        bool `json:"cname_verify,omitempty"`
+	AbuseAt      bool `json:"abuse_at_verify,omitempty"`
+	PostmasterAt bool `json:"postmaster_at_verify,omitempty"`
+
+	AbuseAtToken      string `json:"abuse_at_token,omitempty"`
+	PostmasterAtToken string `json:"postmaster_at_token,omitempty"`
+}
+
+func (d *SendingDomain) String() string {
+	s := d.Domain
+	if d.Status != nil {
+		s += fmt.Sprintf(" (dkim %s, spf %s, cname %s)", d.Status.DKIMStatus, d.Status.SPFStatus, d.Status.CNAMEStatus)
+	}
+	return s
+}
+
+// writable returns a copy of the SendingDomain without the fields SparkPost sets itself.
+func (d *SendingDomain) writable() SendingDomain {
+	tmp := *d
+	tmp.Status = nil
+	return tmp
+}
+
+// SendingDomainCreate creates the provided SendingDomain, returning the DKIM details
+// SparkPost holds for it, including the generated public key if GenerateDKIM was set.
+func (c *Client) SendingDomainCreate(d *SendingDomain) (dkim *DKIM, res *Response, err error) {
+	if d == nil {
+		err = fmt.Errorf("Create called with nil SendingDomain")
+		return
+	} else if d.Domain == "" {
+		err = fmt.Errorf("SendingDomain requires a non-empty Domain")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(d.writable())
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(sendingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return
+		}
+		var created struct {
+			Results struct {
+				DKIM *DKIM `json:"dkim"`
+			} `json:"results"`
+		}
+		if err = json.Unmarshal(body, &created); err != nil {
+			return
+		}
+		dkim = created.Results.DKIM
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+	if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("SendingDomain", "create")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
+
+// SendingDomains returns all SendingDomains, with their Status.
+func (c *Client) SendingDomains() ([]SendingDomain, *Response, error) {
+	path := fmt.Sprintf(sendingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		dlist := map[string][]SendingDomain{}
+		if err = json.Unmarshal(body, &dlist); err != nil {
+			return nil, res, err
+		} else if list, ok := dlist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to SendingDomain list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("SendingDomain", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// SendingDomain retrieves the SendingDomain with the specified name, including its DKIM
+// public key and Status.
+func (c *Client) SendingDomain(domain string) (*SendingDomain, *Response, error) {
+	if domain == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank domain")
+	}
+
+	path := fmt.Sprintf(sendingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, domain)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*SendingDomain{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if d, ok := tmp["results"]; ok && d != nil {
+			// the domain isn't repeated in the results
+			d.Domain = domain
+			return d, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to SendingDomain retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("SendingDomain", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// SendingDomainUpdate replaces the settings of the SendingDomain with the Domain of the one provided.
+func (c *Client) SendingDomainUpdate(d *SendingDomain) (res *Response, err error) {
+	if d == nil {
+		err = fmt.Errorf("Update called with nil SendingDomain")
+		return
+	} else if d.Domain == "" {
+		err = fmt.Errorf("Update called with blank domain")
+		return
+	}
+
+	tmp := d.writable()
+	tmp.Domain = ""
+	tmp.GenerateDKIM = nil
+	tmp.DKIMKeyLength = 0
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(sendingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, d.Domain)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("SendingDomain", "update")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	return
+}
+
+// SendingDomainDelete removes the SendingDomain with the specified name.
+func (c *Client) SendingDomainDelete(domain string) (res *Response, err error) {
+	if domain == "" {
+		err = fmt.Errorf("Delete called with blank domain")
+		return
+	}
+
+	path := fmt.Sprintf(sendingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, domain)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	// success is a 204, with no body
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("SendingDomain", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
+
+// SendingDomainVerify runs the requested checks against the SendingDomain with the
+// specified name, returning its updated status and the DNS records SparkPost expects.
+func (c *Client) SendingDomainVerify(domain string, v *SendingDomainVerification) (*SendingDomainStatus, *Response, error) {
+	if domain == "" {
+		return nil, nil, fmt.Errorf("Verify called with blank domain")
+	} else if v == nil {
+		return nil, nil, fmt.Errorf("Verify called with nil SendingDomainVerification")
+	}
+
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf(sendingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s/verify", c.Config.BaseUrl, path, domain)
+	res, err := c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*SendingDomainStatus{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if status, ok := tmp["results"]; ok && status != nil {
+			return status, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to SendingDomain verify")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("SendingDomain", "verify")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
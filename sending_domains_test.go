@@ -0,0 +1,108 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSendingDomains(t *testing.T) {
+	bodies := map[string]map[string]interface{}{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+			parsed := map[string]interface{}{}
+			json.Unmarshal(body, &parsed)
+			bodies[key] = parsed
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch key {
+		case "POST /api/v1/sending-domains":
+			w.Write([]byte(`{"results":{"message":"Successfully Created domain.","domain":"example.com",
+				"dkim":{"public":"MIGfMA0GCSqGSIb3","selector":"scph0316","headers":"from:to:subject:date"}}}`))
+		case "GET /api/v1/sending-domains":
+			w.Write([]byte(`{"results":[{"domain":"example.com","tracking_domain":"click.example.com",
+				"status":{"ownership_verified":true,"dkim_status":"valid","spf_status":"unverified","cname_status":"pending"}}]}`))
+		case "GET /api/v1/sending-domains/example.com":
+			w.Write([]byte(`{"results":{"dkim":{"public":"MIGfMA0GCSqGSIb3","selector":"scph0316"},
+				"status":{"ownership_verified":false,"dkim_status":"unverified"}}}`))
+		case "POST /api/v1/sending-domains/example.com/verify":
+			w.Write([]byte(`{"results":{"ownership_verified":true,"dkim_status":"valid","spf_status":"invalid",
+				"dns":{"dkim_record":"k=rsa; h=sha256; p=MIGfMA0GCSqGSIb3","spf_error":"SPF record not found"}}}`))
+		case "PUT /api/v1/sending-domains/broken.com":
+			w.WriteHeader(500)
+			w.Write([]byte(`{}`))
+		case "GET /api/v1/sending-domains/missing.com":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	generate := true
+	dkim, _, err := client.SendingDomainCreate(&sp.SendingDomain{Domain: "example.com", GenerateDKIM: &generate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dkim == nil || dkim.Selector != "scph0316" || dkim.Public == "" {
+		t.Errorf("unexpected dkim %+v", dkim)
+	}
+	if created := bodies["POST /api/v1/sending-domains"]; created["domain"] != "example.com" || created["generate_dkim"] != true {
+		t.Errorf("unexpected create body %v", created)
+	}
+
+	list, _, err := client.SendingDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || !list[0].Status.OwnershipVerified || list[0].Status.CNAMEStatus != "pending" {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	d, _, err := client.SendingDomain("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Domain != "example.com" || d.DKIM.Selector != "scph0316" || d.Status.DKIMStatus != "unverified" {
+		t.Errorf("unexpected domain %s", d)
+	}
+	if _, _, err = client.SendingDomain("missing.com"); err == nil {
+		t.Error("expected an error for an unknown domain")
+	}
+
+	d.TrackingDomain = "click.example.com"
+	off := false
+	d.SharedWithSubaccounts = &off
+	if _, err = client.SendingDomainUpdate(d); err != nil {
+		t.Fatal(err)
+	}
+	updated := bodies["PUT /api/v1/sending-domains/example.com"]
+	if updated["tracking_domain"] != "click.example.com" || updated["status"] != nil || updated["domain"] != nil ||
+		updated["shared_with_subaccounts"] != false || updated["is_default_bounce_domain"] != nil {
+		t.Errorf("unexpected update body %v", updated)
+	}
+	if _, err = client.SendingDomainUpdate(&sp.SendingDomain{Domain: "broken.com"}); err == nil {
+		t.Error("expected an error for a failed update without errors")
+	}
+
+	status, _, err := client.SendingDomainVerify("example.com", &sp.SendingDomainVerification{DKIM: true, SPF: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.DKIMStatus != "valid" || status.SPFStatus != "invalid" || status.DNS.SPFError == "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	verify := bodies["POST /api/v1/sending-domains/example.com/verify"]
+	if verify["dkim_verify"] != true || verify["spf_verify"] != true || verify["cname_verify"] != nil {
+		t.Errorf("unexpected verify body %v", verify)
+	}
+
+	if _, err = client.SendingDomainDelete("example.com"); err != nil {
+		t.Fatal(err)
+	}
+}
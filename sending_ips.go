@@ -0,0 +1,153 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/sending-ips/
+var sendingIPsPathFormat = "/api/v%d/sending-ips"
+
+// SendingIP is a dedicated IP address, and the IPPool it's in.
+type SendingIP struct {
+	ExternalIP        string `json:"external_ip"`
+	Hostname          string `json:"hostname,omitempty"`
+	IPPool            string `json:"ip_pool,omitempty"`
+	AutoWarmupEnabled bool   `json:"auto_warmup_enabled"`
+	AutoWarmupStage   int    `json:"auto_warmup_stage,omitempty"`
+}
+
+func (s *SendingIP) String() string {
+	return fmt.Sprintf("%s (%s) in %s", s.ExternalIP, s.Hostname, s.IPPool)
+}
+
+// SendingIPs returns all of the account's dedicated IPs.
+func (c *Client) SendingIPs() ([]SendingIP, *Response, error) {
+	path := fmt.Sprintf(sendingIPsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		slist := map[string][]SendingIP{}
+		if err = json.Unmarshal(body, &slist); err != nil {
+			return nil, res, err
+		} else if list, ok := slist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to SendingIP list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("SendingIP", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// SendingIP retrieves the dedicated IP with the specified address.
+func (c *Client) SendingIP(ip string) (*SendingIP, *Response, error) {
+	if ip == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank ip")
+	}
+
+	path := fmt.Sprintf(sendingIPsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, ip)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*SendingIP{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if s, ok := tmp["results"]; ok && s != nil {
+			return s, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to SendingIP retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("SendingIP", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// SendingIPMove moves the sending IP with the specified address into the IPPool with id poolID.
+func (c *Client) SendingIPMove(ip, poolID string) (res *Response, err error) {
+	if ip == "" || poolID == "" {
+		err = fmt.Errorf("SendingIPMove called with blank ip or pool id")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(map[string]string{"ip_pool": poolID})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(sendingIPsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, ip)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("SendingIP", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
@@ -0,0 +1,60 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestSendingIPs(t *testing.T) {
+	var moved map[string]string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/sending-ips":
+			w.Write([]byte(`{"results":[{"external_ip":"123.456.789.012","hostname":"mta1.example.com","ip_pool":"default","auto_warmup_enabled":true,"auto_warmup_stage":2},{"external_ip":"123.456.789.013","hostname":"mta2.example.com","ip_pool":"marketing"}]}`))
+		case "GET /api/v1/sending-ips/123.456.789.013":
+			w.Write([]byte(`{"results":{"external_ip":"123.456.789.013","hostname":"mta2.example.com","ip_pool":"marketing"}}`))
+		case "GET /api/v1/sending-ips/10.0.0.1":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		case "PUT /api/v1/sending-ips/123.456.789.012":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &moved)
+			w.Write([]byte(`{"results":{"message":"Updated IP."}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer done()
+
+	ips, _, err := client.SendingIPs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 2 || ips[0].Hostname != "mta1.example.com" || ips[0].IPPool != "default" || ips[0].AutoWarmupStage != 2 {
+		t.Errorf("unexpected sending ips %+v", ips)
+	}
+
+	ip, _, err := client.SendingIP("123.456.789.013")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip.IPPool != "marketing" || ip.ExternalIP != "123.456.789.013" {
+		t.Errorf("unexpected sending ip %+v", ip)
+	}
+	if _, _, err = client.SendingIP("10.0.0.1"); err == nil {
+		t.Error("expected an error for a missing ip")
+	}
+	if _, _, err = client.SendingIP(""); err == nil {
+		t.Error("expected an error for a blank ip")
+	}
+
+	if _, err = client.SendingIPMove("123.456.789.012", "marketing"); err != nil {
+		t.Fatal(err)
+	}
+	if moved["ip_pool"] != "marketing" {
+		t.Errorf("unexpected move body %v", moved)
+	}
+}
@@ -0,0 +1,244 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	URL "net/url"
+	"time"
+)
+
+// https://developers.sparkpost.com/api/signals/#signals-get-engagement-recency
+var signalsCohortPathFormat = "/api/v%d/signals/cohort-engagement"
+
+// https://developers.sparkpost.com/api/signals/#signals-get-health-score
+var signalsHealthScorePathFormat = "/api/v%d/signals/health-score"
+
+// Facets by which Signals results may be grouped.
+const (
+	SignalsFacetSendingDomain   = "sending-domain"
+	SignalsFacetCampaign        = "campaign"
+	SignalsFacetSubaccount      = "subaccount"
+	SignalsFacetMailboxProvider = "mb-provider"
+	SignalsFacetIPPool          = "ip-pool"
+	SignalsFacetSendingIP       = "sending-ip"
+)
+
+// SignalsParams narrows down the results of Signals API calls.
+type SignalsParams struct {
+	// From and To are rounded to whole days. Zero values use the API's defaults.
+	From time.Time
+	To   time.Time
+	// Filter matches facet values containing this string.
+	Filter      string
+	Subaccounts []int
+	Limit       int
+}
+
+func (p *SignalsParams) query() QueryBuilder {
+	q := QueryBuilder{}
+	if p == nil {
+		return q
+	}
+	return q.Time("from", p.From, QueryDateFormat).
+		Time("to", p.To, QueryDateFormat).
+		Set("filter", p.Filter).
+		Ints("subaccounts", p.Subaccounts...).
+		Int("limit", p.Limit)
+}
+
+// EngagementCohorts breaks down one day's recipients by how recently they last engaged.
+// Rates are fractions (0-1) of each cohort which engaged with mail sent that day.
+type EngagementCohorts struct {
+	Date string `json:"dt"`
+
+	TotalRate               float64 `json:"p_total_eng"`
+	NewRate                 float64 `json:"p_new_eng"`
+	NeverEngagedRate        float64 `json:"p_uneng_eng"`
+	NotRecentlyEngagedRate  float64 `json:"p_365d_eng"`
+	SemiRecentlyEngagedRate float64 `json:"p_90d_eng"`
+	RecentlyEngagedRate     float64 `json:"p_14d_eng"`
+
+	Total               int `json:"c_total"`
+	New                 int `json:"c_new"`
+	NeverEngaged        int `json:"c_uneng"`
+	NotRecentlyEngaged  int `json:"c_365d"`
+	SemiRecentlyEngaged int `json:"c_90d"`
+	RecentlyEngaged     int `json:"c_14d"`
+}
+
+// EngagementCohortResult holds the cohort history for one facet value, for example one sending domain.
+// Facet is blank for the account-wide summary.
+type EngagementCohortResult struct {
+	Facet   string
+	History []EngagementCohorts
+}
+
+// UnmarshalJSON handles the facet value being returned under a facet-specific key.
+func (r *EngagementCohortResult) UnmarshalJSON(data []byte) error {
+	*r = EngagementCohortResult{}
+	return unmarshalFacet(data, &r.Facet, &r.History)
+}
+
+// signalsFacetValues holds the key each facet's value is returned under. Only one is set
+// for a given result. Subaccount ids are numbers, and kept as written.
+type signalsFacetValues struct {
+	SendingDomain   string      `json:"sending_domain"`
+	Campaign        string      `json:"campaign_id"`
+	Subaccount      json.Number `json:"sid"`
+	MailboxProvider string      `json:"mb_provider"`
+	IPPool          string      `json:"ip_pool"`
+	SendingIP       string      `json:"sending_ip"`
+}
+
+func (v *signalsFacetValues) value() string {
+	for _, s := range []string{v.SendingDomain, v.Campaign, v.Subaccount.String(),
+		v.MailboxProvider, v.IPPool, v.SendingIP} {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// unmarshalFacet decodes a Signals result, where history is under "history", and the
+// facet value under a key named after the facet.
+func unmarshalFacet(data []byte, facet *string, history interface{}) error {
+	var raw struct {
+		signalsFacetValues
+		History json.RawMessage `json:"history"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.History) > 0 {
+		if err := json.Unmarshal(raw.History, history); err != nil {
+			return err
+		}
+	}
+	*facet = raw.value()
+	return nil
+}
+
+// EngagementCohorts returns engagement recency cohorts grouped by the provided facet,
+// one of the SignalsFacet constants. An empty facet returns an account-wide summary.
+func (c *Client) EngagementCohorts(facet string, params *SignalsParams) ([]EngagementCohortResult, *Response, error) {
+	path := fmt.Sprintf(signalsCohortPathFormat, c.Config.ApiVersion)
+	if facet != "" {
+		path = fmt.Sprintf("%s/%s", path, URL.PathEscape(facet))
+	}
+	url := params.query().URL(c.Config.BaseUrl + path)
+
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		var wrapper struct {
+			Results json.RawMessage `json:"results"`
+		}
+		if err = json.Unmarshal(body, &wrapper); err != nil {
+			return nil, res, err
+		}
+
+		// the summary endpoint returns a single object rather than a list
+		if facet == "" {
+			var summary EngagementCohortResult
+			if err = json.Unmarshal(wrapper.Results, &summary); err != nil {
+				return nil, res, err
+			}
+			return []EngagementCohortResult{summary}, res, nil
+		}
+		var list []EngagementCohortResult
+		if err = json.Unmarshal(wrapper.Results, &list); err != nil {
+			return nil, res, err
+		}
+		return list, res, nil
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Engagement cohorts", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// HealthScore is one day's Signals health score, from 0 (worst) to 1 (best).
+type HealthScore struct {
+	Date  string  `json:"dt"`
+	Score float64 `json:"health_score"`
+}
+
+// HealthScoreResult holds the health score history for one facet value, oldest first.
+type HealthScoreResult struct {
+	Facet   string
+	History []HealthScore
+}
+
+// UnmarshalJSON handles the facet value being returned under a facet-specific key.
+func (r *HealthScoreResult) UnmarshalJSON(data []byte) error {
+	*r = HealthScoreResult{}
+	return unmarshalFacet(data, &r.Facet, &r.History)
+}
+
+// Latest returns the most recent score, if there is one.
+func (r *HealthScoreResult) Latest() (HealthScore, bool) {
+	if len(r.History) == 0 {
+		return HealthScore{}, false
+	}
+	return r.History[len(r.History)-1], true
+}
+
+// HealthScores returns health scores grouped by the provided facet, one of the SignalsFacet constants.
+func (c *Client) HealthScores(facet string, params *SignalsParams) ([]HealthScoreResult, *Response, error) {
+	path := fmt.Sprintf(signalsHealthScorePathFormat, c.Config.ApiVersion)
+	path = fmt.Sprintf("%s/%s", path, URL.PathEscape(facet))
+	res, err := c.HttpGet(params.query().URL(c.Config.BaseUrl + path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		if body, err = res.ReadBody(); err != nil {
+			return nil, res, err
+		}
+		var wrapper struct {
+			Results []HealthScoreResult `json:"results"`
+		}
+		if err = json.Unmarshal(body, &wrapper); err != nil {
+			return nil, res, err
+		}
+		return wrapper.Results, res, nil
+	}
+
+	if err = res.ParseResponse(); err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		if err = res.PrettyError("Health scores", "retrieve"); err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
@@ -0,0 +1,41 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestEngagementCohorts(t *testing.T) {
+	var query string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"sending_domain":"example.com","history":[
+			{"dt":"2018-07-01","p_total_eng":0.5,"p_uneng_eng":0.01,"p_14d_eng":0.9,"c_total":1000,"c_uneng":200,"c_14d":300}
+		]},{"sid":12,"history":[]},{"sid":1000000,"history":[]},{"campaign_id":"summer","extra":1,"history":[]}]}`))
+	})
+	defer done()
+
+	params := &sp.SignalsParams{
+		From:        time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC),
+		Subaccounts: []int{0, 12},
+	}
+	results, _, err := client.EngagementCohorts(sp.SignalsFacetSendingDomain, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "from=2018-07-01&subaccounts=0%2C12" {
+		t.Errorf("unexpected query string %q", query)
+	}
+	if len(results) != 4 || results[0].Facet != "example.com" || results[1].Facet != "12" ||
+		results[2].Facet != "1000000" || results[3].Facet != "summer" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	day := results[0].History[0]
+	if day.Total != 1000 || day.NeverEngaged != 200 || day.RecentlyEngagedRate != 0.9 {
+		t.Errorf("unexpected cohorts %+v", day)
+	}
+}
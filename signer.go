@@ -0,0 +1,63 @@
+package gosparkpost
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RequestSigner is called with each request to the API once its headers are set, just
+// before it's sent, so it can add headers of its own, for example an attestation
+// required by an egress proxy. body is the request body, which is nil for GET and DELETE.
+// An error stops the request being sent.
+type RequestSigner interface {
+	SignRequest(req *http.Request, body []byte) error
+}
+
+// RequestSignerFunc allows a function to be used as a RequestSigner.
+type RequestSignerFunc func(req *http.Request, body []byte) error
+
+func (f RequestSignerFunc) SignRequest(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// HMACSigner signs requests with an HMAC-SHA256 of the timestamp, method, path (with query)
+// and body, each followed by a newline. The hex encoded signature is sent in Header, and the
+// Unix timestamp used in TimestampHeader, so the receiver can reject stale requests.
+type HMACSigner struct {
+	Key []byte
+	// Header and TimestampHeader default to X-Signature and X-Signature-Timestamp.
+	Header          string
+	TimestampHeader string
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+func (s *HMACSigner) SignRequest(req *http.Request, body []byte) error {
+	header, tsHeader, now := s.Header, s.TimestampHeader, s.Now
+	if header == "" {
+		header = "X-Signature"
+	}
+	if tsHeader == "" {
+		tsHeader = "X-Signature-Timestamp"
+	}
+	if now == nil {
+		now = time.Now
+	}
+
+	ts := strconv.FormatInt(now().Unix(), 10)
+	mac := hmac.New(sha256.New, s.Key)
+	for _, part := range []string{ts, req.Method, req.URL.RequestURI()} {
+		mac.Write([]byte(part))
+		mac.Write([]byte("\n"))
+	}
+	mac.Write(body)
+	mac.Write([]byte("\n"))
+
+	req.Header.Set(tsHeader, ts)
+	req.Header.Set(header, hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
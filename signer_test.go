@@ -0,0 +1,59 @@
+package gosparkpost_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestHMACSigner(t *testing.T) {
+	var sig, ts, body string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		sig, ts, body = r.Header.Get("X-Signature"), r.Header.Get("X-Signature-Timestamp"), string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{}}`))
+	})
+	defer done()
+
+	key := []byte("secret")
+	client.Signer = &sp.HMACSigner{Key: key, Now: func() time.Time { return time.Unix(1500000000, 0) }}
+	url := client.Config.BaseUrl + "/api/v1/things?x=1"
+	if _, err := client.HttpPost(url, []byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "1500000000\nPOST\n/api/v1/things?x=1\n%s\n", body)
+	if expected := hex.EncodeToString(mac.Sum(nil)); sig != expected || ts != "1500000000" {
+		t.Errorf("expected signature %s at 1500000000, got %s at %s", expected, sig, ts)
+	}
+}
+
+func TestRequestSignerError(t *testing.T) {
+	called := false
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	defer done()
+
+	client.Signer = sp.RequestSignerFunc(func(req *http.Request, body []byte) error {
+		if req.Header.Get("Authorization") == "" {
+			t.Error("signer called before Authorization was set")
+		}
+		return fmt.Errorf("no attestation")
+	})
+	if _, err := client.HttpGet(client.Config.BaseUrl + "/api/v1/things"); err == nil || err.Error() != "no attestation" {
+		t.Errorf("expected the signer's error, got %v", err)
+	}
+	if called {
+		t.Error("request sent despite signer error")
+	}
+}
@@ -0,0 +1,68 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SinkDomain is SparkPost's sink. Mail to any address ending in this domain
+// goes through the full sending pipeline, but is discarded instead of delivered.
+// https://www.sparkpost.com/docs/faq/using-sink-server/
+const SinkDomain = "sink.sparkpostmail.com"
+
+// SinkAddress rewrites an email address so it's delivered to the provided sink domain,
+// for example "bob@example.com" becomes "bob@example.com.sink.sparkpostmail.com".
+// Addresses already ending in the sink domain are returned unchanged.
+func SinkAddress(email, sinkDomain string) string {
+	if strings.HasSuffix(strings.ToLower(email), "."+strings.ToLower(sinkDomain)) {
+		return email
+	}
+	return email + "." + sinkDomain
+}
+
+// SinkRecipients returns a copy of the provided inline Recipients with each address
+// rewritten by SinkAddress. Stored recipient lists can't be rewritten, and return an error.
+func SinkRecipients(recips interface{}, sinkDomain string) ([]Recipient, error) {
+	list, err := inlineRecipients(recips)
+	if err != nil {
+		return nil, err
+	} else if list == nil {
+		return nil, fmt.Errorf("Can't send [%s] Recipients to the sink, only inline Recipients", reflect.TypeOf(recips))
+	}
+
+	for i, r := range list {
+		addr, err := ParseAddress(r.Address)
+		if err != nil {
+			return nil, err
+		}
+		addr.Email = SinkAddress(addr.Email, sinkDomain)
+		list[i].Address = addr
+	}
+	return list, nil
+}
+
+// inlineRecipients returns a copy of the provided inline Recipients as a slice,
+// or nil if recips is a stored recipient list (or something else entirely).
+func inlineRecipients(recips interface{}) ([]Recipient, error) {
+	list := []Recipient{}
+	switch rVal := recips.(type) {
+	case []string:
+		for _, r := range rVal {
+			list = append(list, Recipient{Address: r})
+		}
+	case []Recipient:
+		list = append(list, rVal...)
+	case []interface{}:
+		for _, r := range rVal {
+			recip, ok := r.(Recipient)
+			if !ok {
+				return nil, fmt.Errorf("Failed to parse inline Transmission.Recipient list")
+			}
+			list = append(list, recip)
+		}
+	default:
+		return nil, nil
+	}
+	return list, nil
+}
@@ -0,0 +1,43 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSink(t *testing.T) {
+	var sent struct {
+		Recipients []struct {
+			Address sp.Address `json:"address"`
+		} `json:"recipients"`
+	}
+	cfg := &sp.Config{ApiKey: "testkey", Sink: sp.SinkDomain}
+	client, done := newTestClient(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"1234"}}`))
+	})
+	defer done()
+
+	tx := &sp.Transmission{
+		Recipients: []string{"a@example.com", "b@example.com.sink.sparkpostmail.com"},
+		Content:    sp.Content{Subject: "sink", Text: "sink", From: "test@example.com"},
+	}
+	if _, _, err := client.Send(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent.Recipients) != 2 ||
+		sent.Recipients[0].Address.Email != "a@example.com.sink.sparkpostmail.com" ||
+		sent.Recipients[1].Address.Email != "b@example.com.sink.sparkpostmail.com" {
+		t.Errorf("unexpected recipients sent: %+v", sent.Recipients)
+	}
+	if addr := tx.Recipients.([]sp.Recipient)[0].Address; addr.(map[string]string)["email"] != "a@example.com" {
+		t.Errorf("expected caller's Transmission to be left alone, got %v", addr)
+	}
+}
@@ -0,0 +1,348 @@
+package gosparkpost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// Kinds of Alert raised by SLAMonitor.
+const (
+	AlertLatency    = "p95_latency"
+	AlertBounceRate = "bounce_rate"
+)
+
+// Alert reports that a monitored value crossed its threshold, or, if Resolved is set,
+// that it has recovered.
+type Alert struct {
+	Kind     string
+	Template string
+	// Value and Threshold are in seconds for AlertLatency, and fractions (0-1) for AlertBounceRate.
+	Value     float64
+	Threshold float64
+	// Messages is the number of messages Value was calculated from.
+	Messages int
+	Resolved bool
+	Time     time.Time
+}
+
+func (a *Alert) String() string {
+	state := "exceeded"
+	if a.Resolved {
+		state = "recovered"
+	}
+	value, threshold := fmt.Sprintf("%.1f%%", a.Value*100), fmt.Sprintf("%.1f%%", a.Threshold*100)
+	if a.Kind == AlertLatency {
+		value = (time.Duration(a.Value * float64(time.Second))).Round(time.Millisecond).String()
+		threshold = (time.Duration(a.Threshold * float64(time.Second))).Round(time.Millisecond).String()
+	}
+	return fmt.Sprintf("%s %s for template %q: %s (threshold %s, %d messages)",
+		a.Kind, state, a.Template, value, threshold, a.Messages)
+}
+
+// AlertNotifier is told when an Alert is raised or resolved.
+type AlertNotifier interface {
+	NotifyAlert(ctx context.Context, alert *Alert) error
+}
+
+// AlertNotifierFunc allows a function to be used as an AlertNotifier.
+type AlertNotifierFunc func(ctx context.Context, alert *Alert) error
+
+func (f AlertNotifierFunc) NotifyAlert(ctx context.Context, alert *Alert) error {
+	return f(ctx, alert)
+}
+
+func (w *WebhookNotifier) NotifyAlert(ctx context.Context, alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	return postNotification(ctx, w.Client, w.URL, body)
+}
+
+func (s *SlackNotifier) NotifyAlert(ctx context.Context, alert *Alert) error {
+	body, err := json.Marshal(map[string]string{"text": alert.String()})
+	if err != nil {
+		return err
+	}
+	return postNotification(ctx, s.Client, s.WebhookURL, body)
+}
+
+func (e *EmailNotifier) NotifyAlert(ctx context.Context, alert *Alert) error {
+	_, _, err := e.Client.Send(&Transmission{
+		Recipients: e.To,
+		Content: Content{
+			From:    e.From,
+			Subject: alert.String(),
+			Text:    alert.String(),
+		},
+	})
+	return err
+}
+
+// SLAThresholds are the limits SLAMonitor alerts on. Zero values aren't checked.
+type SLAThresholds struct {
+	// P95Latency is the time from injection to delivery which 95% of messages must beat.
+	P95Latency time.Duration
+	// BounceRate is the fraction (0-1) of messages which may bounce.
+	BounceRate float64
+}
+
+// SLAMonitor watches injection, delivery and bounce events for transactional messages and
+// alerts when, for any template, the 95th percentile delivery latency or the bounce rate over
+// the recent Window exceeds its threshold. A second alert, with Resolved set, follows once it
+// recovers. Feed it events with Observe, for example from a webhook handler.
+type SLAMonitor struct {
+	// Tag selects the messages to watch, by recipient tag. Defaults to "transactional";
+	// set it to "-" to watch every message.
+	Tag string
+	// Window is how far back, in event time, messages are considered. Defaults to 15 minutes.
+	Window time.Duration
+	// MinMessages is the number of messages needed in the Window before alerting. Defaults to 20.
+	MinMessages int
+
+	Thresholds SLAThresholds
+	// Templates overrides Thresholds for particular template IDs.
+	Templates map[string]SLAThresholds
+	Notifiers []AlertNotifier
+
+	runner
+	mu       sync.Mutex
+	pending  map[string]*slaMessage
+	samples  map[string][]slaSample
+	alerting map[string]bool
+	latest   time.Time
+	pruned   time.Time
+}
+
+var _ Component = &SLAMonitor{}
+
+type slaMessage struct {
+	template string
+	injected time.Time
+	outcome  time.Time
+	bounced  bool
+	seen     time.Time
+	received time.Time
+}
+
+type slaSample struct {
+	at      time.Time
+	latency time.Duration
+	bounced bool
+}
+
+// Observe records an event, notifying the Notifiers of any alerts it raises or resolves.
+// Events may arrive in any order. Errors from Notifiers are returned, joined together.
+func (m *SLAMonitor) Observe(ctx context.Context, e events.Event) error {
+	var messageID, template string
+	var tags []string
+	var at time.Time
+	var injection, bounced bool
+	switch e := e.(type) {
+	case *events.Injection:
+		messageID, template, tags, at, injection = e.MessageID, e.TemplateID, e.Tags, time.Time(e.Timestamp), true
+	case *events.Delivery:
+		messageID, template, tags, at = e.MessageID, e.TemplateID, e.Tags, time.Time(e.Timestamp)
+	case *events.Bounce:
+		messageID, template, tags, at, bounced = e.MessageID, e.TemplateID, e.Tags, time.Time(e.Timestamp), true
+	default:
+		return nil
+	}
+	if messageID == "" || !m.watched(tags) {
+		return nil
+	}
+
+	m.mu.Lock()
+	if m.pending == nil {
+		m.pending = map[string]*slaMessage{}
+		m.samples = map[string][]slaSample{}
+		m.alerting = map[string]bool{}
+	}
+	if at.After(m.latest) {
+		m.latest = at
+	}
+
+	msg := m.pending[messageID]
+	if msg == nil {
+		msg = &slaMessage{seen: at, received: time.Now()}
+		m.pending[messageID] = msg
+	}
+	if template != "" {
+		msg.template = template
+	}
+	if injection {
+		msg.injected = at
+	} else if msg.outcome.IsZero() {
+		msg.outcome, msg.bounced = at, bounced
+	}
+
+	var alerts []*Alert
+	if !msg.injected.IsZero() && !msg.outcome.IsZero() {
+		delete(m.pending, messageID)
+		m.samples[msg.template] = append(m.samples[msg.template],
+			slaSample{msg.outcome, msg.outcome.Sub(msg.injected), msg.bounced})
+		alerts = m.evaluate(msg.template)
+	}
+	m.prune()
+	m.mu.Unlock()
+
+	var err error
+	for _, alert := range alerts {
+		for _, n := range m.Notifiers {
+			if nerr := n.NotifyAlert(ctx, alert); nerr != nil {
+				if err == nil {
+					err = fmt.Errorf("Notifier failed: %s", nerr)
+				} else {
+					err = fmt.Errorf("%s; Notifier failed: %s", err, nerr)
+				}
+			}
+		}
+	}
+	if err != nil {
+		m.fail(err)
+	}
+	return err
+}
+
+// Start forgets, hourly until Stop is called or ctx is done, messages received over a day
+// ago which are still waiting for their other half. Observe does the same as events arrive,
+// by event time; this bounds memory once they stop.
+func (m *SLAMonitor) Start(ctx context.Context) error {
+	return m.start(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				m.expire(now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// Stop stops the work begun by Start.
+func (m *SLAMonitor) Stop(ctx context.Context) error {
+	return m.stop(ctx)
+}
+
+// Health reports whether the SLAMonitor was started and is running, and the last error
+// from its Notifiers.
+func (m *SLAMonitor) Health() Health {
+	return m.report()
+}
+
+// expire forgets messages received before now, by the wall clock, less a day.
+func (m *SLAMonitor) expire(now time.Time) {
+	cutoff := now.Add(-24 * time.Hour)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, msg := range m.pending {
+		if msg.received.Before(cutoff) {
+			delete(m.pending, id)
+		}
+	}
+}
+
+func (m *SLAMonitor) watched(tags []string) bool {
+	tag := m.Tag
+	if tag == "" {
+		tag = "transactional"
+	} else if tag == "-" {
+		return true
+	}
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *SLAMonitor) window() time.Duration {
+	if m.Window <= 0 {
+		return 15 * time.Minute
+	}
+	return m.Window
+}
+
+// evaluate checks the thresholds for a template, returning any alerts raised or resolved.
+// The caller holds mu.
+func (m *SLAMonitor) evaluate(template string) []*Alert {
+	cutoff := m.latest.Add(-m.window())
+	samples := m.samples[template][:0]
+	for _, s := range m.samples[template] {
+		if !s.at.Before(cutoff) {
+			samples = append(samples, s)
+		}
+	}
+	m.samples[template] = samples
+
+	min := m.MinMessages
+	if min <= 0 {
+		min = 20
+	}
+	if len(samples) < min {
+		return nil
+	}
+
+	thresholds, ok := m.Templates[template]
+	if !ok {
+		thresholds = m.Thresholds
+	}
+
+	var alerts []*Alert
+	check := func(kind string, value, threshold float64) {
+		if threshold <= 0 {
+			return
+		}
+		key := kind + "\x00" + template
+		breached := value > threshold
+		if breached != m.alerting[key] {
+			m.alerting[key] = breached
+			alerts = append(alerts, &Alert{
+				Kind: kind, Template: template, Value: value, Threshold: threshold,
+				Messages: len(samples), Resolved: !breached, Time: m.latest,
+			})
+		}
+	}
+
+	var latencies []time.Duration
+	bounces := 0
+	for _, s := range samples {
+		if s.bounced {
+			bounces++
+		} else {
+			latencies = append(latencies, s.latency)
+		}
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		p95 := latencies[(len(latencies)*95+99)/100-1]
+		check(AlertLatency, p95.Seconds(), thresholds.P95Latency.Seconds())
+	}
+	check(AlertBounceRate, float64(bounces)/float64(len(samples)), thresholds.BounceRate)
+	return alerts
+}
+
+// prune forgets messages which have waited a day for their other half, at most hourly.
+// The caller holds mu.
+func (m *SLAMonitor) prune() {
+	if m.latest.Sub(m.pruned) < time.Hour {
+		return
+	}
+	m.pruned = m.latest
+	cutoff := m.latest.Add(-24 * time.Hour)
+	for id, msg := range m.pending {
+		if msg.seen.Before(cutoff) {
+			delete(m.pending, id)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+func TestSLAMonitor(t *testing.T) {
+	var alerts []string
+	m := &sp.SLAMonitor{
+		MinMessages: 4,
+		Thresholds:  sp.SLAThresholds{P95Latency: 30 * time.Second, BounceRate: 0.5},
+		Templates:   map[string]sp.SLAThresholds{"receipt": {P95Latency: time.Minute}},
+		Notifiers: []sp.AlertNotifier{sp.AlertNotifierFunc(func(ctx context.Context, a *sp.Alert) error {
+			alerts = append(alerts, a.String())
+			return nil
+		})},
+	}
+
+	start := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	n := 0
+	send := func(template string, latency time.Duration, bounced bool, tags ...string) {
+		n++
+		id := fmt.Sprintf("m%d", n)
+		at := start.Add(time.Duration(n) * time.Second)
+		injection := &events.Injection{MessageID: id, TemplateID: template, Tags: tags, Timestamp: events.Timestamp(at)}
+		var outcome events.Event = &events.Delivery{MessageID: id, Tags: tags, Timestamp: events.Timestamp(at.Add(latency))}
+		if bounced {
+			outcome = &events.Bounce{MessageID: id, Tags: tags, Timestamp: events.Timestamp(at.Add(latency))}
+		}
+		// deliveries sometimes arrive before their injection
+		first, second := events.Event(injection), outcome
+		if n%2 == 0 {
+			first, second = second, first
+		}
+		for _, e := range []events.Event{first, second} {
+			if err := m.Observe(context.Background(), e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		send("welcome", 5*time.Second, false, "transactional")
+	}
+	// untagged mail isn't watched
+	for i := 0; i < 10; i++ {
+		send("welcome", time.Hour, true)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("unexpected alerts %v", alerts)
+	}
+
+	send("welcome", 45*time.Second, false, "transactional")
+	if len(alerts) != 1 || alerts[0] != `p95_latency exceeded for template "welcome": 45s (threshold 30s, 5 messages)` {
+		t.Fatalf("expected a latency alert, got %v", alerts)
+	}
+	// still breached, so no repeat
+	send("welcome", 45*time.Second, false, "transactional")
+	if len(alerts) != 1 {
+		t.Fatalf("expected no repeated alert, got %v", alerts)
+	}
+
+	// the receipt template allows a minute, and has no bounce threshold
+	for i := 0; i < 6; i++ {
+		send("receipt", 45*time.Second, i%2 == 0, "transactional")
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected receipt to stay within its thresholds, got %v", alerts)
+	}
+
+	// once the slow messages leave the window, the alert resolves
+	start = start.Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		send("welcome", time.Second, false, "transactional")
+	}
+	if len(alerts) != 2 || alerts[1] != `p95_latency recovered for template "welcome": 1s (threshold 30s, 4 messages)` {
+		t.Fatalf("expected the alert to resolve, got %v", alerts)
+	}
+}
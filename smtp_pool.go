@@ -0,0 +1,403 @@
+package gosparkpost
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SMTPInjectionUser is the username used with an API key to authenticate with SparkPost's SMTP relay.
+const SMTPInjectionUser = "SMTP_Injection"
+
+// SMTPPool sends messages through SparkPost's SMTP relay over a pool of persistent connections,
+// so high volume SMTP injection doesn't pay for a handshake per message. Connections idle for
+// a while are checked with NOOP before reuse, and a message whose connection turns out to be dead
+// is retried once on a new one. An SMTPPool may be used concurrently.
+// If the server supports PIPELINING, the envelope of each message is sent without waiting
+// for the response to each command. Sends wait for the Client's Limiter, if it has one.
+type SMTPPool struct {
+	// Client provides the API key used to authenticate, and the TLSPolicy applied with STARTTLS.
+	Client *Client
+	// Addr defaults to smtp.sparkpostmail.com:587.
+	Addr string
+	// Size is the most connections open at once. It defaults to 4.
+	Size int
+	// MaxMessagesPerConn, if set, closes connections after they've sent this many messages.
+	MaxMessagesPerConn int
+	// IdleCheck is how long a connection may be idle before it's checked. It defaults to 30 seconds.
+	IdleCheck time.Duration
+	// DisablePipelining stops commands being pipelined, even if the server supports it.
+	DisablePipelining bool
+	// Dial defaults to net.Dialer with a 30 second timeout.
+	Dial func(ctx context.Context, addr string) (net.Conn, error)
+
+	runner
+	once   sync.Once
+	idle   chan *smtpConn
+	slots  chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ Component = &SMTPPool{}
+
+type smtpConn struct {
+	*smtp.Client
+	// conn is the connection under Client, whose deadline applies through STARTTLS too
+	conn       net.Conn
+	pipelining bool
+	sent       int
+	lastUsed   time.Time
+}
+
+func (p *SMTPPool) init() {
+	p.once.Do(func() {
+		if p.Addr == "" {
+			p.Addr = "smtp.sparkpostmail.com:587"
+		}
+		if p.Size <= 0 {
+			p.Size = 4
+		}
+		if p.IdleCheck <= 0 {
+			p.IdleCheck = 30 * time.Second
+		}
+		if p.Dial == nil {
+			d := &net.Dialer{Timeout: 30 * time.Second}
+			p.Dial = func(ctx context.Context, addr string) (net.Conn, error) {
+				return d.DialContext(ctx, "tcp", addr)
+			}
+		}
+		p.idle = make(chan *smtpConn, p.Size)
+		p.slots = make(chan struct{}, p.Size)
+	})
+}
+
+// Send sends msg, a complete RFC 822 message, from the envelope sender to the recipients,
+// waiting for a connection if all of them are busy.
+func (p *SMTPPool) Send(ctx context.Context, from string, to []string, msg []byte) (err error) {
+	p.init()
+	defer func() {
+		if err != nil {
+			p.fail(err)
+		}
+	}()
+	if err := checkEnvelope(from, to); err != nil {
+		return err
+	}
+	if p.Client != nil {
+		if err := p.Client.Limiter.Wait(ctx, len(to)); err != nil {
+			return err
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		conn, reused, err := p.get(ctx)
+		if err != nil {
+			return err
+		}
+
+		release := bindConn(ctx, conn.conn)
+		stale, err := p.send(conn, from, to, msg)
+		if err == nil {
+			release()
+			p.put(conn)
+			return nil
+		}
+		if _, rejected := err.(*textproto.Error); rejected {
+			// the server refused the message, but the connection is still good
+			rerr := conn.Reset()
+			release()
+			if rerr == nil {
+				p.put(conn)
+			} else {
+				p.discard(conn)
+			}
+			return err
+		}
+		release()
+		p.discard(conn)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if !stale || !reused || attempt > 0 {
+			return err
+		}
+	}
+}
+
+// send sends one message on conn. stale is set if it failed before the server accepted
+// anything, which is how a connection closed by the server while idle shows up.
+func (p *SMTPPool) send(conn *smtpConn, from string, to []string, msg []byte) (stale bool, err error) {
+	if conn.pipelining && !p.DisablePipelining {
+		if stale, err = conn.pipelineEnvelope(from, to); err != nil {
+			return
+		}
+	} else {
+		if err = conn.Mail(from); err != nil {
+			return true, err
+		}
+		for _, addr := range to {
+			if err = conn.Rcpt(addr); err != nil {
+				return false, err
+			}
+		}
+	}
+	w, err := conn.Data()
+	if err != nil {
+		return false, err
+	}
+	if _, err = w.Write(msg); err != nil {
+		w.Close()
+		return false, err
+	}
+	if err = w.Close(); err != nil {
+		return false, err
+	}
+	conn.sent++
+	return false, nil
+}
+
+// get returns an idle connection, checking it if it's been idle a while, or dials a new one.
+func (p *SMTPPool) get(ctx context.Context) (conn *smtpConn, reused bool, err error) {
+	for {
+		p.mu.Lock()
+		closed := p.closed
+		p.mu.Unlock()
+		if closed {
+			return nil, false, fmt.Errorf("SMTPPool is closed")
+		}
+
+		select {
+		case conn = <-p.idle:
+		default:
+			select {
+			case conn = <-p.idle:
+			case p.slots <- struct{}{}:
+				if conn, err = p.dial(ctx); err != nil {
+					<-p.slots
+					return nil, false, err
+				}
+				return conn, false, nil
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			}
+		}
+
+		if time.Since(conn.lastUsed) < p.IdleCheck {
+			return conn, true, nil
+		}
+		release := bindConn(ctx, conn.conn)
+		err = conn.noop()
+		release()
+		if err == nil {
+			return conn, true, nil
+		}
+		p.discard(conn)
+		if ctx.Err() != nil {
+			return nil, false, ctx.Err()
+		}
+	}
+}
+
+// pipelineEnvelope sends MAIL and RCPT commands together, then reads their responses.
+// DATA is left until every recipient is known to have been accepted, so a rejected
+// recipient fails the whole message, as it does without pipelining.
+func (c *smtpConn) pipelineEnvelope(from string, to []string) (stale bool, err error) {
+	// net/smtp's Mail and Rcpt check this, but Text.Cmd doesn't
+	if err = checkEnvelope(from, to); err != nil {
+		return false, err
+	}
+
+	// the same parameters as net/smtp's Mail
+	mail := "MAIL FROM:<%s>"
+	if ok, _ := c.Extension("8BITMIME"); ok {
+		mail += " BODY=8BITMIME"
+	}
+	if ok, _ := c.Extension("SMTPUTF8"); ok {
+		mail += " SMTPUTF8"
+	}
+
+	ids := make([]uint, 0, len(to)+1)
+	id, err := c.Text.Cmd(mail, from)
+	if err != nil {
+		return true, err
+	}
+	ids = append(ids, id)
+	for _, addr := range to {
+		if id, err = c.Text.Cmd("RCPT TO:<%s>", addr); err != nil {
+			return false, err
+		}
+		ids = append(ids, id)
+	}
+
+	// every response has to be read, even after one fails
+	for i, id := range ids {
+		expect := 25 // 250 or 251 for RCPT
+		if i == 0 {
+			expect = 250
+		}
+		c.Text.StartResponse(id)
+		_, _, rerr := c.Text.ReadResponse(expect)
+		c.Text.EndResponse(id)
+		if rerr != nil && err == nil {
+			_, rejected := rerr.(*textproto.Error)
+			stale, err = i == 0 && !rejected, rerr
+		}
+	}
+	return stale, err
+}
+
+// bindConn makes I/O on conn fail once ctx is done, until the returned func is called.
+func bindConn(ctx context.Context, conn net.Conn) (release func()) {
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	if ctx.Done() == nil {
+		return func() { conn.SetDeadline(time.Time{}) }
+	}
+	done, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			// a deadline in the past interrupts reads and writes in progress
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+		conn.SetDeadline(time.Time{})
+	}
+}
+
+// checkEnvelope refuses addresses containing CR or LF, which would let them inject SMTP commands.
+func checkEnvelope(from string, to []string) error {
+	for _, addr := range append([]string{from}, to...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("SMTP envelope address %q contains CR or LF", addr)
+		}
+	}
+	return nil
+}
+
+// noop checks the connection is still alive.
+func (c *smtpConn) noop() error {
+	id, err := c.Text.Cmd("NOOP")
+	if err != nil {
+		return err
+	}
+	c.Text.StartResponse(id)
+	defer c.Text.EndResponse(id)
+	_, _, err = c.Text.ReadResponse(250)
+	return err
+}
+
+func (p *SMTPPool) dial(ctx context.Context) (*smtpConn, error) {
+	if p.Client == nil || p.Client.Config == nil {
+		return nil, fmt.Errorf("SMTPPool requires an initialized Client")
+	}
+	host, _, err := net.SplitHostPort(p.Addr)
+	if err != nil {
+		return nil, err
+	}
+	netConn, err := p.Dial(ctx, p.Addr)
+	if err != nil {
+		return nil, err
+	}
+	release := bindConn(ctx, netConn)
+	defer release()
+	client, err := smtp.NewClient(netConn, host)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		tlsConfig, err := p.Client.SMTPTLSConfig(host)
+		if err == nil {
+			err = client.StartTLS(tlsConfig)
+		}
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok {
+		// PlainAuth refuses to send the key over a connection without TLS, other than to localhost
+		auth := smtp.PlainAuth("", SMTPInjectionUser, p.Client.Config.ApiKey, host)
+		if err = client.Auth(auth); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	pipelining, _ := client.Extension("PIPELINING")
+	return &smtpConn{Client: client, conn: netConn, pipelining: pipelining, lastUsed: time.Now()}, nil
+}
+
+// put returns a connection to the pool, unless it's sent its share of messages or the pool is closed.
+// mu is held while it's returned, so Close, which takes it first, always finds it.
+func (p *SMTPPool) put(conn *smtpConn) {
+	p.mu.Lock()
+	if !p.closed && (p.MaxMessagesPerConn <= 0 || conn.sent < p.MaxMessagesPerConn) {
+		conn.lastUsed = time.Now()
+		// there's room: idle holds as many connections as there are slots
+		p.idle <- conn
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	conn.Quit()
+	p.discard(conn)
+}
+
+func (p *SMTPPool) discard(conn *smtpConn) {
+	conn.Close()
+	<-p.slots
+}
+
+// Start ties the pool to ctx: it's closed when Stop is called or ctx is done. Using a pool
+// without calling Start is fine too, calling Close when done with it.
+func (p *SMTPPool) Start(ctx context.Context) error {
+	p.init()
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return fmt.Errorf("SMTPPool is closed")
+	}
+	return p.start(ctx, func(ctx context.Context) {
+		<-ctx.Done()
+		p.Close()
+	})
+}
+
+// Stop closes the pool, as Close does.
+func (p *SMTPPool) Stop(ctx context.Context) error {
+	return p.stop(ctx)
+}
+
+// Health reports whether the pool was started and is open, and the last error from Send.
+func (p *SMTPPool) Health() Health {
+	return p.report()
+}
+
+// Close closes idle connections, and any others as they're finished with.
+func (p *SMTPPool) Close() error {
+	p.init()
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	for {
+		select {
+		case conn := <-p.idle:
+			conn.Quit()
+			p.discard(conn)
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// fakeSMTP is a minimal SMTP server, which hangs up after dropAfter messages on a connection,
+// and counts how often MAIL arrives with the commands after it already sent. If stall is set,
+// it signals stalled once each message has arrived, and waits for stall to close before
+// accepting it.
+type fakeSMTP struct {
+	ln         net.Listener
+	dropAfter  int
+	pipelining bool
+	stall      chan struct{}
+	stalled    chan struct{}
+
+	mu        sync.Mutex
+	conns     int
+	messages  []string
+	noops     int
+	auth      []string
+	pipelined int
+	commands  []string
+}
+
+func newFakeSMTP(t *testing.T, dropAfter int, pipelining bool) *fakeSMTP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTP{ln: ln, dropAfter: dropAfter, pipelining: pipelining}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	tc := textproto.NewConn(conn)
+	tc.PrintfLine("220 fake ESMTP")
+	sent := 0
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO":
+			tc.PrintfLine("250-fake")
+			if s.pipelining {
+				tc.PrintfLine("250-PIPELINING")
+				tc.PrintfLine("250-8BITMIME")
+			}
+			tc.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = append(s.auth, line)
+			s.mu.Unlock()
+			tc.PrintfLine("235 ok")
+		case "MAIL":
+			if s.dropAfter > 0 && sent >= s.dropAfter {
+				return
+			}
+			if s.pipelining {
+				// give a pipelining client time to send the RCPTs too
+				time.Sleep(20 * time.Millisecond)
+				if tc.R.Buffered() > 0 {
+					s.mu.Lock()
+					s.pipelined++
+					s.mu.Unlock()
+				}
+			}
+			tc.PrintfLine("250 ok")
+		case "RCPT":
+			if strings.Contains(line, "bad@") {
+				tc.PrintfLine("550 no such user")
+			} else {
+				tc.PrintfLine("250 ok")
+			}
+		case "DATA":
+			tc.PrintfLine("354 go ahead")
+			body, err := tc.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if s.stall != nil {
+				s.stalled <- struct{}{}
+				<-s.stall
+			}
+			sent++
+			s.mu.Lock()
+			s.messages = append(s.messages, string(body))
+			s.mu.Unlock()
+			tc.PrintfLine("250 queued")
+		case "NOOP":
+			s.mu.Lock()
+			s.noops++
+			s.mu.Unlock()
+			tc.PrintfLine("250 ok")
+		case "RSET":
+			tc.PrintfLine("250 ok")
+		case "QUIT":
+			tc.PrintfLine("221 bye")
+			return
+		default:
+			tc.PrintfLine("502 unknown")
+		}
+	}
+}
+
+func (s *fakeSMTP) stats() (conns, messages, noops int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns, len(s.messages), s.noops
+}
+
+func TestSMTPPool(t *testing.T) {
+	server := newFakeSMTP(t, 0, false)
+	defer server.ln.Close()
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{ApiKey: "testkey"}); err != nil {
+		t.Fatal(err)
+	}
+	pool := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String(), Size: 2, MaxMessagesPerConn: 5}
+	defer pool.Close()
+
+	ctx := context.Background()
+	msg := []byte("Subject: hi\r\n\r\nhello\r\n")
+	for i := 0; i < 10; i++ {
+		if err := pool.Send(ctx, "from@example.com", []string{"to@example.com"}, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if conns, messages, _ := server.stats(); conns != 2 || messages != 10 {
+		t.Errorf("expected 10 messages over 2 connections, got %d over %d", messages, conns)
+	}
+	server.mu.Lock()
+	auth := server.auth
+	server.mu.Unlock()
+	if len(auth) == 0 || !strings.HasPrefix(auth[0], "AUTH PLAIN") {
+		t.Errorf("expected PLAIN authentication, got %v", auth)
+	}
+
+	// a rejected recipient doesn't cost the connection
+	if err := pool.Send(ctx, "from@example.com", []string{"bad@example.com"}, msg); err == nil {
+		t.Error("expected an error for a rejected recipient")
+	}
+	if err := pool.Send(ctx, "from@example.com", []string{"to@example.com"}, msg); err != nil {
+		t.Fatal(err)
+	}
+	if conns, _, _ := server.stats(); conns != 3 {
+		t.Errorf("expected 3 connections, got %d", conns)
+	}
+}
+
+func TestSMTPPoolContext(t *testing.T) {
+	server := newFakeSMTP(t, 0, false)
+	server.stall, server.stalled = make(chan struct{}), make(chan struct{}, 3)
+	defer server.ln.Close()
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{ApiKey: "testkey"}); err != nil {
+		t.Fatal(err)
+	}
+	pool := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String()}
+	msg := []byte("Subject: hi\r\n\r\nhello\r\n")
+
+	// a stalled server doesn't hold up Send past ctx's deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := pool.Send(ctx, "from@example.com", []string{"to@example.com"}, msg); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+	// or once it's cancelled
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-server.stalled
+		<-server.stalled
+		cancel()
+	}()
+	if err := pool.Send(ctx, "from@example.com", []string{"to@example.com"}, msg); err != context.Canceled {
+		t.Errorf("expected the send to be cancelled, got %v", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("sends took %s", d)
+	}
+
+	// a connection finished with after Close is closed, not returned to the pool
+	sent := make(chan error)
+	go func() {
+		sent <- pool.Send(context.Background(), "from@example.com", []string{"to@example.com"}, msg)
+	}()
+	<-server.stalled
+	pool.Close()
+	close(server.stall)
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		server.mu.Lock()
+		quit := len(server.commands) > 0 && server.commands[len(server.commands)-1] == "QUIT"
+		server.mu.Unlock()
+		if quit {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("expected the connection to be closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSMTPPoolReconnect(t *testing.T) {
+	server := newFakeSMTP(t, 2, false)
+	defer server.ln.Close()
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{ApiKey: "testkey"}); err != nil {
+		t.Fatal(err)
+	}
+	pool := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String(), Size: 1, IdleCheck: time.Hour}
+	defer pool.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := pool.Send(ctx, "from@example.com", []string{"to@example.com"}, []byte("hi\r\n")); err != nil {
+			t.Fatalf("message %d: %s", i, err)
+		}
+	}
+	if conns, messages, _ := server.stats(); conns != 3 || messages != 5 {
+		t.Errorf("expected 5 messages over 3 connections, got %d over %d", messages, conns)
+	}
+
+	pool2 := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String(), IdleCheck: time.Nanosecond}
+	defer pool2.Close()
+	for i := 0; i < 2; i++ {
+		if err := pool2.Send(ctx, "from@example.com", []string{"to@example.com"}, []byte("hi\r\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, noops := server.stats(); noops != 1 {
+		t.Errorf("expected the idle connection to be checked, got %d NOOPs", noops)
+	}
+}
+
+func TestSMTPPoolPipelining(t *testing.T) {
+	server := newFakeSMTP(t, 0, true)
+	defer server.ln.Close()
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{ApiKey: "testkey"}); err != nil {
+		t.Fatal(err)
+	}
+	pool := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String()}
+	defer pool.Close()
+
+	ctx := context.Background()
+	to := []string{"a@example.com", "b@example.com", "c@example.com"}
+	if err := pool.Send(ctx, "from@example.com", to, []byte("hi\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	// a rejected recipient in the middle of a pipeline leaves the connection usable
+	if err := pool.Send(ctx, "from@example.com", []string{"a@example.com", "bad@example.com", "c@example.com"}, []byte("hi\r\n")); err == nil {
+		t.Error("expected an error for a rejected recipient")
+	}
+	if err := pool.Send(ctx, "from@example.com", to, []byte("hi\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	server.mu.Lock()
+	pipelined, messages := server.pipelined, len(server.messages)
+	server.mu.Unlock()
+	if pipelined != 3 || messages != 2 {
+		t.Errorf("expected 2 messages with 3 pipelined envelopes, got %d and %d", messages, pipelined)
+	}
+	if conns, _, _ := server.stats(); conns != 1 {
+		t.Errorf("expected 1 connection, got %d", conns)
+	}
+}
+
+func TestSMTPPoolPipeliningInjection(t *testing.T) {
+	server := newFakeSMTP(t, 0, true)
+	defer server.ln.Close()
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{ApiKey: "testkey"}); err != nil {
+		t.Fatal(err)
+	}
+	pool := &sp.SMTPPool{Client: client, Addr: server.ln.Addr().String()}
+	defer pool.Close()
+
+	ctx := context.Background()
+	if err := pool.Send(ctx, "from@example.com", []string{"to@example.com"}, []byte("hi\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []struct {
+		from string
+		to   []string
+	}{
+		{"from@example.com>\r\nRCPT TO:<evil@example.com", []string{"to@example.com"}},
+		{"from@example.com", []string{"to@example.com>\nRCPT TO:<evil@example.com"}},
+	} {
+		if err := pool.Send(ctx, env.from, env.to, []byte("hi\r\n")); err == nil {
+			t.Errorf("expected an error for %q to %q", env.from, env.to)
+		}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, line := range server.commands {
+		if strings.Contains(line, "evil") {
+			t.Errorf("injected command reached the server: %q", line)
+		}
+		if strings.HasPrefix(line, "MAIL") && line != "MAIL FROM:<from@example.com> BODY=8BITMIME" {
+			t.Errorf("unexpected MAIL command %q", line)
+		}
+	}
+}
+
+func TestSMTPPoolStop(t *testing.T) {
+	pool := &sp.SMTPPool{}
+	if err := pool.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	pool.Stop(context.Background())
+	if err := pool.Start(context.Background()); err == nil {
+		t.Error("expected an error starting a closed SMTPPool")
+	}
+	if err := pool.Send(context.Background(), "a@example.com", []string{"b@example.com"}, nil); err == nil {
+		t.Error("expected an error sending on a closed SMTPPool")
+	} else if h := pool.Health(); h.LastError != err {
+		t.Errorf("expected Health to report %v, got %+v", err, h)
+	}
+}
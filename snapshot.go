@@ -0,0 +1,77 @@
+package gosparkpost
+
+import (
+	"context"
+	"sync"
+)
+
+// Snapshot gathers the account-wide information an admin dashboard typically shows.
+type Snapshot struct {
+	Account        *Account
+	Webhooks       []*WebhookItem
+	Templates      []Template
+	SendingDomains []SendingDomain
+	Suppressions   map[string]int
+}
+
+// Snapshot fetches account info, webhooks, template metadata, sending domains and the
+// suppression list summary concurrently, returning the first error encountered.
+// If ctx is done before all requests complete, Snapshot returns ctx.Err() without
+// waiting for the remaining requests, which will finish in the background.
+func (c *Client) Snapshot(ctx context.Context) (*Snapshot, error) {
+	snap := &Snapshot{}
+	fetches := []func() error{
+		func() (err error) {
+			snap.Account, _, err = c.Account()
+			return
+		},
+		func() error {
+			hooks, err := c.ListWebhooks(nil)
+			if err == nil {
+				snap.Webhooks = hooks.Results
+			}
+			return err
+		},
+		func() (err error) {
+			snap.Templates, _, err = c.Templates()
+			return
+		},
+		func() (err error) {
+			snap.SendingDomains, _, err = c.SendingDomains()
+			return
+		},
+		func() (err error) {
+			snap.Suppressions, err = c.SuppressionSummary()
+			return
+		},
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, fetch := range fetches {
+		wg.Add(1)
+		go func(fetch func() error) {
+			defer wg.Done()
+			if err := fetch(); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(fetch)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return snap, nil
+}
@@ -0,0 +1,38 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/account":                  `{"results":{"customer_id":123,"company_name":"Example"}}`,
+		"/api/v1/webhooks":                 `{"results":[{"id":"abc","name":"hook"}]}`,
+		"/api/v1/templates":                `{"results":[{"id":"welcome"},{"id":"reset"}]}`,
+		"/api/v1/sending-domains":          `{"results":[{"domain":"example.com","status":{"dkim_status":"valid"}}]}`,
+		"/api/v1/suppression-list/summary": `{"results":{"spam_complaint":2,"total":2}}`,
+	}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	defer done()
+
+	snap, err := client.Snapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Account.CustomerID != 123 {
+		t.Errorf("unexpected account %+v", snap.Account)
+	}
+	if len(snap.Webhooks) != 1 || len(snap.Templates) != 2 || len(snap.SendingDomains) != 1 || snap.Suppressions["total"] != 2 {
+		t.Errorf("unexpected snapshot %+v", snap)
+	}
+}
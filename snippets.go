@@ -0,0 +1,270 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://developers.sparkpost.com/api/snippets/
+// Snippets are still a labs feature; this becomes "/api/v%d/snippets" once they're generally available.
+var snippetsPathFormat = "/api/v%d/labs/snippets"
+
+// Snippet is a reusable block of content, included in templates with {{ render_snippet("id") }}.
+type Snippet struct {
+	ID                    string         `json:"id,omitempty"`
+	Name                  string         `json:"name,omitempty"`
+	Content               SnippetContent `json:"content"`
+	SharedWithSubaccounts bool           `json:"shared_with_subaccounts"`
+}
+
+// SnippetContent holds the versions of a Snippet used in html and text parts.
+type SnippetContent struct {
+	HTML string `json:"html,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+func (s *Snippet) String() string {
+	return fmt.Sprintf("%s %q", s.ID, s.Name)
+}
+
+// validate checks a Snippet has content, and isn't too big.
+func (s *Snippet) validate() error {
+	if s.Content.HTML == "" && s.Content.Text == "" {
+		return fmt.Errorf("Snippet requires HTML or Text content")
+	}
+	return checkSize("Snippet content", len(s.Content.HTML)+len(s.Content.Text), MaxSnippetContentBytes)
+}
+
+// SnippetCreate accepts a populated Snippet and creates it, returning its ID.
+// If ID is blank, SparkPost generates one from the Name.
+func (c *Client) SnippetCreate(s *Snippet) (id string, res *Response, err error) {
+	if s == nil {
+		err = fmt.Errorf("Create called with nil Snippet")
+		return
+	}
+	if err = s.validate(); err != nil {
+		return
+	}
+
+	jsonBytes, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(snippetsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var ok bool
+		id, ok = res.Results["id"].(string)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to Snippet creation")
+		}
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("Snippet", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// Snippets returns all Snippets, without their content.
+func (c *Client) Snippets() ([]Snippet, *Response, error) {
+	path := fmt.Sprintf(snippetsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		slist := map[string][]Snippet{}
+		if err = json.Unmarshal(body, &slist); err != nil {
+			return nil, res, err
+		} else if list, ok := slist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to Snippet list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Snippet", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// Snippet retrieves the Snippet with the specified id, including its content.
+func (c *Client) Snippet(id string) (*Snippet, *Response, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank id")
+	}
+
+	path := fmt.Sprintf(snippetsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*Snippet{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if s, ok := tmp["results"]; ok && s != nil {
+			return s, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to Snippet retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Snippet", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// SnippetUpdate replaces the name, content and sharing of the Snippet with the ID of the one provided.
+func (c *Client) SnippetUpdate(s *Snippet) (res *Response, err error) {
+	if s == nil {
+		err = fmt.Errorf("Update called with nil Snippet")
+		return
+	} else if s.ID == "" {
+		err = fmt.Errorf("Update called with blank id")
+		return
+	}
+	if err = s.validate(); err != nil {
+		return
+	}
+
+	tmp := *s
+	tmp.ID = ""
+	jsonBytes, err := json.Marshal(tmp)
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(snippetsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, s.ID)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("Snippet", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// SnippetDelete removes the Snippet with the specified id.
+func (c *Client) SnippetDelete(id string) (res *Response, err error) {
+	if id == "" {
+		err = fmt.Errorf("Delete called with blank id")
+		return
+	}
+
+	path := fmt.Sprintf(snippetsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("Snippet", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
@@ -0,0 +1,100 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSnippets(t *testing.T) {
+	var calls []string
+	bodies := map[string]map[string]interface{}{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		calls = append(calls, call)
+		if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+			var m map[string]interface{}
+			json.Unmarshal(body, &m)
+			bodies[call] = m
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(204)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "POST /api/v1/labs/snippets":
+			w.Write([]byte(`{"results":{"id":"footer"}}`))
+		case "GET /api/v1/labs/snippets":
+			w.Write([]byte(`{"results":[{"id":"footer","name":"Footer","shared_with_subaccounts":true}]}`))
+		case "GET /api/v1/labs/snippets/footer":
+			w.Write([]byte(`{"results":{"id":"footer","name":"Footer","content":{"html":"<p>Bye</p>","text":"Bye"},"shared_with_subaccounts":true}}`))
+		case "GET /api/v1/labs/snippets/missing":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found","code":"1600"}]}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, _, err := client.SnippetCreate(&sp.Snippet{ID: "empty"}); err == nil {
+		t.Error("expected an error without content")
+	}
+	big := &sp.Snippet{ID: "big", Content: sp.SnippetContent{HTML: strings.Repeat("x", sp.MaxSnippetContentBytes+1)}}
+	if _, _, err := client.SnippetCreate(big); err == nil {
+		t.Error("expected an error for oversized content")
+	}
+
+	s := &sp.Snippet{ID: "footer", Name: "Footer", Content: sp.SnippetContent{HTML: "<p>Bye</p>", Text: "Bye"}, SharedWithSubaccounts: true}
+	id, _, err := client.SnippetCreate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "footer" {
+		t.Errorf("unexpected id %q", id)
+	}
+	created := bodies["POST /api/v1/labs/snippets"]
+	content, _ := created["content"].(map[string]interface{})
+	if created["shared_with_subaccounts"] != true || content["html"] != "<p>Bye</p>" {
+		t.Errorf("unexpected request body %v", created)
+	}
+
+	list, _, err := client.Snippets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || !list[0].SharedWithSubaccounts {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	got, _, err := client.Snippet("footer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Content.Text != "Bye" || got.Name != "Footer" {
+		t.Errorf("unexpected snippet %+v", got)
+	}
+	if _, _, err = client.Snippet("missing"); err == nil {
+		t.Error("expected an error for a missing snippet")
+	}
+
+	got.Content.Text = "Goodbye"
+	if _, err = client.SnippetUpdate(got); err != nil {
+		t.Fatal(err)
+	}
+	if updated := bodies["PUT /api/v1/labs/snippets/footer"]; updated["id"] != nil || updated["content"].(map[string]interface{})["text"] != "Goodbye" {
+		t.Errorf("unexpected update body %v", updated)
+	}
+
+	if _, err = client.SnippetDelete("footer"); err != nil {
+		t.Fatal(err)
+	}
+	if last := calls[len(calls)-1]; last != "DELETE /api/v1/labs/snippets/footer" {
+		t.Errorf("unexpected call %s", last)
+	}
+}
@@ -0,0 +1,72 @@
+package gosparkpost
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// SplitOptions controls SendSplit.
+type SplitOptions struct {
+	// MaxInvalid is the largest fraction (0-1) of recipients which may be dropped so the rest
+	// can be sent. If more are invalid, the original error is returned. Defaults to 0.5.
+	MaxInvalid float64
+}
+
+// SendSplit is like Send, except that when the whole Transmission is rejected because some of
+// its inline recipients are invalid, it sends it again without them, so the valid recipients still
+// get mail. Recipients are treated as invalid if the API's errors mention their address, or
+// it doesn't parse. The recipients dropped are returned along with the result of the retry.
+// The caller's Transmission isn't modified.
+func (c *Client) SendSplit(t *Transmission, opts *SplitOptions) (id string, dropped []Recipient, res *Response, err error) {
+	id, res, err = c.Send(t)
+	if err == nil || res == nil || res.HTTP == nil {
+		return
+	}
+	if code := res.HTTP.StatusCode; code != 400 && code != 422 {
+		return
+	}
+
+	recips, rerr := inlineRecipients(t.Recipients)
+	if rerr != nil || len(recips) == 0 {
+		return
+	}
+	maxInvalid := 0.5
+	if opts != nil && opts.MaxInvalid > 0 {
+		maxInvalid = opts.MaxInvalid
+	}
+
+	var valid []Recipient
+	for _, r := range recips {
+		if invalidRecipient(r, res.Errors) {
+			dropped = append(dropped, r)
+		} else {
+			valid = append(valid, r)
+		}
+	}
+	if len(dropped) == 0 || len(valid) == 0 || float64(len(dropped))/float64(len(recips)) > maxInvalid {
+		return id, nil, res, err
+	}
+
+	tx := *t
+	tx.Recipients = valid
+	id, res, err = c.Send(&tx)
+	return
+}
+
+// invalidRecipient reports whether r's address doesn't parse, or is mentioned by errs.
+func invalidRecipient(r Recipient, errs []Error) bool {
+	addr, err := ParseAddress(r.Address)
+	if err != nil {
+		return true
+	}
+	if _, err = mail.ParseAddress(addr.Email); err != nil {
+		return true
+	}
+	email := strings.ToLower(addr.Email)
+	for _, e := range errs {
+		if strings.Contains(strings.ToLower(e.Message+" "+e.Description), email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSendSplit(t *testing.T) {
+	var sent [][]string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var tx struct {
+			Recipients []struct {
+				Address struct {
+					Email string `json:"email"`
+				} `json:"address"`
+			} `json:"recipients"`
+		}
+		json.Unmarshal(body, &tx)
+		var emails []string
+		for _, r := range tx.Recipients {
+			emails = append(emails, r.Address.Email)
+		}
+		sent = append(sent, emails)
+
+		w.Header().Set("Content-Type", "application/json")
+		for _, e := range emails {
+			if e == "bounced@example" || e == "not an address" {
+				w.WriteHeader(422)
+				w.Write([]byte(`{"errors":[{"message":"Invalid recipient","code":"5002","description":"Recipient address 'bounced@example' is invalid"}]}`))
+				return
+			}
+		}
+		w.Write([]byte(`{"results":{"id":"11668787484950529","total_accepted_recipients":2,"total_rejected_recipients":0}}`))
+	})
+	defer done()
+
+	addr := func(email string) sp.Recipient { return sp.Recipient{Address: sp.Address{Email: email}} }
+	tx := &sp.Transmission{
+		Recipients: []sp.Recipient{addr("a@example.com"), addr("bounced@example"), addr("not an address"), addr("b@example.com"), addr("c@example.com")},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hi"},
+	}
+	id, dropped, _, err := client.SendSplit(tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "11668787484950529" || len(sent) != 2 || len(sent[1]) != 3 {
+		t.Errorf("unexpected sends %v", sent)
+	}
+	if len(dropped) != 2 || len(tx.Recipients.([]sp.Recipient)) != 5 {
+		t.Errorf("unexpected dropped recipients %v", dropped)
+	}
+
+	// too many invalid recipients to retry
+	sent = nil
+	tx.Recipients = []sp.Recipient{addr("bounced@example"), addr("not an address"), addr("a@example.com")}
+	if _, dropped, _, err = client.SendSplit(tx, nil); err == nil || dropped != nil {
+		t.Errorf("expected the original error, got %v, %v", err, dropped)
+	}
+	if len(sent) != 1 {
+		t.Errorf("expected no retry, got %v", sent)
+	}
+
+	sent = nil
+	if _, dropped, _, err = client.SendSplit(tx, &sp.SplitOptions{MaxInvalid: 0.8}); err != nil || len(dropped) != 2 {
+		t.Errorf("unexpected result %v, %v", err, dropped)
+	}
+}
@@ -0,0 +1,96 @@
+package sptest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// Transmissions returns the Transmissions POSTed to the Server so far.
+// Recipients are decoded as []sp.Recipient, or as a map for stored lists.
+func (s *Server) Transmissions() []sp.Transmission {
+	var txs []sp.Transmission
+	for _, req := range s.Requests() {
+		if req.Method != "POST" || req.Path != "/api/v1/transmissions" {
+			continue
+		}
+
+		var raw struct {
+			sp.Transmission
+			Recipients json.RawMessage `json:"recipients"`
+		}
+		if err := json.Unmarshal(req.Body, &raw); err != nil {
+			continue
+		}
+		tx := raw.Transmission
+		var list []sp.Recipient
+		if err := json.Unmarshal(raw.Recipients, &list); err == nil {
+			tx.Recipients = list
+		} else {
+			var stored map[string]string
+			json.Unmarshal(raw.Recipients, &stored)
+			tx.Recipients = stored
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// AssertTransmissionSent fails the test unless a single Transmission was sent
+// to all of the provided email addresses, and returns it.
+func (s *Server) AssertTransmissionSent(t testing.TB, emails ...string) *sp.Transmission {
+	t.Helper()
+	txs := s.Transmissions()
+	for i := range txs {
+		list, ok := txs[i].Recipients.([]sp.Recipient)
+		if !ok {
+			continue
+		}
+		sentTo := map[string]bool{}
+		for _, r := range list {
+			if addr, err := sp.ParseAddress(r.Address); err == nil {
+				sentTo[strings.ToLower(addr.Email)] = true
+			}
+		}
+		all := true
+		for _, email := range emails {
+			all = all && sentTo[strings.ToLower(email)]
+		}
+		if all {
+			return &txs[i]
+		}
+	}
+	t.Errorf("no Transmission was sent to all of %v (%d Transmissions sent)", emails, len(txs))
+	return nil
+}
+
+// AssertSuppressed fails the test unless each of the provided email addresses
+// was added to the suppression list.
+func (s *Server) AssertSuppressed(t testing.TB, emails ...string) {
+	t.Helper()
+	suppressed := map[string]bool{}
+	for _, req := range s.Requests() {
+		if req.Method != "PUT" || !strings.HasPrefix(req.Path, "/api/v1/suppression-list") {
+			continue
+		}
+		if email := strings.TrimPrefix(req.Path, "/api/v1/suppression-list/"); email != req.Path {
+			suppressed[strings.ToLower(email)] = true
+			continue
+		}
+		var list sp.SuppressionListWrapper
+		if err := json.Unmarshal(req.Body, &list); err != nil {
+			continue
+		}
+		for _, entry := range list.Recipients {
+			suppressed[strings.ToLower(entry.Email+entry.Recipient)] = true
+		}
+	}
+
+	for _, email := range emails {
+		if !suppressed[strings.ToLower(email)] {
+			t.Errorf("%s was not added to the suppression list", email)
+		}
+	}
+}
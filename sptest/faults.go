@@ -0,0 +1,96 @@
+package sptest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Fault describes a misbehaviour the Server should exhibit for an endpoint.
+// Faults are registered with Inject.
+type Fault struct {
+	// Status is the HTTP status code returned, along with a SparkPost-style error body.
+	// If zero, the normal response is sent (after Delay), unless Malformed is set.
+	Status int
+	// RetryAfter, if non-zero, is sent in a Retry-After header (rounded up to whole seconds).
+	RetryAfter time.Duration
+	// Delay is how long to wait before responding.
+	// The wait is cut short if the client gives up on the request.
+	Delay time.Duration
+	// Malformed makes the response body truncated JSON.
+	Malformed bool
+	// Times limits how many requests the fault applies to; 0 means all of them.
+	Times int
+}
+
+// RateLimited returns a Fault which responds with a 429, asking the client to wait.
+func RateLimited(retryAfter time.Duration) Fault {
+	return Fault{Status: http.StatusTooManyRequests, RetryAfter: retryAfter}
+}
+
+// Inject makes requests with the provided method and path misbehave as described by f.
+// It replaces any Fault previously injected for the endpoint, and takes precedence over Handle.
+func (s *Server) Inject(method, path string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[method+" "+path] = &f
+}
+
+// ClearFaults removes all injected Faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[string]*Fault{}
+}
+
+// takeFault returns the Fault for key, if any, counting it against Times.
+// Callers must hold s.mu.
+func (s *Server) takeFault(key string) *Fault {
+	f := s.faults[key]
+	if f == nil {
+		return nil
+	}
+	if f.Times > 0 {
+		f.Times--
+		if f.Times == 0 {
+			delete(s.faults, key)
+		}
+	}
+	copied := *f
+	return &copied
+}
+
+// inject applies the fault, returning true if the response has been written.
+func (f *Fault) inject(w http.ResponseWriter, r *http.Request) bool {
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if f.RetryAfter > 0 {
+		secs := int((f.RetryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+	}
+
+	switch {
+	case f.Malformed:
+		w.Header().Set("Content-Type", "application/json")
+		status := f.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"results":{"id":`))
+	case f.Status != 0:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.Status)
+		fmt.Fprintf(w, `{"errors":[{"message":"%s","code":"%d"}]}`, http.StatusText(f.Status), f.Status)
+	default:
+		return false
+	}
+	return true
+}
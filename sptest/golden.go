@@ -0,0 +1,127 @@
+package sptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable which, when set to a non-empty value,
+// makes AssertGolden (re)write golden files instead of comparing against them.
+const UpdateGoldenEnv = "SPTEST_UPDATE_GOLDEN"
+
+// goldenRequest is the stable representation of a Request in golden files.
+// Headers are left out, since they include things like the library version.
+type goldenRequest struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Query  map[string][]string `json:"query,omitempty"`
+	Body   interface{}         `json:"body,omitempty"`
+}
+
+// Golden returns the recorded requests in the format used for golden files.
+// JSON bodies are re-encoded with sorted keys, so the output is stable.
+func (s *Server) Golden() ([]byte, error) {
+	reqs := s.Requests()
+	out := make([]goldenRequest, len(reqs))
+	for i, req := range reqs {
+		out[i] = goldenRequest{Method: req.Method, Path: req.Path}
+		if len(req.Query) > 0 {
+			out[i].Query = req.Query
+		}
+		if len(req.Body) > 0 {
+			var body interface{}
+			if err := json.Unmarshal(req.Body, &body); err != nil {
+				body = string(req.Body)
+			}
+			out[i].Body = body
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(jsonBytes, '\n'), nil
+}
+
+// WriteGolden saves the recorded requests to the file at path.
+func (s *Server) WriteGolden(path string) error {
+	golden, err := s.Golden()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, golden, 0644)
+}
+
+// AssertGolden fails the test if the recorded requests differ from those in
+// the golden file at path, reporting a line diff. If UpdateGoldenEnv is set,
+// the golden file is written instead.
+func (s *Server) AssertGolden(t testing.TB, path string) {
+	t.Helper()
+	got, err := s.Golden()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err = ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s (set %s=1 to create it)", err, UpdateGoldenEnv)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("requests differ from %s (-golden +got):\n%s", path, Diff(string(want), string(got)))
+	}
+}
+
+// Diff returns a line-by-line diff of a and b, with removed lines prefixed by "-",
+// added lines by "+", and unchanged lines by a space.
+func Diff(a, b string) string {
+	al := strings.Split(a, "\n")
+	bl := strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of al[i:] and bl[j:]
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	i, j := 0, 0
+	for i < len(al) || j < len(bl) {
+		switch {
+		case i < len(al) && j < len(bl) && al[i] == bl[j]:
+			fmt.Fprintf(&buf, " %s\n", al[i])
+			i++
+			j++
+		case j < len(bl) && (i == len(al) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Fprintf(&buf, "+%s\n", bl[j])
+			j++
+		default:
+			fmt.Fprintf(&buf, "-%s\n", al[i])
+			i++
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,199 @@
+package sptest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// Soak generates synthetic event batches at a steady rate, for checking that a
+// webhook handler or event poller keeps up without dropping events or leaking memory.
+type Soak struct {
+	// Rate is how many batches are generated per second; 0 means as fast as possible.
+	Rate int
+	// BatchSize is the number of events per batch. Defaults to 100.
+	BatchSize int
+	// Duration is how long events are generated for. Defaults to 10 seconds.
+	Duration time.Duration
+	// Settle is how long to wait, once generation stops, for the consumer to catch up.
+	// Defaults to 5 seconds.
+	Settle time.Duration
+	// Event returns the JSON for the i'th event. By default, each event is a
+	// list_unsubscribe for a different recipient.
+	Event func(i int) string
+}
+
+// SoakResult summarizes a soak run.
+type SoakResult struct {
+	Generated     int
+	Delivered     int
+	Batches       int
+	FailedBatches int
+	Elapsed       time.Duration
+	// HeapGrowth is the change in live heap bytes over the run, measured after garbage collection.
+	HeapGrowth int64
+}
+
+// Throughput is the number of events delivered per second.
+func (r SoakResult) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Delivered) / r.Elapsed.Seconds()
+}
+
+// DropRate is the fraction of generated events which weren't delivered.
+func (r SoakResult) DropRate() float64 {
+	if r.Generated == 0 {
+		return 0
+	}
+	return 1 - float64(r.Delivered)/float64(r.Generated)
+}
+
+func (r SoakResult) String() string {
+	return fmt.Sprintf("%d/%d events delivered in %s (%.0f/s, %.2f%% dropped, %d/%d batches failed, heap %+d bytes)",
+		r.Delivered, r.Generated, r.Elapsed, r.Throughput(), r.DropRate()*100,
+		r.FailedBatches, r.Batches, r.HeapGrowth)
+}
+
+func (s Soak) defaults() Soak {
+	if s.BatchSize <= 0 {
+		s.BatchSize = 100
+	}
+	if s.Duration <= 0 {
+		s.Duration = 10 * time.Second
+	}
+	if s.Settle <= 0 {
+		s.Settle = 5 * time.Second
+	}
+	if s.Event == nil {
+		s.Event = func(i int) string {
+			return fmt.Sprintf(`{"type":"list_unsubscribe","rcpt_to":"soak%d@example.com","campaign_id":"soak","timestamp":"%d"}`,
+				i, time.Now().Unix())
+		}
+	}
+	return s
+}
+
+// generate calls batch with the raw events of each batch, at the configured rate.
+func (s Soak) generate(batch func(evs []string) bool) (res SoakResult) {
+	var tick <-chan time.Time
+	if s.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(s.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	n := 0
+	deadline := time.Now().Add(s.Duration)
+	for time.Now().Before(deadline) {
+		if tick != nil {
+			<-tick
+		}
+		evs := make([]string, s.BatchSize)
+		for i := range evs {
+			evs[i] = s.Event(n)
+			n++
+		}
+		res.Batches++
+		res.Generated += len(evs)
+		if !batch(evs) {
+			res.FailedBatches++
+		}
+	}
+	return
+}
+
+// settle waits until delivered stops changing, or Settle elapses.
+func (s Soak) settle(generated int, delivered func() int) int {
+	deadline := time.Now().Add(s.Settle)
+	for {
+		got := delivered()
+		if got >= generated || time.Now().After(deadline) {
+			return got
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func heapAlloc() int64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
+
+// Webhook posts batches to the handler as a SparkPost webhook would. The delivered
+// func reports how many events the application has received so far.
+func (s Soak) Webhook(h http.Handler, delivered func() int) SoakResult {
+	s = s.defaults()
+	heap := heapAlloc()
+	start := time.Now()
+
+	res := s.generate(func(evs []string) bool {
+		var body bytes.Buffer
+		body.WriteString("[")
+		for i, ev := range evs {
+			if i > 0 {
+				body.WriteString(",")
+			}
+			fmt.Fprintf(&body, `{"msys":{"event":%s}}`, ev)
+		}
+		body.WriteString("]")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/", &body))
+		return w.Code >= 200 && w.Code < 300
+	})
+
+	res.Delivered = s.settle(res.Generated, delivered)
+	res.Elapsed = time.Since(start)
+	res.HeapGrowth = heapAlloc() - heap
+	return res
+}
+
+// Poller queues batches to be returned by the Server's Message Events API, and runs
+// the poller (which should be using a Client from this Server) until they've been consumed.
+// The delivered func reports how many events the application has received so far.
+func (s Soak) Poller(server *Server, poller sp.Component, delivered func() int) (SoakResult, error) {
+	s = s.defaults()
+
+	var mu sync.Mutex
+	var queue []string
+	server.Handle("GET", "/api/v1/message-events", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		evs := queue
+		queue = nil
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"results":[%s],"total_count":%d}`, strings.Join(evs, ","), len(evs))
+	})
+
+	heap := heapAlloc()
+	start := time.Now()
+	ctx := context.Background()
+	if err := poller.Start(ctx); err != nil {
+		return SoakResult{}, err
+	}
+
+	res := s.generate(func(evs []string) bool {
+		mu.Lock()
+		queue = append(queue, evs...)
+		mu.Unlock()
+		return true
+	})
+
+	res.Delivered = s.settle(res.Generated, delivered)
+	res.Elapsed = time.Since(start)
+	err := poller.Stop(ctx)
+	res.HeapGrowth = heapAlloc() - heap
+	return res, err
+}
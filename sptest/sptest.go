@@ -0,0 +1,130 @@
+// Package sptest provides a mock SparkPost API server and assertion helpers,
+// so code using gosparkpost can be tested without talking to SparkPost.
+package sptest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+// Request is a copy of a request received by the Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a mock SparkPost API. Every request is recorded, and answered
+// by a handler registered with Handle if there is one. Otherwise:
+// POSTs to the Transmissions API get a new transmission id, other GETs get
+// an empty list of results, and everything else gets an empty results object.
+type Server struct {
+	*httptest.Server
+
+	// Keep limits how many of the most recent requests are remembered; 0 means all of them.
+	Keep int
+
+	mu       sync.Mutex
+	requests []Request
+	handlers map[string]http.HandlerFunc
+	faults   map[string]*Fault
+	nextID   int
+}
+
+// NewServer starts a mock SparkPost API. Call Close when done with it.
+func NewServer() *Server {
+	s := &Server{handlers: map[string]http.HandlerFunc{}, faults: map[string]*Fault{}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Client returns a gosparkpost Client configured to talk to this Server.
+func (s *Server) Client() (*sp.Client, error) {
+	client := &sp.Client{Client: s.Server.Client()}
+	err := client.Init(&sp.Config{BaseUrl: s.URL, ApiKey: "sptest"})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Handle overrides the response to requests with the provided method and path,
+// for example Handle("GET", "/api/v1/templates", ...).
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequests returns (up to) the n most recent requests, oldest first.
+func (s *Server) LastRequests(n int) []Request {
+	reqs := s.Requests()
+	if n < len(reqs) {
+		reqs = reqs[len(reqs)-n:]
+	}
+	return reqs
+}
+
+// Reset forgets all recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   body,
+	})
+	if s.Keep > 0 && len(s.requests) > s.Keep {
+		s.requests = s.requests[len(s.requests)-s.Keep:]
+	}
+	handler := s.handlers[r.Method+" "+r.URL.Path]
+	fault := s.takeFault(r.Method + " " + r.URL.Path)
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+
+	if fault != nil && fault.inject(w, r) {
+		return
+	}
+
+	if handler != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		handler(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/transmissions":
+		fmt.Fprintf(w, `{"results":{"id":"%d","total_rejected_recipients":0}}`, id)
+	case r.Method == "GET":
+		w.Write([]byte(`{"results":[]}`))
+	default:
+		w.Write([]byte(`{"results":{}}`))
+	}
+}
@@ -0,0 +1,158 @@
+package sptest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Keep = 2
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, _, err := client.Send(&sp.Transmission{
+		Recipients: []string{"a@example.com", "b@example.com"},
+		Content:    sp.Content{Subject: "sptest", Text: "sptest", From: "test@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == "" {
+		t.Error("expected a transmission id")
+	}
+	server.AssertTransmissionSent(t, "a@example.com", "B@example.com")
+
+	err = client.SuppressionInsertOrUpdate([]sp.SuppressionEntry{{Email: "c@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AssertSuppressed(t, "c@example.com")
+
+	if _, _, err = client.Templates(); err != nil {
+		t.Fatal(err)
+	}
+	last := server.LastRequests(5)
+	if len(last) != 2 || last[0].Method != "PUT" || last[1].Method != "GET" {
+		t.Errorf("expected the last two requests to be kept, got %+v", last)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Send(&sp.Transmission{
+		CampaignID: "golden",
+		Recipients: []string{"a@example.com"},
+		Content:    sp.Content{Subject: "sptest", Text: "sptest", From: "test@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AssertGolden(t, "testdata/send.golden")
+}
+
+func TestDiff(t *testing.T) {
+	diff := Diff("a\nb\nc", "a\nc\nd")
+	if diff != " a\n-b\n c\n+d\n" {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestInject(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &sp.Transmission{
+		Recipients: []string{"a@example.com"},
+		Content:    sp.Content{Subject: "sptest", Text: "sptest", From: "test@example.com"},
+	}
+
+	f := RateLimited(1500 * time.Millisecond)
+	f.Times = 1
+	server.Inject("POST", "/api/v1/transmissions", f)
+	_, res, err := client.Send(tx)
+	if err == nil {
+		t.Fatal("expected an error for a rate limited request")
+	}
+	if res.HTTP.StatusCode != 429 || res.HTTP.Header.Get("Retry-After") != "2" {
+		t.Errorf("expected a 429 with Retry-After: 2, got %d %q",
+			res.HTTP.StatusCode, res.HTTP.Header.Get("Retry-After"))
+	}
+	if _, _, err = client.Send(tx); err != nil {
+		t.Errorf("expected the fault to apply once, got %s", err)
+	}
+
+	server.Inject("POST", "/api/v1/transmissions", Fault{Malformed: true, Delay: 20 * time.Millisecond})
+	start := time.Now()
+	if _, _, err = client.Send(tx); err == nil {
+		t.Error("expected an error for a malformed response")
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("expected the response to be delayed")
+	}
+
+	server.ClearFaults()
+	if _, _, err = client.Send(tx); err != nil {
+		t.Errorf("expected no error after ClearFaults, got %s", err)
+	}
+}
+
+func TestSoak(t *testing.T) {
+	soak := Soak{Rate: 50, BatchSize: 10, Duration: 200 * time.Millisecond, Settle: 2 * time.Second}
+
+	var mu sync.Mutex
+	count := 0
+	delivered := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+	onUnsubscribe := func(email, campaign string, at time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		return nil
+	}
+
+	res := soak.Webhook(&sp.UnsubscribeSync{OnUnsubscribe: onUnsubscribe}, delivered)
+	if res.Generated == 0 || res.DropRate() != 0 || res.FailedBatches != 0 {
+		t.Errorf("webhook: %s", res)
+	}
+
+	server := NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a new UnsubscribeSync, since the default events would be deduplicated
+	unsub := &sp.UnsubscribeSync{Client: client, OnUnsubscribe: onUnsubscribe, Interval: 20 * time.Millisecond}
+	mu.Lock()
+	count = 0
+	mu.Unlock()
+
+	res, err = soak.Poller(server, unsub, delivered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Generated == 0 || res.DropRate() != 0 {
+		t.Errorf("poller: %s (%+v)", res, unsub.Health())
+	}
+}
@@ -1,24 +1,39 @@
 package gosparkpost
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 // https://www.sparkpost.com/api#/reference/subaccounts
 var subaccountsPathFormat = "/api/v%d/subaccounts"
-var availableGrants = []string{
-	"smtp/inject",
-	"sending_domains/manage",
-	"message_events/view",
-	"suppression_lists/manage",
-	"transmissions/view",
-	"transmissions/modify",
+
+// SubaccountHeader makes an API call on behalf of the Subaccount whose id it holds.
+const SubaccountHeader = "X-MSYS-SUBACCOUNT"
+
+// availableGrants are given to Subaccount keys created without any Grants.
+var availableGrants = []Grant{
+	GrantSMTPInject,
+	GrantSendingDomainsManage,
+	GrantMessageEventsView,
+	GrantSuppressionListsManage,
+	GrantTransmissionsView,
+	GrantTransmissionsModify,
 }
+
+// Subaccount statuses. Terminated Subaccounts can't be reactivated.
+const (
+	SubaccountActive     = "active"
+	SubaccountSuspended  = "suspended"
+	SubaccountTerminated = "terminated"
+)
+
 var validStatuses = []string{
-	"active",
-	"suspended",
-	"terminated",
+	SubaccountActive,
+	SubaccountSuspended,
+	SubaccountTerminated,
 }
 
 // Subaccount is the JSON structure accepted by and returned from the SparkPost Subaccounts API.
@@ -33,6 +48,28 @@ type Subaccount struct {
 	ComplianceStatus string   `json:"compliance_status,omitempty"`
 }
 
+func (s *Subaccount) String() string {
+	if s == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%d %q (%s)", s.ID, s.Name, s.Status)
+}
+
+// Equal reports whether two Subaccounts are the same, ignoring the order of Grants.
+func (s *Subaccount) Equal(o *Subaccount) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.ID == o.ID &&
+		s.Name == o.Name &&
+		s.Key == o.Key &&
+		s.KeyLabel == o.KeyLabel &&
+		sameStrings(s.Grants, o.Grants) &&
+		s.ShortKey == o.ShortKey &&
+		s.Status == o.Status &&
+		s.ComplianceStatus == o.ComplianceStatus
+}
+
 // Create accepts a populated Subaccount object, validates it,
 // and performs an API call against the configured endpoint.
 func (c *Client) SubaccountCreate(s *Subaccount) (res *Response, err error) {
@@ -56,6 +93,8 @@ func (c *Client) SubaccountCreate(s *Subaccount) (res *Response, err error) {
 
 	if len(s.Grants) == 0 {
 		s.Grants = availableGrants
+	} else if err = c.validateGrants(s.Grants); err != nil {
+		return
 	}
 
 	jsonBytes, err := json.Marshal(s)
@@ -138,8 +177,8 @@ func (c *Client) SubaccountUpdate(s *Subaccount) (res *Response, err error) {
 		return
 	}
 
-	path := fmt.Sprintf(templatesPathFormat, c.Config.ApiVersion)
-	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, s.ID)
+	path := fmt.Sprintf(subaccountsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%d", c.Config.BaseUrl, path, s.ID)
 
 	res, err = c.HttpPut(url, jsonBytes)
 	if err != nil {
@@ -165,9 +204,9 @@ func (c *Client) SubaccountUpdate(s *Subaccount) (res *Response, err error) {
 			return
 		}
 
-		// handle template-specific ones
+		// handle subaccount-specific ones
 		if res.HTTP.StatusCode == 409 {
-			err = fmt.Errorf("Subaccount with id [%s] is in use by msg generation", s.ID)
+			err = fmt.Errorf("Subaccount with id [%d] is in use by msg generation", s.ID)
 		} else { // everything else
 			err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 		}
@@ -176,10 +215,130 @@ func (c *Client) SubaccountUpdate(s *Subaccount) (res *Response, err error) {
 	return
 }
 
-// List returns metadata for all Templates in the system.
+// SubaccountStatusError is returned when the API refuses to change a Subaccount's status,
+// for example because it's already terminated.
+type SubaccountStatusError struct {
+	ID         int
+	Status     string
+	StatusCode int
+	Err        Error
+}
+
+func (e *SubaccountStatusError) Error() string {
+	return fmt.Sprintf("Can't set status of Subaccount [%d] to %s: %d: %s",
+		e.ID, e.Status, e.StatusCode, e.Err.Message)
+}
+
+// SubaccountSuspend stops the Subaccount with the specified id from sending.
+func (c *Client) SubaccountSuspend(id int) (*Response, error) {
+	return c.subaccountStatus(id, SubaccountSuspended)
+}
+
+// SubaccountTerminate permanently closes the Subaccount with the specified id.
+func (c *Client) SubaccountTerminate(id int) (*Response, error) {
+	return c.subaccountStatus(id, SubaccountTerminated)
+}
+
+// SubaccountReactivate lets a suspended Subaccount send again.
+func (c *Client) SubaccountReactivate(id int) (*Response, error) {
+	return c.subaccountStatus(id, SubaccountActive)
+}
+
+// subaccountStatus updates only the status of a Subaccount. Refusals are returned
+// as a *SubaccountStatusError.
+func (c *Client) subaccountStatus(id int, status string) (res *Response, err error) {
+	if id <= 0 {
+		err = fmt.Errorf("Subaccount status update called with invalid id [%d]", id)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(map[string]string{"status": status})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(subaccountsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%d", c.Config.BaseUrl, path, id)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	if err = res.ParseResponse(); err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+	}
+	if code := res.HTTP.StatusCode; len(res.Errors) > 0 && (code == 400 || code == 404 || code == 409 || code == 422) {
+		err = &SubaccountStatusError{ID: id, Status: status, StatusCode: code, Err: res.Errors[0]}
+		return
+	}
+	if err = res.PrettyError("Subaccount", "update"); err != nil {
+		return
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	return
+}
+
+// Subaccounts returns the first page of Subaccounts, which is every one for most accounts.
+// Use SubaccountsAll to read them all.
 func (c *Client) Subaccounts() (subaccounts []Subaccount, res *Response, err error) {
+	return c.SubaccountList(nil)
+}
+
+// SubaccountListOptions pages through SubaccountList. Zero values are left for the API to default.
+type SubaccountListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// DefaultSubaccountPageSize is the page size SubaccountsAll uses when it's passed zero.
+const DefaultSubaccountPageSize = 100
+
+// SubaccountsAll returns every Subaccount, requesting pageSize of them at a time.
+// If the API ignores paging and returns every Subaccount each time, that's noticed, by
+// a page which is too big or which starts again, and the Subaccounts are returned once.
+func (c *Client) SubaccountsAll(pageSize int) ([]Subaccount, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultSubaccountPageSize
+	}
+	var all []Subaccount
+	firsts := map[int]bool{}
+	for {
+		page, _, err := c.SubaccountList(&SubaccountListOptions{Limit: pageSize, Offset: len(all)})
+		if err != nil {
+			return nil, err
+		}
+		if len(page) > pageSize {
+			// not paged: this is all of them
+			return page, nil
+		} else if len(page) == 0 || firsts[page[0].ID] {
+			return all, nil
+		}
+		firsts[page[0].ID] = true
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// SubaccountList returns the page of Subaccounts described by opts, which may be nil.
+func (c *Client) SubaccountList(opts *SubaccountListOptions) (subaccounts []Subaccount, res *Response, err error) {
+	if opts == nil {
+		opts = &SubaccountListOptions{}
+	}
 	path := fmt.Sprintf(subaccountsPathFormat, c.Config.ApiVersion)
-	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	url := QueryBuilder{}.
+		Int("limit", opts.Limit).
+		Int("offset", opts.Offset).
+		URL(c.Config.BaseUrl + path)
 	res, err = c.HttpGet(url)
 	if err != nil {
 		return
@@ -273,3 +432,39 @@ func (c *Client) Subaccount(id int) (subaccount *Subaccount, res *Response, err
 
 	return
 }
+
+// ForSubaccount returns a Client which makes every API call on behalf of the Subaccount with
+// the specified id. It shares c's Config and http.Client, and starts with a copy of c's headers;
+// changes to either Client's headers don't affect the other.
+func (c *Client) ForSubaccount(id int) *Client {
+	sub := &Client{
+		Config:       c.Config,
+		Client:       c.Client,
+		headers:      make(map[string]string, len(c.headers)+1),
+		agents:       append([]string(nil), c.agents...),
+		Dedupe:       c.Dedupe,
+		Latency:      c.Latency,
+		Audit:        c.Audit,
+		Injection:    c.Injection,
+		Signer:       c.Signer,
+		Limiter:      c.Limiter,
+		Archive:      c.Archive,
+		FrequencyCap: c.FrequencyCap,
+		BouncePolicy: c.BouncePolicy,
+	}
+	for header, value := range c.headers {
+		sub.headers[header] = value
+	}
+	sub.headers[SubaccountHeader] = strconv.Itoa(id)
+	return sub
+}
+
+type subaccountKey struct{}
+
+// WithSubaccount returns a copy of ctx which makes the API calls passed it, such as
+// DoRequestContext and SuppressionInsertOrUpdateContext, on behalf of the Subaccount with
+// the specified id. It overrides a Client's own headers, but not headers passed to a call.
+// ForSubaccount covers calls which don't take a context.
+func WithSubaccount(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, subaccountKey{}, id)
+}
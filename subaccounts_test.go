@@ -0,0 +1,234 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSubaccountsAll(t *testing.T) {
+	var queries []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/subaccounts" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var page []sp.Subaccount
+		for id := offset + 1; id <= 5 && len(page) < limit; id++ {
+			page = append(page, sp.Subaccount{ID: id, Name: "sub " + strconv.Itoa(id)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": page})
+	})
+	defer done()
+
+	all, err := client.SubaccountsAll(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 || all[0].ID != 1 || all[4].ID != 5 {
+		t.Errorf("unexpected subaccounts %v", all)
+	}
+	expected := []string{"limit=2", "limit=2&offset=2", "limit=2&offset=4"}
+	if len(queries) != len(expected) {
+		t.Fatalf("unexpected queries %q", queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d: got %q, want %q", i, queries[i], expected[i])
+		}
+	}
+}
+
+func TestSubaccountsAllUnpaged(t *testing.T) {
+	for _, total := range []int{3, 4} {
+		requests := 0
+		client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			// limit and offset are ignored
+			requests++
+			var subs []sp.Subaccount
+			for id := 1; id <= total; id++ {
+				subs = append(subs, sp.Subaccount{ID: id})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": subs})
+		})
+
+		all, err := client.SubaccountsAll(2)
+		done()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != total || requests > 2 {
+			t.Errorf("%d subaccounts: got %d after %d requests", total, len(all), requests)
+		}
+	}
+
+	// a page exactly the right size, repeated
+	requests := 0
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"subaccount_id":1},{"subaccount_id":2}]}`))
+	})
+	defer done()
+	all, err := client.SubaccountsAll(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || requests != 2 {
+		t.Errorf("got %v after %d requests", all, requests)
+	}
+}
+
+func TestSubaccountStatus(t *testing.T) {
+	var paths, bodies []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/subaccounts/9" {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"errors":[{"message":"subaccount is terminated"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":{"message":"Successfully updated subaccount information"}}`))
+	})
+	defer done()
+
+	if _, err := client.SubaccountSuspend(123); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SubaccountTerminate(123); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0] != "PUT /api/v1/subaccounts/123" ||
+		bodies[0] != `{"status":"suspended"}` || bodies[1] != `{"status":"terminated"}` {
+		t.Errorf("unexpected requests %q %q", paths, bodies)
+	}
+
+	_, err := client.SubaccountReactivate(9)
+	serr, ok := err.(*sp.SubaccountStatusError)
+	if !ok || serr.ID != 9 || serr.Status != sp.SubaccountActive || serr.StatusCode != 409 {
+		t.Errorf("expected a *SubaccountStatusError, got %#v", err)
+	}
+	if _, err = client.SubaccountSuspend(0); err == nil || len(paths) != 3 {
+		t.Error("expected an invalid id to be rejected without a request")
+	}
+}
+
+func TestSubaccountCreateGrants(t *testing.T) {
+	var created []sp.Subaccount
+	var grantLookups int
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/authenticate/grants" {
+			grantLookups++
+			w.Write([]byte(`{"results":["smtp/inject",{"key":"shiny/new"}]}`))
+			return
+		}
+		var s sp.Subaccount
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &s)
+		created = append(created, s)
+		w.Write([]byte(`{"results":{"subaccount_id":7,"short_key":"abcd"}}`))
+	})
+	defer done()
+
+	grants, _, err := client.Grants()
+	if err != nil || len(grants) != 2 || grants[1] != "shiny/new" {
+		t.Fatalf("unexpected grants %v, %v", grants, err)
+	}
+
+	for idx, test := range []struct {
+		grants  []string
+		err     bool
+		lookups int
+	}{
+		{[]string{sp.GrantSMTPInject, sp.GrantTemplatesView}, false, 1},
+		{[]string{sp.GrantSMTPInject, "shiny/new"}, false, 2},
+		{[]string{"transmissions/modfiy"}, true, 3},
+	} {
+		sub := &sp.Subaccount{Name: "sub", KeyLabel: "key", Grants: test.grants}
+		_, err := client.SubaccountCreate(sub)
+		if (err != nil) != test.err || grantLookups != test.lookups {
+			t.Errorf("SubaccountCreate[%d] => err %v after %d grant lookups", idx, err, grantLookups)
+		}
+	}
+	if len(created) != 2 {
+		t.Errorf("expected 2 subaccounts created, got %d", len(created))
+	}
+}
+
+func TestSubaccountScoping(t *testing.T) {
+	var got []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(sp.SubaccountHeader)+" "+r.Header.Get("X-Team"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[]}`))
+	})
+	defer done()
+	client.SetHeader("X-Team", "ops")
+
+	sub := client.ForSubaccount(42)
+	sub.SetHeader("X-Team", "eu")
+	if _, _, err := sub.Templates(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Templates(); err != nil {
+		t.Fatal(err)
+	}
+
+	url := client.Config.BaseUrl + "/api/v1/templates"
+	ctx := sp.WithSubaccount(context.Background(), 7)
+	if _, err := sub.DoRequestContext(ctx, "GET", url, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DoRequestContext(ctx, "GET", url, nil, map[string]string{sp.SubaccountHeader: "9"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"42 eu", " ops", "7 eu", "9 ops"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected headers %q, got %q", want, got)
+	}
+}
+
+func TestSubaccountUpdate(t *testing.T) {
+	var paths []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if len(paths) > 1 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"errors":[{"message":"in use"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":{}}`))
+	})
+	defer done()
+
+	// updates go to the subaccount's own path, not the templates endpoint
+	s := &sp.Subaccount{ID: 12, Name: "renamed"}
+	if _, err := client.SubaccountUpdate(s); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.SubaccountUpdate(s)
+	if err == nil || err.Error() != "Subaccount with id [12] is in use by msg generation" {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, p := range paths {
+		if p != "PUT /api/v1/subaccounts/12" {
+			t.Errorf("unexpected request %s", p)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SubstitutionData converts v, a struct or pointer to a struct, into substitution data.
+// Fields are named by their `sp` tag, for example `sp:"first_name"`, and may add ",omitempty".
+// Fields without an sp tag, or tagged `sp:"-"`, are left out. Nested structs become objects,
+// and slices and arrays become arrays.
+//
+// If vars is non-nil, typically the result of TemplateVars, the data is checked against it:
+// fields the template doesn't use, required variables which are missing and values of the
+// wrong type are all reported, so a misspelt tag fails in a test instead of rendering blank.
+func SubstitutionData(v interface{}, vars []*TemplateVar) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("SubstitutionData expects a struct, got %T", v)
+	}
+	data := structData(rv)
+	if vars == nil {
+		return data, nil
+	}
+
+	var problems []string
+	checkObject(&problems, "", data, vars)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("Substitution data doesn't match template: %s", strings.Join(problems, "; "))
+	}
+	return data, nil
+}
+
+// TemplateSubstitutionData is like SubstitutionData, checking v against the variables
+// used by the stored Template with the provided ID.
+func (c *Client) TemplateSubstitutionData(id string, v interface{}) (map[string]interface{}, error) {
+	t, _, err := c.Template(id)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := TemplateVars(t.Content)
+	if err != nil {
+		return nil, err
+	}
+	return SubstitutionData(v, vars)
+}
+
+func structData(rv reflect.Value) map[string]interface{} {
+	data := map[string]interface{}{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := f.Tag.Lookup("sp")
+		if !ok || tag == "-" || f.PkgPath != "" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := rv.Field(i)
+		if opts == "omitempty" && isEmptyValue(fv) {
+			continue
+		}
+		if val, ok := dataValue(fv); ok {
+			data[name] = val
+		}
+	}
+	return data
+}
+
+// dataValue converts a field value, returning false for nil pointers and interfaces.
+func dataValue(v reflect.Value) (interface{}, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if hasSpTags(v.Type()) {
+			return structData(v), true
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a string
+			break
+		}
+		items := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, _ := dataValue(v.Index(i))
+			items = append(items, item)
+		}
+		return items, true
+	}
+	return v.Interface(), true
+}
+
+// hasSpTags reports whether a struct type is meant to be converted field by field,
+// rather than passed through for encoding/json (time.Time, for example).
+func hasSpTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("sp"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func checkObject(problems *[]string, prefix string, data map[string]interface{}, vars []*TemplateVar) {
+	known := make(map[string]*TemplateVar, len(vars))
+	for _, v := range vars {
+		known[v.Name] = v
+		if _, ok := data[v.Name]; !ok && v.Required {
+			*problems = append(*problems, fmt.Sprintf("missing [%s%s]", prefix, v.Name))
+		}
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := known[name]
+		if v == nil {
+			*problems = append(*problems, fmt.Sprintf("[%s%s] isn't used by the template", prefix, name))
+			continue
+		}
+		checkValue(problems, prefix+name, data[name], v)
+	}
+}
+
+func checkValue(problems *[]string, path string, value interface{}, v *TemplateVar) {
+	wrongType := func() {
+		*problems = append(*problems, fmt.Sprintf("[%s] should be %s %s, not %T", path, article(v.Type), v.Type, value))
+	}
+	switch v.Type {
+	case VarObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			wrongType()
+			return
+		}
+		checkObject(problems, path+".", obj, v.Fields)
+	case VarArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			wrongType()
+			return
+		}
+		for i, item := range items {
+			checkValue(problems, fmt.Sprintf("%s[%d]", path, i), item, v.Elem)
+		}
+	case VarNumber:
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			wrongType()
+		}
+	case VarString:
+		// anything printable; objects and arrays aren't
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			wrongType()
+		}
+	}
+	// any value can be tested for truth, so VarBool accepts anything
+}
+
+func article(word string) string {
+	if strings.IndexAny(word[:1], "aeiou") >= 0 {
+		return "an"
+	}
+	return "a"
+}
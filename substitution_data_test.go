@@ -0,0 +1,97 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+type testOrderItem struct {
+	Name  string  `sp:"name"`
+	Price float64 `sp:"price"`
+}
+
+type testOrderData struct {
+	User struct {
+		FirstName string `sp:"first_name"`
+	} `sp:"user"`
+	Coupon   string          `sp:"coupon,omitempty"`
+	Items    []testOrderItem `sp:"items"`
+	Internal string
+}
+
+var testOrderContent = sp.Content{
+	Subject: "Hi {{ user.first_name }}",
+	HTML:    `{{ if coupon }}Use {{ coupon }}{{ end }}{{ each items }}{{ loop_var.name }}: {{ if loop_var.price > 10 }}big{{ end }}{{ end }}`,
+}
+
+func TestSubstitutionData(t *testing.T) {
+	vars, err := sp.TemplateVars(testOrderContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var order testOrderData
+	order.User.FirstName = "Ann"
+	order.Items = []testOrderItem{{"Hat", 12.5}}
+	order.Internal = "not sent"
+
+	data, err := sp.SubstitutionData(&order, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"user":  map[string]interface{}{"first_name": "Ann"},
+		"items": []interface{}{map[string]interface{}{"name": "Hat", "price": 12.5}},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("got %#v", data)
+	}
+
+	type typo struct {
+		User struct {
+			FirstName string `sp:"firstname"`
+		} `sp:"user"`
+		Items []struct {
+			Price string `sp:"price"`
+		} `sp:"items"`
+	}
+	var bad typo
+	bad.Items = append(bad.Items, struct {
+		Price string `sp:"price"`
+	}{"12"})
+	_, err = sp.SubstitutionData(bad, vars)
+	if err == nil {
+		t.Fatal("expected mismatches to be reported")
+	}
+	for _, problem := range []string{
+		"missing [user.first_name]",
+		"[user.firstname] isn't used by the template",
+		"[items[0].price] should be a number, not string",
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected %q in %q", problem, err)
+		}
+	}
+
+	if _, err = sp.SubstitutionData("hello", nil); err == nil {
+		t.Error("expected an error for a non-struct")
+	}
+}
+
+func TestTemplateSubstitutionData(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"orders","content":{"subject":"Hi {{ user.first_name }}"}}}`))
+	})
+	defer done()
+
+	var order testOrderData
+	if _, err := client.TemplateSubstitutionData("orders", order); err == nil ||
+		!strings.Contains(err.Error(), "[items] isn't used") {
+		t.Errorf("expected items to be reported, got %v", err)
+	}
+}
@@ -1,9 +1,16 @@
 package gosparkpost
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	URL "net/url"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // https://developers.sparkpost.com/api/#/reference/suppression-list
@@ -16,6 +23,10 @@ type SuppressionEntry struct {
 	// Recipient is used when a list is returned
 	Recipient string `json:"recipient,omitempty"`
 
+	// Type is SuppressionTransactional or SuppressionNonTransactional, for entries returned
+	// by the API, which lists each type separately.
+	Type string `json:"type,omitempty"`
+
 	Transactional    bool   `json:"transactional,omitempty"`
 	NonTransactional bool   `json:"non_transactional,omitempty"`
 	Source           string `json:"source,omitempty"`
@@ -24,11 +35,56 @@ type SuppressionEntry struct {
 	Created          string `json:"created,omitempty"`
 }
 
+// address returns whichever of Email and Recipient is set.
+func (s *SuppressionEntry) address() string {
+	if s.Email != "" {
+		return s.Email
+	}
+	return s.Recipient
+}
+
+// Types returns which types of suppression the entry is for, from Type if it's set.
+func (s *SuppressionEntry) Types() []string {
+	if s.Type != "" {
+		return []string{s.Type}
+	}
+	var types []string
+	if s.Transactional {
+		types = append(types, SuppressionTransactional)
+	}
+	if s.NonTransactional {
+		types = append(types, SuppressionNonTransactional)
+	}
+	return types
+}
+
+func (s *SuppressionEntry) String() string {
+	if s == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s [%s] %s: %s", s.address(), strings.Join(s.Types(), ","), s.Source, s.Description)
+}
+
+// Equal reports whether two entries suppress the same address in the same way.
+// Addresses are compared case-insensitively, whether they're in Email or Recipient,
+// and the Created and Updated times are ignored.
+func (s *SuppressionEntry) Equal(o *SuppressionEntry) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return strings.EqualFold(s.address(), o.address()) &&
+		strings.Join(s.Types(), ",") == strings.Join(o.Types(), ",") &&
+		s.Source == o.Source &&
+		s.Description == o.Description
+}
+
 type SuppressionListWrapper struct {
 	Results    []*SuppressionEntry `json:"results,omitempty"`
 	Recipients []SuppressionEntry  `json:"recipients,omitempty"`
 }
 
+// SuppressionList returns the first page of the suppression list.
+// Use SuppressionPages or SuppressionEach to read all of it.
 func (c *Client) SuppressionList() (*SuppressionListWrapper, error) {
 	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
 	finalUrl := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
@@ -36,6 +92,154 @@ func (c *Client) SuppressionList() (*SuppressionListWrapper, error) {
 	return doSuppressionRequest(c, finalUrl)
 }
 
+// SuppressionSummary returns the number of suppression list entries per source, plus a "total".
+func (c *Client) SuppressionSummary() (map[string]int, error) {
+	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
+	finalUrl := fmt.Sprintf("%s%s/summary", c.Config.BaseUrl, path)
+
+	bodyBytes, err := doRequest(c, finalUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	var resMap struct {
+		Results map[string]int `json:"results"`
+	}
+	err = json.Unmarshal(bodyBytes, &resMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return resMap.Results, nil
+}
+
+// SuppressionPage is a page of results from SuppressionPages.
+type SuppressionPage struct {
+	client *Client
+
+	Results    []*SuppressionEntry
+	TotalCount int
+	nextPage   string
+}
+
+func (page *SuppressionPage) UnmarshalJSON(data []byte) error {
+	var wrapper struct {
+		Results    []*SuppressionEntry `json:"results"`
+		TotalCount int                 `json:"total_count"`
+		Links      struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	*page = SuppressionPage{Results: wrapper.Results, TotalCount: wrapper.TotalCount, nextPage: wrapper.Links.Next}
+	return nil
+}
+
+// SuppressionPages returns the first page of the suppression list entries matching params,
+// which may be nil, using cursor pagination. Use Next to page through the rest.
+func (c *Client) SuppressionPages(params *SuppressionSearchParams) (*SuppressionPage, error) {
+	p := SuppressionSearchParams{}
+	if params != nil {
+		p = *params
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if p.Cursor == "" {
+		p.Cursor = "initial"
+	}
+	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
+	return c.suppressionPage(p.query().URL(c.Config.BaseUrl + path))
+}
+
+// Next returns the following page of results, or ErrEmptyPage if this is the last page.
+func (page *SuppressionPage) Next() (*SuppressionPage, error) {
+	if page.nextPage == "" || len(page.Results) == 0 {
+		return nil, ErrEmptyPage
+	}
+	return page.client.suppressionPage(page.client.Config.BaseUrl + page.nextPage)
+}
+
+// SuppressionEach passes each suppression list entry matching params to fn, fetching
+// pages as they're needed. It stops at the first error returned by fn, and returns it.
+func (c *Client) SuppressionEach(params *SuppressionSearchParams, fn func(*SuppressionEntry) error) error {
+	page, err := c.SuppressionPages(params)
+	for err == nil {
+		for _, entry := range page.Results {
+			if err = fn(entry); err != nil {
+				return err
+			}
+		}
+		page, err = page.Next()
+	}
+	if err == ErrEmptyPage {
+		return nil
+	}
+	return err
+}
+
+// suppressionCSVColumns are the header row written by SuppressionExportCSV.
+var suppressionCSVColumns = []string{"recipient", "type", "source", "description", "created", "updated"}
+
+// SuppressionExportCSV writes every entry matching params to w as CSV, a page at a time,
+// so a backup of the whole list never has to be held in memory. Each row is for one
+// suppression type, so an entry suppressed for both is written twice.
+func (c *Client) SuppressionExportCSV(w io.Writer, params *SuppressionSearchParams) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(suppressionCSVColumns); err != nil {
+		return err
+	}
+	err := c.SuppressionEach(params, func(e *SuppressionEntry) error {
+		for _, t := range e.Types() {
+			if err := cw.Write([]string{e.address(), t, e.Source, e.Description, e.Created, e.Updated}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (c *Client) suppressionPage(url string) (*SuppressionPage, error) {
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, err
+	}
+
+	if res.HTTP.StatusCode != 200 {
+		if err = res.ParseResponse(); err != nil {
+			return nil, err
+		}
+		if err = res.PrettyError("Suppression list", "search"); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	bodyBytes, err := res.ReadBody()
+	if err != nil {
+		return nil, err
+	}
+
+	var page SuppressionPage
+	if err = json.Unmarshal(bodyBytes, &page); err != nil {
+		return nil, err
+	}
+	page.client = c
+
+	return &page, nil
+}
+
 func (c *Client) SuppressionRetrieve(recipientEmail string) (*SuppressionListWrapper, error) {
 	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
 	finalUrl := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, recipientEmail)
@@ -43,50 +247,207 @@ func (c *Client) SuppressionRetrieve(recipientEmail string) (*SuppressionListWra
 	return doSuppressionRequest(c, finalUrl)
 }
 
-func (c *Client) SuppressionSearch(parameters map[string]string) (*SuppressionListWrapper, error) {
-	var finalUrl string
-	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
+// Suppression types, for SuppressionSearchParams.Types.
+const (
+	SuppressionTransactional    = "transactional"
+	SuppressionNonTransactional = "non_transactional"
+)
 
-	if parameters == nil || len(parameters) == 0 {
-		finalUrl = fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
-	} else {
-		params := URL.Values{}
-		for k, v := range parameters {
-			params.Add(k, v)
+// MaxSuppressionsPerPage is the largest page the suppression list search will return.
+const MaxSuppressionsPerPage = 10000
+
+// SuppressionSearchParams filters a search of the suppression list. Zero values don't filter.
+type SuppressionSearchParams struct {
+	// From and To limit when entries were last updated.
+	From time.Time
+	To   time.Time
+	// Types are SuppressionTransactional and/or SuppressionNonTransactional.
+	Types []string
+	// Sources are how entries were added, such as "Spam Complaint" or "Manually Added".
+	Sources []string
+	// Domain matches the domain of recipients' addresses.
+	Domain string
+	// Limit is the number of entries per page, at most MaxSuppressionsPerPage.
+	Limit int
+	// Cursor resumes a paginated search; see SuppressionPages.
+	Cursor string
+}
+
+// Validate checks the params make sense, before a request is made.
+func (p *SuppressionSearchParams) Validate() error {
+	if !p.From.IsZero() && !p.To.IsZero() && p.To.Before(p.From) {
+		return fmt.Errorf("Suppression search To [%s] is before From [%s]", p.To, p.From)
+	}
+	for _, t := range p.Types {
+		if t != SuppressionTransactional && t != SuppressionNonTransactional {
+			return fmt.Errorf("Unknown suppression type [%s]", t)
 		}
+	}
+	if p.Limit < 0 || p.Limit > MaxSuppressionsPerPage {
+		return fmt.Errorf("Suppression search Limit must be between 0 and %d", MaxSuppressionsPerPage)
+	}
+	return nil
+}
 
-		finalUrl = fmt.Sprintf("%s%s?%s", c.Config.BaseUrl, path, params.Encode())
+func (p *SuppressionSearchParams) query() QueryBuilder {
+	return QueryBuilder{}.
+		Time("from", p.From, time.RFC3339).
+		Time("to", p.To, time.RFC3339).
+		List("types", p.Types...).
+		List("sources", p.Sources...).
+		Set("domain", p.Domain).
+		Int("per_page", p.Limit).
+		Set("cursor", p.Cursor)
+}
+
+// SuppressionSearch returns the first page of suppression list entries matching params,
+// which may be nil. Use SuppressionPages or SuppressionEach to read them all.
+func (c *Client) SuppressionSearch(params *SuppressionSearchParams) (*SuppressionListWrapper, error) {
+	if params == nil {
+		params = &SuppressionSearchParams{}
 	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
+	finalUrl := params.query().URL(c.Config.BaseUrl + path)
 
 	return doSuppressionRequest(c, finalUrl)
 }
 
 func (c *Client) SuppressionDelete(recipientEmail string) (res *Response, err error) {
+	return c.SuppressionDeleteContext(context.Background(), recipientEmail)
+}
+
+// SuppressionDeleteContext is like SuppressionDelete, and records the change with Client.Audit,
+// attributing it using any values set on ctx with WithAuditActor and WithAuditReason.
+func (c *Client) SuppressionDeleteContext(ctx context.Context, recipientEmail string) (res *Response, err error) {
+	res, err = c.suppressionDelete(ctx, recipientEmail, "")
+	err = c.audit(ctx, AuditSuppressionDelete, []SuppressionEntry{{Email: recipientEmail}}, res, err)
+	return
+}
+
+// SuppressionDeleteConcurrency is how many deletes SuppressionDeleteBulk has in flight at once.
+var SuppressionDeleteConcurrency = 8
+
+// SuppressionDeleteError maps each address SuppressionDeleteBulk couldn't remove to the reason why.
+type SuppressionDeleteError map[string]error
+
+func (e SuppressionDeleteError) Error() string {
+	emails := make([]string, 0, len(e))
+	for email := range e {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	if len(emails) == 1 {
+		return fmt.Sprintf("Failed to delete SuppressionEntry [%s]: %s", emails[0], e[emails[0]])
+	}
+	return fmt.Sprintf("Failed to delete %d SuppressionEntries, including [%s]: %s",
+		len(emails), emails[0], e[emails[0]])
+}
+
+// SuppressionDeleteBulk removes many addresses from the suppression list, with up to
+// SuppressionDeleteConcurrency requests at once. An empty suppressionType removes both
+// transactional and non-transactional entries. Addresses which couldn't be removed are
+// reported in a SuppressionDeleteError.
+func (c *Client) SuppressionDeleteBulk(emails []string, suppressionType string) error {
+	return c.SuppressionDeleteBulkContext(context.Background(), emails, suppressionType)
+}
+
+// SuppressionDeleteBulkContext is like SuppressionDeleteBulk, and records each change with
+// Client.Audit. Addresses not yet attempted when ctx is done fail with ctx.Err().
+func (c *Client) SuppressionDeleteBulkContext(ctx context.Context, emails []string, suppressionType string) error {
+	switch suppressionType {
+	case "", SuppressionTransactional, SuppressionNonTransactional:
+	default:
+		return fmt.Errorf("Unknown suppression type %q", suppressionType)
+	}
+	workers := SuppressionDeleteConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	var mu sync.Mutex
+	failed := SuppressionDeleteError{}
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for email := range queue {
+				err := ctx.Err()
+				if err == nil {
+					var res *Response
+					res, err = c.suppressionDelete(ctx, email, suppressionType)
+					err = c.audit(ctx, AuditSuppressionDelete, []SuppressionEntry{{Email: email}}, res, err)
+				}
+				if err != nil {
+					mu.Lock()
+					failed[email] = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, email := range emails {
+		queue <- email
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
+
+func (c *Client) suppressionDelete(ctx context.Context, recipientEmail, suppressionType string) (res *Response, err error) {
 	path := fmt.Sprintf(suppressionListsPathFormat, c.Config.ApiVersion)
-	finalUrl := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, recipientEmail)
+	finalUrl := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, url.PathEscape(recipientEmail))
+
+	var body []byte
+	if suppressionType != "" {
+		if body, err = json.Marshal(map[string]string{"type": suppressionType}); err != nil {
+			return nil, err
+		}
+	}
 
-	res, err = c.HttpDelete(finalUrl)
+	res, err = c.DoRequestContext(ctx, "DELETE", finalUrl, body, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if res.HTTP.StatusCode >= 200 && res.HTTP.StatusCode <= 299 {
+		// read and close the body, so the connection can be reused
+		_, err = res.ReadBody()
 		return
+	}
 
-	} else if len(res.Errors) > 0 {
-		// handle common errors
-		err = res.PrettyError("SuppressionEntry", "delete")
-		if err != nil {
-			return nil, err
-		}
+	if err = res.AssertJson(); err != nil {
+		return
+	}
 
-		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	if err = res.ParseResponse(); err != nil {
+		return
+	}
+
+	// handle common errors
+	if err = res.PrettyError("SuppressionEntry", "delete"); err != nil {
+		return
 	}
 
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 	return
 }
 
 func (c *Client) SuppressionInsertOrUpdate(entries []SuppressionEntry) (err error) {
+	return c.SuppressionInsertOrUpdateContext(context.Background(), entries)
+}
+
+// SuppressionInsertOrUpdateContext is like SuppressionInsertOrUpdate, and records the change with
+// Client.Audit, attributing it using any values set on ctx with WithAuditActor and WithAuditReason.
+func (c *Client) SuppressionInsertOrUpdateContext(ctx context.Context, entries []SuppressionEntry) (err error) {
 	if entries == nil {
 		err = fmt.Errorf("send `entries` cannot be nil here")
 		return
@@ -97,17 +458,17 @@ func (c *Client) SuppressionInsertOrUpdate(entries []SuppressionEntry) (err erro
 
 	list := SuppressionListWrapper{nil, entries}
 
-	return c.send(finalUrl, list)
-
+	res, err := c.send(ctx, finalUrl, list)
+	return c.audit(ctx, AuditSuppressionUpsert, entries, res, err)
 }
 
-func (c *Client) send(finalUrl string, recipients SuppressionListWrapper) (err error) {
+func (c *Client) send(ctx context.Context, finalUrl string, recipients SuppressionListWrapper) (res *Response, err error) {
 	jsonBytes, err := json.Marshal(recipients)
 	if err != nil {
 		return
 	}
 
-	res, err := c.HttpPut(finalUrl, jsonBytes)
+	res, err = c.DoRequestContext(ctx, "PUT", finalUrl, jsonBytes, nil)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,219 @@
+package gosparkpost_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSuppressionPages(t *testing.T) {
+	var queries []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/suppression-list" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cursor") {
+		case "initial":
+			fmt.Fprint(w, `{"results":[{"recipient":"a@example.com","type":"non_transactional"},
+				{"recipient":"b@example.com","type":"transactional"}],"total_count":3,
+				"links":{"next":"/api/v1/suppression-list?cursor=abc&per_page=2"}}`)
+		case "abc":
+			fmt.Fprint(w, `{"results":[{"recipient":"c@example.com","type":"transactional"}],"total_count":3,
+				"links":{"next":"/api/v1/suppression-list?cursor=def&per_page=2"}}`)
+		default:
+			fmt.Fprint(w, `{"results":[],"total_count":3,"links":{}}`)
+		}
+	})
+	defer done()
+
+	var got []string
+	err := client.SuppressionEach(&sp.SuppressionSearchParams{Limit: 2}, func(e *sp.SuppressionEntry) error {
+		got = append(got, e.Recipient)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[a@example.com b@example.com c@example.com]" {
+		t.Errorf("unexpected entries %v", got)
+	}
+	if len(queries) != 3 || queries[0] != "cursor=initial&per_page=2" || queries[1] != "cursor=abc&per_page=2" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+
+	stop := errors.New("stop")
+	queries = nil
+	err = client.SuppressionEach(nil, func(e *sp.SuppressionEntry) error { return stop })
+	if err != stop || len(queries) != 1 {
+		t.Errorf("expected SuppressionEach to stop at the first error, got %v after %d pages", err, len(queries))
+	}
+}
+
+func TestSuppressionSearchParams(t *testing.T) {
+	var query string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results":[{"recipient":"a@example.com","type":"transactional"}]}`)
+	})
+	defer done()
+
+	from := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600))
+	list, err := client.SuppressionSearch(&sp.SuppressionSearchParams{
+		From:    from,
+		Types:   []string{sp.SuppressionTransactional},
+		Sources: []string{"Spam Complaint", "Bounce Rule"},
+		Domain:  "example.com",
+		Limit:   50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Results) != 1 {
+		t.Errorf("unexpected results %v", list.Results)
+	}
+	expected := "domain=example.com&from=2026-01-02T08%3A04%3A05Z&per_page=50&sources=Spam+Complaint%2CBounce+Rule&types=transactional"
+	if query != expected {
+		t.Errorf("got query %s, want %s", query, expected)
+	}
+
+	for idx, bad := range []sp.SuppressionSearchParams{
+		{From: from, To: from.Add(-time.Hour)},
+		{Types: []string{"marketing"}},
+		{Limit: sp.MaxSuppressionsPerPage + 1},
+	} {
+		query = ""
+		if _, err = client.SuppressionSearch(&bad); err == nil || query != "" {
+			t.Errorf("SuppressionSearch[%d] => expected a validation error without a request, got %v", idx, err)
+		}
+	}
+}
+
+func TestSuppressionDeleteReusesConnection(t *testing.T) {
+	conns := map[string]bool{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		conns[r.RemoteAddr] = true
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results":{"message":"deleted"}}`)
+	})
+	defer done()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.SuppressionDelete(fmt.Sprintf("user%d@example.com", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the body of each response is read and closed, so its connection is reused
+	if len(conns) != 1 {
+		t.Errorf("expected one connection, got %d", len(conns))
+	}
+}
+
+func TestSuppressionDeleteBulk(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	deleted := map[string]string{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		email := strings.TrimPrefix(r.URL.Path, "/api/v1/suppression-list/")
+		var body struct {
+			Type string `json:"type"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(email, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"message":"Recipient could not be found"}]}`)
+			return
+		}
+		mu.Lock()
+		deleted[email] = body.Type
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer done()
+
+	old := sp.SuppressionDeleteConcurrency
+	sp.SuppressionDeleteConcurrency = 3
+	defer func() { sp.SuppressionDeleteConcurrency = old }()
+
+	var emails []string
+	for i := 0; i < 10; i++ {
+		emails = append(emails, fmt.Sprintf("user%d@example.com", i))
+	}
+	emails = append(emails, "missing1@example.com", "missing2@example.com")
+
+	err := client.SuppressionDeleteBulk(emails, sp.SuppressionNonTransactional)
+	failed, ok := err.(sp.SuppressionDeleteError)
+	if !ok || len(failed) != 2 || failed["missing1@example.com"] == nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if len(deleted) != 10 || deleted["user0@example.com"] != sp.SuppressionNonTransactional {
+		t.Errorf("unexpected deletes %v", deleted)
+	}
+	if maxInFlight > 3 {
+		t.Errorf("expected at most 3 deletes at once, got %d", maxInFlight)
+	}
+
+	if err = client.SuppressionDeleteBulk(emails[:2], "bogus"); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
+
+func TestSuppressionExportCSV(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "initial" {
+			if r.URL.Query().Get("types") != "transactional" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"results":[
+				{"recipient":"a@example.com","type":"transactional","source":"Manually Added","description":"asked, politely","created":"2026-01-02T03:04:05Z"},
+				{"recipient":"b@example.com","transactional":true,"non_transactional":true,"source":"Spam Complaint"}],
+				"links":{"next":"/api/v1/suppression-list?cursor=abc"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"results":[],"links":{}}`)
+	})
+	defer done()
+
+	var buf bytes.Buffer
+	err := client.SuppressionExportCSV(&buf, &sp.SuppressionSearchParams{Types: []string{sp.SuppressionTransactional}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "recipient,type,source,description,created,updated\n" +
+		"a@example.com,transactional,Manually Added,\"asked, politely\",2026-01-02T03:04:05Z,\n" +
+		"b@example.com,transactional,Spam Complaint,,,\n" +
+		"b@example.com,non_transactional,Spam Complaint,,,\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
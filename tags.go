@@ -0,0 +1,160 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// NormalizeTag trims and lower-cases a recipient tag, so "Segment-A " and "segment-a"
+// are counted together.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// AddTags adds each of the provided tags, normalized, that the Recipient doesn't already have.
+func (r *Recipient) AddTags(tags ...string) {
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		found := false
+		for _, t := range r.Tags {
+			found = found || NormalizeTag(t) == tag
+		}
+		if !found {
+			r.Tags = append(r.Tags, tag)
+		}
+	}
+}
+
+// TagRecipients returns a copy of the provided inline Recipients with the tags added to each.
+// Stored recipient lists can't be tagged, and return an error.
+func TagRecipients(recips interface{}, tags ...string) ([]Recipient, error) {
+	list, err := inlineRecipients(recips)
+	if err != nil {
+		return nil, err
+	} else if list == nil {
+		return nil, fmt.Errorf("Can't tag [%s] Recipients, only inline Recipients", reflect.TypeOf(recips))
+	}
+	for i := range list {
+		// don't share the caller's slice
+		list[i].Tags = append([]string(nil), list[i].Tags...)
+		list[i].AddTags(tags...)
+	}
+	return list, nil
+}
+
+// TagMetrics are the deliverability counts of the messages to Recipients with one tag.
+// Opens and clicks are counted once per message.
+type TagMetrics struct {
+	Injected       int
+	Delivered      int
+	Bounced        int
+	SpamComplaints int
+	UniqueOpens    int
+	UniqueClicks   int
+	Unsubscribes   int
+}
+
+// DeliveryRate is the fraction of injected messages which were delivered.
+func (m *TagMetrics) DeliveryRate() float64 {
+	return rate(m.Delivered, m.Injected)
+}
+
+// OpenRate is the fraction of delivered messages which were opened.
+func (m *TagMetrics) OpenRate() float64 {
+	return rate(m.UniqueOpens, m.Delivered)
+}
+
+// ClickRate is the fraction of delivered messages which were clicked.
+func (m *TagMetrics) ClickRate() float64 {
+	return rate(m.UniqueClicks, m.Delivered)
+}
+
+func rate(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) / float64(of)
+}
+
+// tagMetricsEvents are the event types MetricsByTag counts.
+var tagMetricsEvents = []string{
+	"injection", "delivery", "bounce", "out_of_band", "spam_complaint",
+	"open", "initial_open", "amp_open", "amp_initial_open", "click", "amp_click",
+	"list_unsubscribe", "link_unsubscribe",
+}
+
+// MetricsByTag rolls up the events matching params (for example, one campaign) by recipient
+// tag, which the Metrics API can't group by. Messages to Recipients with several tags count
+// towards each; those without tags are counted under "". The event types in params are
+// ignored.
+func (c *Client) MetricsByTag(params *EventsParams) (map[string]*TagMetrics, error) {
+	p := EventsParams{}
+	if params != nil {
+		p = *params
+	}
+	p.Events = tagMetricsEvents
+
+	byTag := map[string]*TagMetrics{}
+	opened := map[string]bool{}
+	clicked := map[string]bool{}
+	page, err := c.SearchEvents(&p)
+	for err == nil {
+		for _, e := range page.Events {
+			r, ferr := events.Flatten(e)
+			if ferr != nil {
+				// an event type this library doesn't know yet
+				continue
+			}
+			tags := r.RecipientTags
+			if len(tags) == 0 {
+				tags = []string{""}
+			}
+			seen := map[string]bool{}
+			for _, tag := range tags {
+				tag = NormalizeTag(tag)
+				if seen[tag] {
+					continue
+				}
+				seen[tag] = true
+				m := byTag[tag]
+				if m == nil {
+					m = &TagMetrics{}
+					byTag[tag] = m
+				}
+				switch r.Type {
+				case "injection":
+					m.Injected++
+				case "delivery":
+					m.Delivered++
+				case "bounce", "out_of_band":
+					m.Bounced++
+				case "spam_complaint":
+					m.SpamComplaints++
+				case "open", "initial_open", "amp_open", "amp_initial_open":
+					if key := tag + "\x00" + r.MessageID; !opened[key] {
+						opened[key] = true
+						m.UniqueOpens++
+					}
+				case "click", "amp_click":
+					if key := tag + "\x00" + r.MessageID; !clicked[key] {
+						clicked[key] = true
+						m.UniqueClicks++
+					}
+				case "list_unsubscribe", "link_unsubscribe":
+					m.Unsubscribes++
+				}
+			}
+		}
+		page, err = page.Next()
+	}
+	if err != ErrEmptyPage {
+		return nil, err
+	}
+	return byTag, nil
+}
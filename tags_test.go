@@ -0,0 +1,73 @@
+package gosparkpost_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTagRecipients(t *testing.T) {
+	recips := []sp.Recipient{
+		{Address: "a@example.com", Tags: []string{"VIP"}},
+		{Address: "b@example.com"},
+	}
+	tagged, err := sp.TagRecipients(recips, " Segment-A", "vip", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tagged[0].Tags, []string{"VIP", "segment-a"}) || !reflect.DeepEqual(tagged[1].Tags, []string{"segment-a", "vip"}) {
+		t.Errorf("unexpected tags %q, %q", tagged[0].Tags, tagged[1].Tags)
+	}
+	if len(recips[0].Tags) != 1 {
+		t.Error("TagRecipients modified the caller's Recipients")
+	}
+	if _, err = sp.TagRecipients(map[string]string{"list_id": "all"}, "x"); err == nil {
+		t.Error("expected an error tagging a stored list")
+	}
+}
+
+func TestMetricsByTag(t *testing.T) {
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("campaigns") != "spring" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results":[
+			{"type":"injection","message_id":"m1","rcpt_tags":["a"]},
+			{"type":"injection","message_id":"m2","rcpt_tags":["b"]},
+			{"type":"injection","message_id":"m3","rcpt_tags":["A","b"]},
+			{"type":"delivery","message_id":"m1","rcpt_tags":["a"]},
+			{"type":"delivery","message_id":"m3","rcpt_tags":["A","b"]},
+			{"type":"bounce","message_id":"m2","rcpt_tags":["b"]},
+			{"type":"open","message_id":"m1","rcpt_tags":["a"]},
+			{"type":"initial_open","message_id":"m1","rcpt_tags":["a"]},
+			{"type":"click","message_id":"m3","rcpt_tags":["A","b"]},
+			{"type":"delivery","message_id":"m4"}
+		],"links":{}}`)
+	})
+	defer done()
+
+	byTag, err := client.MetricsByTag(&sp.EventsParams{Campaigns: []string{"spring"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]sp.TagMetrics{
+		"a": {Injected: 2, Delivered: 2, UniqueOpens: 1, UniqueClicks: 1},
+		"b": {Injected: 2, Delivered: 1, Bounced: 1, UniqueClicks: 1},
+		"":  {Delivered: 1},
+	}
+	if len(byTag) != len(want) {
+		t.Fatalf("unexpected tags %v", byTag)
+	}
+	for tag, m := range want {
+		if got := byTag[tag]; got == nil || *got != m {
+			t.Errorf("tag %q: got %+v, want %+v", tag, got, m)
+		}
+	}
+	if r := byTag["b"].DeliveryRate(); r != 0.5 {
+		t.Errorf("unexpected delivery rate %v", r)
+	}
+}
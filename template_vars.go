@@ -0,0 +1,417 @@
+package gosparkpost
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Types of substitution variable found by TemplateVars.
+const (
+	// VarString is printed in the template.
+	VarString = "string"
+	// VarNumber is compared with a number.
+	VarNumber = "number"
+	// VarBool is only ever tested, for example {{if has_coupon}}.
+	VarBool = "bool"
+	// VarObject has fields, for example {{user.name}}.
+	VarObject = "object"
+	// VarArray is looped over with {{each}}.
+	VarArray = "array"
+)
+
+// TemplateVar describes one substitution variable used by a template.
+type TemplateVar struct {
+	Name string
+	Type string
+	// Required is set for variables used outside {{if}} and {{unless}} blocks,
+	// other than as a condition.
+	Required bool
+	// Fields holds the fields of an object.
+	Fields []*TemplateVar
+	// Elem describes the elements of an array.
+	Elem *TemplateVar
+}
+
+// https://developers.sparkpost.com/api/template-language/
+var (
+	substitutionTag  = regexp.MustCompile(`\{\{\{?\s*(.*?)\s*\}?\}\}`)
+	substitutionExpr = regexp.MustCompile(`'[^']*'|"[^"]*"|-?\d+(\.\d+)?|==|!=|<=|>=|<|>|[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*|\[\d+\])*\s*\(?`)
+	substitutionPart = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*|\[\d+\]`)
+)
+
+var substitutionKeywords = map[string]bool{
+	"if": true, "elseif": true, "unless": true, "else": true, "end": true, "each": true,
+	"and": true, "or": true, "not": true, "true": true, "false": true,
+}
+
+// varNode is a TemplateVar under construction.
+type varNode struct {
+	kind     string
+	required bool
+	fields   map[string]*varNode
+	elem     *varNode
+}
+
+func (n *varNode) field(name string) *varNode {
+	n.setKind(VarObject)
+	if n.fields == nil {
+		n.fields = map[string]*varNode{}
+	}
+	f := n.fields[name]
+	if f == nil {
+		f = &varNode{}
+		n.fields[name] = f
+	}
+	return f
+}
+
+func (n *varNode) element() *varNode {
+	n.setKind(VarArray)
+	if n.elem == nil {
+		n.elem = &varNode{}
+	}
+	return n.elem
+}
+
+// setKind records a use of the variable, keeping the most specific kind seen.
+func (n *varNode) setKind(kind string) {
+	rank := map[string]int{"": 0, VarBool: 1, VarNumber: 2, VarString: 3, VarObject: 4, VarArray: 5}
+	if rank[kind] > rank[n.kind] {
+		n.kind = kind
+	}
+}
+
+type varParser struct {
+	root *varNode
+	// blocks holds the open {{if}}, {{unless}} and {{each}} blocks
+	blocks []string
+	// loops holds the array name and element for each open {{each}}
+	loops []struct {
+		name string
+		elem *varNode
+	}
+	conditional int
+}
+
+// TemplateVars finds the substitution variables used by Content, and infers their types.
+// Variables used in {{each}} loops via loop_var and loop_vars are attributed to the elements
+// of the arrays looped over. Content which only references a stored template can't be examined.
+func TemplateVars(c Content) ([]*TemplateVar, error) {
+	html, text := c.HTML, c.Text
+	var err error
+	if c.HTMLLoader != nil {
+		if html, err = c.HTMLLoader(); err != nil {
+			return nil, err
+		}
+	}
+	if c.TextLoader != nil {
+		if text, err = c.TextLoader(); err != nil {
+			return nil, err
+		}
+	}
+	parts := []string{c.Subject, c.ReplyTo, html, text, c.EmailRFC822}
+	if from, ok := c.From.(string); ok {
+		parts = append(parts, from)
+	} else if from, ok := c.From.(From); ok {
+		parts = append(parts, from.Email, from.Name)
+	}
+	for _, v := range c.Headers {
+		parts = append(parts, v)
+	}
+
+	p := &varParser{root: &varNode{}}
+	for _, part := range parts {
+		if err = p.parse(part); err != nil {
+			return nil, err
+		}
+	}
+	return p.root.vars(), nil
+}
+
+func (p *varParser) parse(s string) error {
+	for _, m := range substitutionTag.FindAllStringSubmatch(s, -1) {
+		tag := m[1]
+		word := tag
+		if i := strings.IndexFunc(tag, unicode.IsSpace); i >= 0 {
+			word = tag[:i]
+		}
+
+		switch word {
+		case "if", "unless":
+			p.blocks = append(p.blocks, word)
+			p.conditional++
+			if err := p.expr(tag[len(word):], true); err != nil {
+				return err
+			}
+		case "elseif":
+			if err := p.expr(tag[len(word):], true); err != nil {
+				return err
+			}
+		case "else":
+		case "each":
+			p.blocks = append(p.blocks, word)
+			path := strings.TrimSpace(tag[len(word):])
+			n, err := p.resolve(path)
+			if err != nil {
+				return err
+			}
+			if p.conditional == 0 {
+				n.required = true
+			}
+			parts := substitutionPart.FindAllString(path, -1)
+			p.loops = append(p.loops, struct {
+				name string
+				elem *varNode
+			}{parts[len(parts)-1], n.element()})
+		case "end":
+			if len(p.blocks) == 0 {
+				return fmt.Errorf("Unexpected {{end}} in template")
+			}
+			switch p.blocks[len(p.blocks)-1] {
+			case "each":
+				p.loops = p.loops[:len(p.loops)-1]
+			default:
+				p.conditional--
+			}
+			p.blocks = p.blocks[:len(p.blocks)-1]
+		default:
+			if err := p.expr(tag, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expr records the variables in an expression. Those in conditions are typed by what
+// they're compared with, if anything; others are printed, so they're strings.
+func (p *varParser) expr(expr string, condition bool) error {
+	tokens := substitutionExpr.FindAllString(expr, -1)
+	for i := range tokens {
+		tokens[i] = strings.TrimSpace(tokens[i])
+	}
+	for i, tok := range tokens {
+		if literalKind(tok) != "" || comparisons[tok] || strings.HasSuffix(tok, "(") || substitutionKeywords[tok] {
+			// literals, operators, keywords and macros like render_snippet
+			continue
+		}
+
+		n, err := p.resolve(tok)
+		if err != nil {
+			return err
+		}
+		if !condition {
+			n.setKind(VarString)
+			if p.conditional == 0 {
+				n.required = true
+			}
+			continue
+		}
+
+		// compared with a literal, for example {{if count > 2}}
+		kind := VarBool
+		if i+2 < len(tokens) && comparisons[tokens[i+1]] && literalKind(tokens[i+2]) != "" {
+			kind = literalKind(tokens[i+2])
+		} else if i >= 2 && comparisons[tokens[i-1]] && literalKind(tokens[i-2]) != "" {
+			kind = literalKind(tokens[i-2])
+		}
+		n.setKind(kind)
+	}
+	return nil
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true}
+
+// literalKind returns VarString or VarNumber for literal tokens, and "" otherwise.
+func literalKind(tok string) string {
+	switch c := tok[0]; {
+	case c == '\'' || c == '"':
+		return VarString
+	case c == '-' || (c >= '0' && c <= '9'):
+		return VarNumber
+	}
+	return ""
+}
+
+// resolve finds (or creates) the node for a path like user.name, items[0] or loop_var.price.
+func (p *varParser) resolve(path string) (*varNode, error) {
+	parts := substitutionPart.FindAllString(path, -1)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("Invalid substitution variable [%s]", path)
+	}
+
+	n := p.root
+	switch parts[0] {
+	case "loop_var":
+		if len(p.loops) == 0 {
+			return nil, fmt.Errorf("loop_var used outside {{each}}")
+		}
+		n, parts = p.loops[len(p.loops)-1].elem, parts[1:]
+	case "loop_vars":
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("loop_vars used without an array name")
+		}
+		found := false
+		for i := len(p.loops) - 1; i >= 0; i-- {
+			if p.loops[i].name == parts[1] {
+				n, found = p.loops[i].elem, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("loop_vars.%s used outside {{each %s}}", parts[1], parts[1])
+		}
+		parts = parts[2:]
+	}
+
+	for _, part := range parts {
+		if part[0] == '[' {
+			n = n.element()
+		} else {
+			n = n.field(part)
+		}
+	}
+	return n, nil
+}
+
+func (n *varNode) vars() []*TemplateVar {
+	names := make([]string, 0, len(n.fields))
+	for name := range n.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vars := make([]*TemplateVar, len(names))
+	for i, name := range names {
+		vars[i] = n.fields[name].templateVar(name)
+	}
+	return vars
+}
+
+func (n *varNode) templateVar(name string) *TemplateVar {
+	v := &TemplateVar{Name: name, Type: n.kind, Required: n.required}
+	if v.Type == "" {
+		v.Type = VarString
+	}
+	switch v.Type {
+	case VarObject:
+		v.Fields = n.vars()
+		for _, f := range v.Fields {
+			v.Required = v.Required || f.Required
+		}
+	case VarArray:
+		v.Elem = n.element().templateVar("")
+	}
+	return v
+}
+
+// TemplateVarsSchema returns a JSON Schema describing substitution data for the provided variables.
+func TemplateVarsSchema(vars []*TemplateVar) ([]byte, error) {
+	schema := objectSchema(vars)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func objectSchema(vars []*TemplateVar) map[string]interface{} {
+	props := map[string]interface{}{}
+	var required []string
+	for _, v := range vars {
+		props[v.Name] = varSchema(v)
+		if v.Required {
+			required = append(required, v.Name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func varSchema(v *TemplateVar) map[string]interface{} {
+	switch v.Type {
+	case VarObject:
+		return objectSchema(v.Fields)
+	case VarArray:
+		return map[string]interface{}{"type": "array", "items": varSchema(v.Elem)}
+	case VarBool:
+		return map[string]interface{}{"type": "boolean"}
+	}
+	return map[string]interface{}{"type": v.Type}
+}
+
+// TemplateVarsStruct returns gofmt-ed Go source for a struct type with the provided name,
+// which marshals to substitution data for the provided variables. Nested objects get their own types.
+func TemplateVarsStruct(name string, vars []*TemplateVar) string {
+	var buf bytes.Buffer
+	writeStruct(&buf, name, vars)
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		// only possible with a name that isn't a valid identifier
+		return buf.String()
+	}
+	return string(src)
+}
+
+func writeStruct(buf *bytes.Buffer, name string, vars []*TemplateVar) {
+	var nested bytes.Buffer
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, v := range vars {
+		field := goName(v.Name)
+		tag := v.Name
+		if !v.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n", field, goType(&nested, name+field, v), tag)
+	}
+	buf.WriteString("}\n")
+	if nested.Len() > 0 {
+		buf.WriteString("\n")
+		buf.Write(nested.Bytes())
+	}
+}
+
+func goType(nested *bytes.Buffer, name string, v *TemplateVar) string {
+	switch v.Type {
+	case VarObject:
+		if nested.Len() > 0 {
+			nested.WriteString("\n")
+		}
+		writeStruct(nested, name, v.Fields)
+		return name
+	case VarArray:
+		return "[]" + goType(nested, name+"Item", v.Elem)
+	case VarBool:
+		return "bool"
+	case VarNumber:
+		return "float64"
+	}
+	return "string"
+}
+
+// goName converts a snake_case variable name to an exported Go identifier.
+func goName(s string) string {
+	var buf bytes.Buffer
+	upper := true
+	for _, r := range s {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+	if buf.Len() == 0 || !unicode.IsLetter([]rune(buf.String())[0]) {
+		return "X" + buf.String()
+	}
+	return buf.String()
+}
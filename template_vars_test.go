@@ -0,0 +1,98 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTemplateVars(t *testing.T) {
+	content := sp.Content{
+		Subject: "Hi {{ user.first_name }}",
+		HTML: `<p>{{{ banner_html }}}</p>
+{{ if has_coupon }}<p>Use {{ coupon }}</p>{{ end }}
+{{ if tier == 'gold' and points > 100 }}VIP{{ elseif tier }}member{{ end }}
+{{ each orders }}
+  <h2>{{ loop_var.id }}</h2>
+  {{ each loop_var.items }}<li>{{ loop_var.name }} for {{ loop_vars.orders.currency }}</li>{{ end }}
+{{ end }}
+{{ render_dynamic_content(dynamic_html.footer) }}
+{{ render_snippet("legal") }}`,
+	}
+
+	vars, err := sp.TemplateVars(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	var walk func(prefix string, vs []*sp.TemplateVar)
+	walk = func(prefix string, vs []*sp.TemplateVar) {
+		for _, v := range vs {
+			desc := prefix + v.Name + ":" + v.Type
+			if v.Required {
+				desc += "!"
+			}
+			got = append(got, desc)
+			walk(prefix+v.Name+".", v.Fields)
+			if v.Elem != nil {
+				walk(prefix+v.Name+"[].", v.Elem.Fields)
+			}
+		}
+	}
+	walk("", vars)
+
+	expected := strings.Join([]string{
+		"banner_html:string!", "coupon:string", "dynamic_html:object!", "dynamic_html.footer:string!",
+		"has_coupon:bool",
+		"orders:array!", "orders[].currency:string!", "orders[].id:string!",
+		"orders[].items:array!", "orders[].items[].name:string!",
+		"points:number", "tier:string",
+		"user:object!", "user.first_name:string!",
+	}, " ")
+	if strings.Join(got, " ") != expected {
+		t.Errorf("got      %s\nexpected %s", strings.Join(got, " "), expected)
+	}
+
+	schemaBytes, err := sp.TemplateVarsSchema(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Required   []string
+		Properties map[string]struct {
+			Type  string
+			Items struct{ Type string }
+		}
+	}
+	if err = json.Unmarshal(schemaBytes, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Properties["orders"].Items.Type != "object" || schema.Properties["has_coupon"].Type != "boolean" ||
+		strings.Join(schema.Required, ",") != "banner_html,dynamic_html,orders,user" {
+		t.Errorf("unexpected schema:\n%s", schemaBytes)
+	}
+
+	src := sp.TemplateVarsStruct("Welcome", vars)
+	// ignore gofmt's alignment
+	flat := regexp.MustCompile(`[ \t]+`).ReplaceAllString(src, " ")
+	for _, line := range []string{
+		"type Welcome struct {",
+		" Orders []WelcomeOrdersItem `json:\"orders\"`",
+		" HasCoupon bool `json:\"has_coupon,omitempty\"`",
+		" Points float64 `json:\"points,omitempty\"`",
+		"type WelcomeOrdersItem struct {",
+		" Items []WelcomeOrdersItemItemsItem `json:\"items\"`",
+		"type WelcomeUser struct {",
+	} {
+		if !strings.Contains(flat, line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, src)
+		}
+	}
+
+	if _, err = sp.TemplateVars(sp.Content{Text: "{{ loop_var.x }}"}); err == nil {
+		t.Error("expected an error for loop_var outside a loop")
+	}
+}
@@ -3,8 +3,11 @@ package gosparkpost
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,6 +25,66 @@ type Template struct {
 	LastUse     time.Time    `json:"last_use,omitempty"`
 	LastUpdate  time.Time    `json:"last_update_time,omitempty"`
 	Options     *TmplOptions `json:"options,omitempty"`
+
+	// HasDraft and HasPublished are only set by TemplateList, and never sent.
+	HasDraft     bool `json:"has_draft,omitempty"`
+	HasPublished bool `json:"has_published,omitempty"`
+}
+
+// Versions of a Template, for TemplateGet and TemplateList.
+const (
+	// TemplateLatest is the published version if there is one, and the draft otherwise.
+	TemplateLatest    = ""
+	TemplateDraft     = "draft"
+	TemplatePublished = "published"
+)
+
+// templateVersionQuery returns the draft query parameter selecting version.
+func templateVersionQuery(version string) (QueryBuilder, error) {
+	q := QueryBuilder{}
+	switch version {
+	case TemplateLatest:
+	case TemplateDraft:
+		q = q.Set("draft", "true")
+	case TemplatePublished:
+		q = q.Set("draft", "false")
+	default:
+		return q, fmt.Errorf("Unknown Template version [%s]", version)
+	}
+	return q, nil
+}
+
+// writable returns a copy of t without the fields which are only set on retrieval.
+func (t *Template) writable() *Template {
+	tmp := *t
+	tmp.HasDraft, tmp.HasPublished = false, false
+	return &tmp
+}
+
+func (t *Template) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	state := "draft"
+	if t.Published {
+		state = "published"
+	}
+	return fmt.Sprintf("%s %q (%s, updated %s)", t.ID, t.Name, state, t.LastUpdate.Format(time.RFC3339))
+}
+
+// Equal reports whether two Templates have the same ID, settings and content.
+// LastUse and LastUpdate are ignored, as are HTMLLoader and TextLoader: only content
+// which is already loaded is compared.
+func (t *Template) Equal(o *Template) bool {
+	if t == nil || o == nil {
+		return t == o
+	}
+	return t.ID == o.ID &&
+		t.Name == o.Name &&
+		t.Description == o.Description &&
+		t.Published == o.Published &&
+		jsonEqual(t.Options, o.Options) &&
+		jsonEqual(t.Content.loaded(), o.Content.loaded())
 }
 
 // Content is what you'll send to your Recipients.
@@ -37,6 +100,57 @@ type Content struct {
 	EmailRFC822  string            `json:"email_rfc822,omitempty"`
 	Attachments  []Attachment      `json:"attachments,omitempty"`
 	InlineImages []InlineImage     `json:"inline_images,omitempty"`
+
+	// HTMLLoader and TextLoader, when set, are called as Content is marshaled,
+	// and their results take the place of HTML and Text respectively.
+	HTMLLoader ContentLoader `json:"-"`
+	TextLoader ContentLoader `json:"-"`
+}
+
+// ContentLoader produces HTML or Text content on demand.
+type ContentLoader func() (string, error)
+
+// ReaderLoader returns a ContentLoader which reads r until EOF the first time it's called.
+// The result is remembered, so the same Content (or its clones) may be marshaled
+// more than once, including concurrently.
+func ReaderLoader(r io.Reader) ContentLoader {
+	var once sync.Once
+	var str string
+	var err error
+	return func() (string, error) {
+		once.Do(func() {
+			var b []byte
+			b, err = ioutil.ReadAll(r)
+			str = string(b)
+		})
+		return str, err
+	}
+}
+
+// loaded returns a copy of c without HTMLLoader and TextLoader, so marshaling it doesn't call them.
+func (c Content) loaded() Content {
+	c.HTMLLoader, c.TextLoader = nil, nil
+	return c
+}
+
+// MarshalJSON evaluates HTMLLoader and TextLoader, if present.
+func (c Content) MarshalJSON() ([]byte, error) {
+	// avoid infinite recursion by marshaling a type without this method
+	type content Content
+	tmp := content(c)
+
+	var err error
+	if c.HTMLLoader != nil {
+		if tmp.HTML, err = c.HTMLLoader(); err != nil {
+			return nil, fmt.Errorf("Content.HTMLLoader failed: %s", err)
+		}
+	}
+	if c.TextLoader != nil {
+		if tmp.Text, err = c.TextLoader(); err != nil {
+			return nil, fmt.Errorf("Content.TextLoader failed: %s", err)
+		}
+	}
+	return json.Marshal(tmp)
 }
 
 // Attachment contains metadata and the contents of the file to attach.
@@ -125,13 +239,14 @@ func (t *Template) Validate() error {
 		// TODO: optionally validate MIME structure
 		// if MIME content is present, clobber all other Content options
 		t.Content = Content{EmailRFC822: t.Content.EmailRFC822}
-		return nil
+		return checkSize("Template content", len(t.Content.EmailRFC822), MaxTemplateContentBytes)
 	}
 
 	// enforce required parameters
 	if t.Content.Subject == "" {
 		return fmt.Errorf("Template requires a non-empty Content.Subject")
-	} else if t.Content.HTML == "" && t.Content.Text == "" {
+	} else if t.Content.HTML == "" && t.Content.Text == "" &&
+		t.Content.HTMLLoader == nil && t.Content.TextLoader == nil {
 		return fmt.Errorf("Template requires either Content.HTML or Content.Text")
 	}
 	_, err := ParseFrom(t.Content.From)
@@ -159,6 +274,14 @@ func (t *Template) Validate() error {
 		}
 	}
 
+	// enforce max sizes
+	if err = checkSize("Template content", t.Content.contentSize(), MaxTemplateContentBytes); err != nil {
+		return err
+	}
+	if err = checkSize("Total attachment data", t.Content.attachmentSize(), MaxAttachmentBytes); err != nil {
+		return err
+	}
+
 	// enforce max lengths
 	if len(t.ID) > 64 {
 		return fmt.Errorf("Template id may not be longer than 64 bytes")
@@ -189,7 +312,7 @@ func (c *Client) TemplateCreate(t *Template) (id string, res *Response, err erro
 		return
 	}
 
-	jsonBytes, err := json.Marshal(t)
+	jsonBytes, err := json.Marshal(t.writable())
 	if err != nil {
 		return
 	}
@@ -247,7 +370,7 @@ func (c *Client) TemplateUpdate(t *Template) (res *Response, err error) {
 		return
 	}
 
-	jsonBytes, err := json.Marshal(t)
+	jsonBytes, err := json.Marshal(t.writable())
 	if err != nil {
 		return
 	}
@@ -290,10 +413,104 @@ func (c *Client) TemplateUpdate(t *Template) (res *Response, err error) {
 	return
 }
 
+// Template retrieves the Template with the specified id, including its Content
+// and last_update_time (as Template.LastUpdate).
+func (c *Client) Template(id string) (*Template, *Response, error) {
+	return c.TemplateGet(id, TemplateLatest)
+}
+
+// TemplateGet retrieves the specified version of the Template with the specified id.
+// Published is set if the version returned is the published one.
+func (c *Client) TemplateGet(id, version string) (*Template, *Response, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank id")
+	}
+	q, err := templateVersionQuery(version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf(templatesPathFormat, c.Config.ApiVersion)
+	url := q.URL(fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id))
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*Template{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if tmpl, ok := tmp["results"]; ok && tmpl != nil {
+			return tmpl, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to Template retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Template", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// TemplateUpdateIfUnchanged is like TemplateUpdate, but first re-fetches the Template
+// and returns ErrConflict if its last_update_time no longer matches t.LastUpdate.
+// This stops two deploys from silently overwriting each other's edits.
+// There is a small window between the check and the update which this can't protect against.
+func (c *Client) TemplateUpdateIfUnchanged(t *Template) (res *Response, err error) {
+	if t == nil {
+		err = fmt.Errorf("Update called with nil Template")
+		return
+	} else if t.LastUpdate.IsZero() {
+		err = fmt.Errorf("TemplateUpdateIfUnchanged requires Template.LastUpdate")
+		return
+	}
+
+	remote, res, err := c.Template(t.ID)
+	if err != nil {
+		return
+	}
+	if !remote.LastUpdate.Equal(t.LastUpdate) {
+		err = ErrConflict
+		return
+	}
+
+	return c.TemplateUpdate(t)
+}
+
 // List returns metadata for all Templates in the system.
 func (c *Client) Templates() ([]Template, *Response, error) {
+	return c.TemplateList(TemplateLatest)
+}
+
+// TemplateList returns metadata for Templates with the specified version; TemplateDraft
+// lists those with a draft, and TemplatePublished those which have been published.
+// HasDraft and HasPublished say which versions each has.
+func (c *Client) TemplateList(version string) ([]Template, *Response, error) {
+	q, err := templateVersionQuery(version)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	path := fmt.Sprintf(templatesPathFormat, c.Config.ApiVersion)
-	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	url := q.URL(fmt.Sprintf("%s%s", c.Config.BaseUrl, path))
 	res, err := c.HttpGet(url)
 	if err != nil {
 		return nil, nil, err
@@ -348,6 +565,11 @@ func (c *Client) TemplateDelete(id string) (res *Response, err error) {
 		return
 	}
 
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
 	if err = res.AssertJson(); err != nil {
 		return
 	}
@@ -378,7 +600,42 @@ func (c *Client) TemplateDelete(id string) (res *Response, err error) {
 	return
 }
 
+// TemplatePreview renders the published version of a Template with the provided substitution data.
+// The rendered content is in res.Results; see TemplatePreviewContent for a typed version.
 func (c *Client) TemplatePreview(id string, payload *PreviewOptions) (res *Response, err error) {
+	return c.templatePreview(id, payload, false)
+}
+
+// PreviewContent is a Template rendered by TemplatePreviewContent.
+type PreviewContent struct {
+	Subject string            `json:"subject"`
+	HTML    string            `json:"html,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	From    From              `json:"from"`
+	ReplyTo string            `json:"reply_to,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// TemplatePreviewContent renders a Template with the provided substitution data, so it can be
+// checked before sending. If draft is set the draft version is rendered, rather than the published one.
+func (c *Client) TemplatePreviewContent(id string, substitutionData map[string]interface{}, draft bool) (*PreviewContent, *Response, error) {
+	res, err := c.templatePreview(id, &PreviewOptions{SubstitutionData: substitutionData}, draft)
+	if err != nil {
+		return nil, res, err
+	}
+
+	var wrapper struct {
+		Results *PreviewContent `json:"results"`
+	}
+	if err = json.Unmarshal(res.Body, &wrapper); err != nil {
+		return nil, res, err
+	} else if wrapper.Results == nil {
+		return nil, res, fmt.Errorf("Unexpected response to Template preview")
+	}
+	return wrapper.Results, res, nil
+}
+
+func (c *Client) templatePreview(id string, payload *PreviewOptions, draft bool) (res *Response, err error) {
 	if id == "" {
 		err = fmt.Errorf("Preview called with blank id")
 		return
@@ -394,7 +651,11 @@ func (c *Client) TemplatePreview(id string, payload *PreviewOptions) (res *Respo
 	}
 
 	path := fmt.Sprintf(templatesPathFormat, c.Config.ApiVersion)
-	url := fmt.Sprintf("%s%s/%s/preview", c.Config.BaseUrl, path, id)
+	q := QueryBuilder{}
+	if draft {
+		q = q.Set("draft", "true")
+	}
+	url := q.URL(fmt.Sprintf("%s%s/%s/preview", c.Config.BaseUrl, path, id))
 	res, err = c.HttpPost(url, jsonBytes)
 	if err != nil {
 		return
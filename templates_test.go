@@ -1,7 +1,12 @@
 package gosparkpost_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
 	sp "github.com/SparkPost/gosparkpost"
@@ -74,3 +79,176 @@ func TestTemplates(t *testing.T) {
 	}
 	fmt.Printf("Deleted Template with id=%s\n", id)
 }
+
+func TestContentLoaders(t *testing.T) {
+	calls := 0
+	content := sp.Content{
+		Subject:    "lazy",
+		From:       "test@example.com",
+		HTMLLoader: sp.ReaderLoader(strings.NewReader("<p>from a reader</p>")),
+		TextLoader: func() (string, error) {
+			calls++
+			return "from a func", nil
+		},
+	}
+	tmpl := &sp.Template{Content: content}
+	if err := tmpl.Validate(); err != nil {
+		t.Fatalf("loaders should satisfy the html/text requirement: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		jsonBytes, err := json.Marshal(tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Content struct {
+				HTML string `json:"html"`
+				Text string `json:"text"`
+			} `json:"content"`
+		}
+		if err = json.Unmarshal(jsonBytes, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.Content.HTML != "<p>from a reader</p>" || out.Content.Text != "from a func" {
+			t.Fatalf("pass %d: unexpected content %+v", i, out.Content)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected TextLoader to run once per marshal, ran %d times", calls)
+	}
+}
+
+func TestTemplatePreviewContent(t *testing.T) {
+	var uri string
+	var body map[string]interface{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		uri = r.URL.RequestURI()
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"subject":"Hi Bob","html":"<p>Hi Bob</p>","text":"Hi Bob","from":{"email":"me@example.com","name":"Me"},"headers":{"X-Tag":"1"}}}`))
+	})
+	defer done()
+
+	preview, _, err := client.TemplatePreviewContent("welcome", map[string]interface{}{"name": "Bob"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri != "/api/v1/templates/welcome/preview?draft=true" {
+		t.Errorf("unexpected request %s", uri)
+	}
+	if body["substitution_data"].(map[string]interface{})["name"] != "Bob" {
+		t.Errorf("unexpected request body %v", body)
+	}
+	if preview.Subject != "Hi Bob" || preview.HTML != "<p>Hi Bob</p>" || preview.From.Email != "me@example.com" || preview.Headers["X-Tag"] != "1" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+
+	if _, err = client.TemplatePreview("welcome", nil); err != nil {
+		t.Fatal(err)
+	}
+	if uri != "/api/v1/templates/welcome/preview" {
+		t.Errorf("unexpected request %s", uri)
+	}
+}
+
+func TestTemplateVersions(t *testing.T) {
+	var calls []string
+	var updated map[string]interface{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.RequestURI()
+		calls = append(calls, call)
+		if r.Method == "PUT" {
+			b, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(b, &updated)
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(204)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "GET /api/v1/templates?draft=true":
+			w.Write([]byte(`{"results":[{"id":"welcome","name":"Welcome","published":false,"has_draft":true,"has_published":true}]}`))
+		case "GET /api/v1/templates/welcome?draft=false":
+			w.Write([]byte(`{"results":{"id":"welcome","name":"Welcome","published":true,"options":{"open_tracking":true},"content":{"subject":"Hi","text":"Hello"}}}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	list, _, err := client.TemplateList(sp.TemplateDraft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || !list[0].HasDraft || !list[0].HasPublished || list[0].Published {
+		t.Errorf("unexpected templates %+v", list)
+	}
+
+	tmpl, _, err := client.TemplateGet("welcome", sp.TemplatePublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tmpl.Published || tmpl.Options == nil || !tmpl.Options.OpenTracking || tmpl.Content.Subject != "Hi" {
+		t.Errorf("unexpected template %+v", tmpl)
+	}
+	if _, _, err = client.TemplateGet("welcome", "old"); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+
+	list[0].Content = sp.Content{From: "me@example.com", Subject: "Hi", Text: "Hello"}
+	if _, err = client.TemplateUpdate(&list[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updated["has_draft"]; ok {
+		t.Errorf("retrieval only fields sent in update %v", updated)
+	}
+
+	if _, err = client.TemplateDelete("welcome"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"GET /api/v1/templates?draft=true",
+		"GET /api/v1/templates/welcome?draft=false",
+		"PUT /api/v1/templates/welcome?update_published=false",
+		"DELETE /api/v1/templates/welcome",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestReaderLoaderConcurrent(t *testing.T) {
+	content := sp.Content{
+		Subject:    "lazy",
+		From:       "test@example.com",
+		HTMLLoader: sp.ReaderLoader(strings.NewReader("<p>from a reader</p>")),
+	}
+	// a reader may only be read once, so every marshal, of the Content or its clones, must
+	// see what the first one read
+	var wg sync.WaitGroup
+	results := make([]string, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := content
+			if i%2 == 1 {
+				c = content.Clone()
+			}
+			jsonBytes, err := json.Marshal(c)
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = string(jsonBytes)
+		}(i)
+	}
+	wg.Wait()
+	for i, r := range results {
+		if !strings.Contains(r, `"html":"\u003cp\u003efrom a reader\u003c/p\u003e"`) {
+			t.Errorf("marshal %d: unexpected content %s", i, r)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package gosparkpost
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// TenantSeparator ends the tenant prefix of namespaced campaign ids and metadata keys.
+const TenantSeparator = ":"
+
+// MaxCampaignIDLength is the longest campaign id SparkPost accepts, in bytes.
+const MaxCampaignIDLength = 64
+
+var (
+	tenantEscaper   = strings.NewReplacer("%", "%25", TenantSeparator, "%3A")
+	tenantUnescaper = strings.NewReplacer("%3A", TenantSeparator, "%25", "%")
+)
+
+// Tenant namespaces the campaign ids and metadata of one customer of a platform which
+// sends on their behalf from a single SparkPost account, so values chosen by different
+// customers can't collide. Namespaced values look like "tenant:value"; any separators
+// (or percent signs) in the tenant name are percent-escaped.
+type Tenant string
+
+func (t Tenant) prefix() string {
+	return tenantEscaper.Replace(string(t)) + TenantSeparator
+}
+
+// CampaignID returns id namespaced for this tenant. It's an error if the result
+// is longer than MaxCampaignIDLength.
+func (t Tenant) CampaignID(id string) (string, error) {
+	if t == "" {
+		return "", fmt.Errorf("Tenant may not be blank")
+	}
+	namespaced := t.prefix() + id
+	if len(namespaced) > MaxCampaignIDLength {
+		return "", fmt.Errorf("Campaign id [%s] may not be longer than %d bytes", namespaced, MaxCampaignIDLength)
+	}
+	return namespaced, nil
+}
+
+// Metadata returns a copy of m with each top-level key namespaced for this tenant.
+func (t Tenant) Metadata(m map[string]interface{}) (map[string]interface{}, error) {
+	if t == "" {
+		return nil, fmt.Errorf("Tenant may not be blank")
+	}
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[t.prefix()+k] = v
+	}
+	return out, nil
+}
+
+// Transmission namespaces the campaign id and metadata of tx, and the metadata of its inline
+// Recipients, for this tenant, in place. Metadata which isn't a map[string]interface{} is an error.
+func (t Tenant) Transmission(tx *Transmission) error {
+	if tx == nil {
+		return fmt.Errorf("Can't namespace a nil Transmission")
+	}
+	if tx.CampaignID != "" {
+		id, err := t.CampaignID(tx.CampaignID)
+		if err != nil {
+			return err
+		}
+		tx.CampaignID = id
+	}
+	var err error
+	if tx.Metadata, err = t.anyMetadata(tx.Metadata); err != nil {
+		return err
+	}
+
+	recips, err := inlineRecipients(tx.Recipients)
+	if err != nil {
+		return err
+	} else if recips == nil {
+		return nil
+	}
+	// inlineRecipients copied the list, so the caller's Recipients aren't changed
+	for i := range recips {
+		if recips[i].Metadata, err = t.anyMetadata(recips[i].Metadata); err != nil {
+			return err
+		}
+	}
+	tx.Recipients = recips
+	return nil
+}
+
+// anyMetadata namespaces metadata, which must be nil or a map[string]interface{}.
+func (t Tenant) anyMetadata(metadata interface{}) (interface{}, error) {
+	switch m := metadata.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return t.Metadata(m)
+	default:
+		return nil, fmt.Errorf("Can't namespace Metadata of type %T", metadata)
+	}
+}
+
+// ParseTenant splits a namespaced value into its tenant and the original value.
+// ok is false if s isn't namespaced.
+func ParseTenant(s string) (t Tenant, value string, ok bool) {
+	i := strings.Index(s, TenantSeparator)
+	if i <= 0 {
+		return "", s, false
+	}
+	return Tenant(tenantUnescaper.Replace(s[:i])), s[i+len(TenantSeparator):], true
+}
+
+// TenantOf returns the tenant an event belongs to, based on its campaign id.
+func TenantOf(e events.Event) (Tenant, bool) {
+	f := eventField(e, "CampaignID")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return "", false
+	}
+	t, _, ok := ParseTenant(f.String())
+	return t, ok
+}
+
+// metadataTenant returns the tenant of the first namespaced metadata key of an event, in order.
+func metadataTenant(e events.Event) (Tenant, bool) {
+	f := eventField(e, "Metadata")
+	if !f.IsValid() {
+		return "", false
+	}
+	if f.Kind() == reflect.Interface {
+		f = f.Elem()
+	}
+	if f.Kind() != reflect.Map || f.Type().Key().Kind() != reflect.String {
+		return "", false
+	}
+	keys := f.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, k := range keys {
+		if t, _, ok := ParseTenant(k.String()); ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// StripTenant removes the namespacing added by Tenant from an event's campaign id and
+// metadata, in place, returning the tenant it belongs to, found from its campaign id or
+// else its metadata. Metadata keys namespaced for other tenants are dropped; keys which
+// aren't namespaced at all are left alone.
+func StripTenant(e events.Event) (Tenant, bool) {
+	t, ok := TenantOf(e)
+	if !ok {
+		if t, ok = metadataTenant(e); !ok {
+			return "", false
+		}
+	}
+	stripTenant(e, t)
+	return t, true
+}
+
+// stripTenant removes t's namespacing from an event, however t was identified.
+func stripTenant(e events.Event, t Tenant) {
+	if f := eventField(e, "CampaignID"); f.CanSet() {
+		if owner, id, ok := ParseTenant(f.String()); ok && owner == t {
+			f.SetString(id)
+		}
+	}
+
+	f := eventField(e, "Metadata")
+	if !f.IsValid() || !f.CanSet() {
+		return
+	}
+	switch m := f.Interface().(type) {
+	case map[string]interface{}:
+		stripped := map[string]interface{}{}
+		for k, v := range m {
+			if key, keep := stripKey(t, k); keep {
+				stripped[key] = v
+			}
+		}
+		f.Set(reflect.ValueOf(stripped))
+	case map[string]string:
+		stripped := map[string]string{}
+		for k, v := range m {
+			if key, keep := stripKey(t, k); keep {
+				stripped[key] = v
+			}
+		}
+		f.Set(reflect.ValueOf(stripped))
+	}
+}
+
+// stripKey returns k without t's prefix, and whether it should be kept at all.
+func stripKey(t Tenant, k string) (string, bool) {
+	owner, key, ok := ParseTenant(k)
+	if !ok {
+		return k, true
+	}
+	return key, owner == t
+}
+
+// eventField returns the named field of the struct an event points to, if any.
+func eventField(e events.Event, name string) reflect.Value {
+	v := reflect.ValueOf(e)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.Elem().FieldByName(name)
+}
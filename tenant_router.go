@@ -0,0 +1,289 @@
+package gosparkpost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// ErrTenantQueueFull is passed to TenantRouter.OnError when a batch is refused because a
+// tenant's handler had fallen too far behind.
+var ErrTenantQueueFull = errors.New("tenant queue full")
+
+// TenantHandler consumes the webhook events of one tenant.
+type TenantHandler interface {
+	HandleEvents(ctx context.Context, t Tenant, evs events.Events) error
+}
+
+// TenantHandlerFunc allows a function to be used as a TenantHandler.
+type TenantHandlerFunc func(ctx context.Context, t Tenant, evs events.Events) error
+
+func (f TenantHandlerFunc) HandleEvents(ctx context.Context, t Tenant, evs events.Events) error {
+	return f(ctx, t, evs)
+}
+
+// TenantRouter accepts SparkPost webhook batches (it's an http.Handler) and passes the events
+// of each tenant to that tenant's own handler. Every tenant has a queue and goroutine of its own,
+// so a slow handler only delays its own tenant's events until its queue is full. Then batches
+// with events for that tenant are refused with a 503, none of their events being queued, and
+// SparkPost retries them later.
+//
+// A TenantRouter is a Component. Handlers are called with the context passed to Start, which
+// is cancelled if Stop gives up waiting for them. If Start isn't called, the first batch
+// starts the handlers with context.Background().
+type TenantRouter struct {
+	// Handler returns the handler for a tenant. It's called the first time each tenant is seen.
+	Handler func(t Tenant) TenantHandler
+	// Identify returns the tenant an event belongs to. Defaults to IdentifyTenant.
+	Identify func(e events.Event, raw json.RawMessage) (Tenant, bool)
+	// Untenanted receives events which don't belong to a tenant. They're dropped if it's nil.
+	Untenanted TenantHandler
+	// Strip, if set, removes the namespacing added by Tenant from events before they're handled.
+	Strip bool
+	// QueueSize is how many batches may be waiting for each tenant's handler. Defaults to 100.
+	QueueSize int
+	// OnError is passed errors returned by handlers, and ErrTenantQueueFull.
+	OnError func(t Tenant, err error)
+
+	runner
+	mu      sync.Mutex
+	queues  map[Tenant]chan events.Events
+	closing chan struct{}
+	started bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+var _ Component = &TenantRouter{}
+
+// IdentifyTenant finds the tenant of an event from its namespaced campaign id or metadata
+// (see Tenant), or failing that, the subaccount it was sent from, as a decimal string.
+func IdentifyTenant(e events.Event, raw json.RawMessage) (Tenant, bool) {
+	if t, ok := TenantOf(e); ok {
+		return t, true
+	} else if t, ok = metadataTenant(e); ok {
+		return t, true
+	}
+
+	var sub struct {
+		SubaccountID interface{} `json:"subaccount_id"`
+	}
+	if json.Unmarshal(raw, &sub) == nil {
+		switch id := sub.SubaccountID.(type) {
+		case float64:
+			if id > 0 {
+				return Tenant(strconv.FormatInt(int64(id), 10)), true
+			}
+		case string:
+			if n, err := strconv.Atoi(id); err == nil && n > 0 {
+				return Tenant(id), true
+			}
+		}
+	}
+	return "", false
+}
+
+// ServeHTTP accepts SparkPost webhook batches, queueing the events for each tenant's handler.
+// Handler errors are reported to OnError, since SparkPost retrying the whole batch would repeat
+// events for every other tenant. A batch is only refused if it's malformed, if a tenant's queue
+// is full, or once the router is stopped.
+func (r *TenantRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	raws, err := events.RawEvents(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	identify := r.Identify
+	if identify == nil {
+		identify = IdentifyTenant
+	}
+	batches := map[Tenant]events.Events{}
+	var order []Tenant
+	for _, raw := range raws {
+		e := events.ParseEvent(raw)
+		t, ok := identify(e, raw)
+		if !ok {
+			t = ""
+		} else if r.Strip {
+			stripTenant(e, t)
+		}
+		if _, seen := batches[t]; !seen {
+			order = append(order, t)
+		}
+		batches[t] = append(batches[t], e)
+	}
+
+	full, err := r.enqueue(order, batches)
+	for _, t := range full {
+		r.report(t, ErrTenantQueueFull)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// enqueue passes each tenant's batch to its queue, starting the handlers and the tenant's
+// goroutine if need be. Batches for tenants without a handler are discarded. If any of the
+// queues is full, nothing is queued, and the full tenants are returned with ErrTenantQueueFull.
+func (r *TenantRouter) enqueue(order []Tenant, batches map[Tenant]events.Events) (full []Tenant, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || (r.started && r.workContext().Err() != nil) {
+		return nil, fmt.Errorf("TenantRouter is stopped")
+	} else if !r.started {
+		if err = r.startLocked(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	// only enqueue sends to the queues, so with mu held, there's still room in them below
+	queues := make([]chan events.Events, len(order))
+	for i, t := range order {
+		queues[i] = r.queue(t)
+		if q := queues[i]; q != nil && len(q) == cap(q) {
+			full = append(full, t)
+		}
+	}
+	if len(full) > 0 {
+		return full, ErrTenantQueueFull
+	}
+	for i, t := range order {
+		if queues[i] != nil {
+			queues[i] <- batches[t]
+		}
+	}
+	return nil, nil
+}
+
+// queue returns the tenant's queue, starting its goroutine the first time it's seen.
+// It's nil for tenants without a handler. The caller holds mu.
+func (r *TenantRouter) queue(t Tenant) chan events.Events {
+	q, ok := r.queues[t]
+	if ok {
+		return q
+	}
+	var h TenantHandler
+	if t == "" {
+		h = r.Untenanted
+	} else if r.Handler != nil {
+		h = r.Handler(t)
+	}
+	size := r.QueueSize
+	if size <= 0 {
+		size = 100
+	}
+	if h != nil {
+		q = make(chan events.Events, size)
+		r.wg.Add(1)
+		go r.consume(r.workContext(), t, h, q)
+	}
+	if r.queues == nil {
+		r.queues = map[Tenant]chan events.Events{}
+	}
+	// a nil queue remembers that the tenant has no handler
+	r.queues[t] = q
+	return q
+}
+
+func (r *TenantRouter) consume(ctx context.Context, t Tenant, h TenantHandler, q chan events.Events) {
+	defer r.wg.Done()
+	for {
+		select {
+		case evs, ok := <-q:
+			if !ok {
+				return
+			}
+			if err := h.HandleEvents(ctx, t, evs); err != nil {
+				r.report(t, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *TenantRouter) report(t Tenant, err error) {
+	r.fail(err)
+	if r.OnError != nil {
+		r.OnError(t, err)
+	}
+}
+
+// Start starts the router, whose handlers are called with ctx: cancelling it abandons the
+// events in progress and those still queued, and stops the router.
+func (r *TenantRouter) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return fmt.Errorf("TenantRouter is stopped")
+	} else if r.started {
+		return fmt.Errorf("already running")
+	}
+	return r.startLocked(ctx)
+}
+
+// startLocked starts the router. It must be called with mu held.
+func (r *TenantRouter) startLocked(ctx context.Context) error {
+	r.started = true
+	r.closing = make(chan struct{})
+	return r.start(ctx, func(ctx context.Context) {
+		select {
+		case <-r.closing:
+		case <-ctx.Done():
+			// refuse batches from now on, since nothing will handle them
+			r.mu.Lock()
+			r.closed = true
+			r.mu.Unlock()
+		}
+		r.wg.Wait()
+	})
+}
+
+// Close stops accepting batches and waits for the events already queued to be handled. When
+// ctx is done it gives up, cancelling the handlers' context.
+func (r *TenantRouter) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		for _, q := range r.queues {
+			if q != nil {
+				close(q)
+			}
+		}
+		if r.closing != nil {
+			close(r.closing)
+		}
+	}
+	r.mu.Unlock()
+
+	if err := r.drain(ctx); err != nil {
+		return fmt.Errorf("timed out waiting for tenant handlers: %s", ctx.Err())
+	}
+	return nil
+}
+
+// Stop is Close.
+func (r *TenantRouter) Stop(ctx context.Context) error {
+	return r.Close(ctx)
+}
+
+// Health reports whether the router is running, and the last error from a handler, or
+// ErrTenantQueueFull.
+func (r *TenantRouter) Health() Health {
+	return r.runner.report()
+}
@@ -0,0 +1,175 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+func TestTenantRouter(t *testing.T) {
+	var mu sync.Mutex
+	got := map[sp.Tenant][]string{}
+	record := sp.TenantHandlerFunc(func(ctx context.Context, tenant sp.Tenant, evs events.Events) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, e := range evs {
+			campaign := ""
+			if d, ok := e.(*events.Delivery); ok {
+				campaign = d.CampaignID
+				// metadata keys which have been stripped
+				if m, ok := d.Metadata.(map[string]interface{}); ok && m["user"] != nil {
+					campaign += "+user"
+				}
+			}
+			got[tenant] = append(got[tenant], campaign)
+		}
+		return nil
+	})
+
+	// acme's handler is stuck until released, and shouldn't hold up globex
+	entered, release := make(chan struct{}, 3), make(chan struct{})
+	stuck := sp.TenantHandlerFunc(func(ctx context.Context, tenant sp.Tenant, evs events.Events) error {
+		entered <- struct{}{}
+		<-release
+		return record(ctx, tenant, evs)
+	})
+
+	var errs []error
+	router := &sp.TenantRouter{
+		Handler: func(tenant sp.Tenant) sp.TenantHandler {
+			if tenant == "acme" {
+				return stuck
+			}
+			return record
+		},
+		Untenanted: record,
+		Strip:      true,
+		QueueSize:  1,
+		OnError: func(tenant sp.Tenant, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	}
+
+	post := func(body string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		return w.Code
+	}
+
+	batch := `[
+		{"msys":{"message_event":{"type":"delivery","campaign_id":"acme:welcome"}}},
+		{"msys":{"message_event":{"type":"delivery","campaign_id":"news","rcpt_meta":{"globex:user":"7"}}}},
+		{"msys":{"message_event":{"type":"delivery","campaign_id":"receipt","subaccount_id":"101"}}},
+		{"msys":{"message_event":{"type":"delivery","campaign_id":"plain"}}}
+	]`
+	// waits for the handlers which aren't stuck to catch up
+	handled := func(n int) {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			done := len(got["globex"])+len(got["101"])+len(got[""]) == n
+			mu.Unlock()
+			if done {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %d events", n)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if code := post(batch); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if i == 1 {
+			<-entered
+		}
+		handled(3 * i)
+	}
+	// one acme batch is being handled and one queued, so the third is refused, to be retried,
+	// and none of its events are queued
+	if code := post(batch); code != http.StatusServiceUnavailable {
+		t.Errorf("expected a full queue to refuse the batch, got %d", code)
+	}
+	if code := post(`{`); code != http.StatusBadRequest {
+		t.Errorf("expected a malformed batch to be rejected, got %d", code)
+	}
+
+	mu.Lock()
+	if len(got["acme"]) != 0 {
+		t.Errorf("expected acme's events to be waiting, got %v", got["acme"])
+	}
+	if len(got["globex"]) != 2 || got["globex"][0] != "news+user" || len(got["101"]) != 2 || len(got[""]) != 2 {
+		t.Errorf("unexpected events %v", got)
+	}
+	if len(errs) != 1 || errs[0] != sp.ErrTenantQueueFull {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	mu.Unlock()
+	if h := router.Health(); !h.Running || h.LastError != sp.ErrTenantQueueFull {
+		t.Errorf("unexpected health %+v", h)
+	}
+
+	close(release)
+	if err := router.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got["acme"]) != 2 || got["acme"][0] != "welcome" {
+		t.Errorf("unexpected acme events %v", got["acme"])
+	}
+	if code := post(batch); code != http.StatusServiceUnavailable {
+		t.Errorf("expected a closed router to refuse batches, got %d", code)
+	}
+}
+
+func TestTenantRouterStop(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	var handlerErr error
+	router := &sp.TenantRouter{
+		Handler: func(tenant sp.Tenant) sp.TenantHandler {
+			return sp.TenantHandlerFunc(func(ctx context.Context, tenant sp.Tenant, evs events.Events) error {
+				entered <- struct{}{}
+				<-ctx.Done()
+				handlerErr = ctx.Err()
+				return handlerErr
+			})
+		},
+	}
+	var _ sp.Component = router
+	if err := router.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.Start(context.Background()); err == nil {
+		t.Error("expected an error starting twice")
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(
+		`[{"msys":{"message_event":{"type":"delivery","campaign_id":"acme:welcome"}}}]`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	<-entered
+
+	// the handler doesn't return by itself, so Stop gives up, cancelling its context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := router.Stop(ctx); err == nil {
+		t.Error("expected Stop to time out")
+	}
+	if err := router.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if h := router.Health(); h.Running || handlerErr != context.Canceled || h.LastError != context.Canceled {
+		t.Errorf("unexpected health %+v, handler error %v", h, handlerErr)
+	}
+}
@@ -0,0 +1,98 @@
+package gosparkpost_test
+
+import (
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+func TestTenantCampaignID(t *testing.T) {
+	for _, tenant := range []sp.Tenant{"acme", "a:b", "100%", "%3A"} {
+		id, err := tenant.CampaignID("spring:sale")
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, value, ok := sp.ParseTenant(id)
+		if !ok || parsed != tenant || value != "spring:sale" {
+			t.Errorf("%q: %q parsed to %q, %q, %v", tenant, id, parsed, value, ok)
+		}
+	}
+
+	if id, _ := sp.Tenant("a:b").CampaignID("x"); id != "a%3Ab:x" {
+		t.Errorf("unexpected escaping %q", id)
+	}
+	if _, err := sp.Tenant("acme").CampaignID(strings.Repeat("x", 60)); err == nil {
+		t.Error("expected an error for a long campaign id")
+	}
+	if _, err := sp.Tenant("").CampaignID("x"); err == nil {
+		t.Error("expected an error for a blank tenant")
+	}
+	if _, _, ok := sp.ParseTenant("plain"); ok {
+		t.Error("expected plain values not to be namespaced")
+	}
+}
+
+func TestTenantTransmission(t *testing.T) {
+	tx := &sp.Transmission{CampaignID: "welcome", Metadata: map[string]interface{}{"user_id": 7}}
+	if err := sp.Tenant("acme").Transmission(tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.CampaignID != "acme:welcome" {
+		t.Errorf("unexpected campaign id %q", tx.CampaignID)
+	}
+	if m := tx.Metadata.(map[string]interface{}); len(m) != 1 || m["acme:user_id"] != 7 {
+		t.Errorf("unexpected metadata %v", m)
+	}
+
+	if err := sp.Tenant("acme").Transmission(&sp.Transmission{Metadata: "x"}); err == nil {
+		t.Error("expected an error for metadata which isn't a map")
+	}
+
+	// inline Recipients' metadata is namespaced too, without changing the caller's list
+	recips := []sp.Recipient{{Address: "a@example.com", Metadata: map[string]interface{}{"ref": "r1"}}, {Address: "b@example.com"}}
+	tx = &sp.Transmission{Recipients: recips}
+	if err := sp.Tenant("acme").Transmission(tx); err != nil {
+		t.Fatal(err)
+	}
+	if m := tx.Recipients.([]sp.Recipient)[0].Metadata.(map[string]interface{}); len(m) != 1 || m["acme:ref"] != "r1" {
+		t.Errorf("unexpected recipient metadata %v", m)
+	}
+	if m := recips[0].Metadata.(map[string]interface{}); m["ref"] != "r1" {
+		t.Errorf("caller's recipient metadata was changed: %v", m)
+	}
+}
+
+func TestStripTenant(t *testing.T) {
+	d := &events.Delivery{
+		CampaignID: "acme:welcome",
+		Metadata:   map[string]interface{}{"acme:user_id": "7", "other:user_id": "8", "plain": "x"},
+	}
+	tenant, ok := sp.StripTenant(d)
+	if !ok || tenant != "acme" {
+		t.Fatalf("unexpected tenant %q, %v", tenant, ok)
+	}
+	if d.CampaignID != "welcome" {
+		t.Errorf("unexpected campaign id %q", d.CampaignID)
+	}
+	m := d.Metadata.(map[string]interface{})
+	if len(m) != 2 || m["user_id"] != "7" || m["plain"] != "x" {
+		t.Errorf("unexpected metadata %v", m)
+	}
+
+	b := &events.Bounce{CampaignID: "acme:welcome", Metadata: map[string]string{"acme:user_id": "7"}}
+	if _, ok = sp.StripTenant(b); !ok || b.Metadata["user_id"] != "7" {
+		t.Errorf("unexpected bounce metadata %v", b.Metadata)
+	}
+
+	// the tenant may only be known from the metadata
+	d = &events.Delivery{CampaignID: "plain", Metadata: map[string]interface{}{"acme:user_id": "7"}}
+	if tenant, ok = sp.StripTenant(d); !ok || tenant != "acme" || d.Metadata.(map[string]interface{})["user_id"] != "7" {
+		t.Errorf("unexpected tenant %q, %v, metadata %v", tenant, ok, d.Metadata)
+	}
+
+	if _, ok = sp.TenantOf(&events.Delivery{CampaignID: "plain"}); ok {
+		t.Error("expected no tenant for a plain campaign id")
+	}
+}
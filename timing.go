@@ -0,0 +1,187 @@
+package gosparkpost
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// CallTiming breaks down where the time went during an API call.
+// Phases which didn't happen (for example DNS and Connect, when a connection
+// was reused) are zero. Total runs until the response headers were received.
+type CallTiming struct {
+	Method string
+	URL    string
+	Status int
+	Err    error
+
+	Reused  bool
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// TTFB is the time from the start of the call until the first byte of the response.
+	TTFB  time.Duration
+	Total time.Duration
+}
+
+func (t CallTiming) String() string {
+	return fmt.Sprintf("%s %s: %d in %s (dns %s, connect %s, tls %s, ttfb %s, reused %t)",
+		t.Method, t.URL, t.Status, t.Total, t.DNS, t.Connect, t.TLS, t.TTFB, t.Reused)
+}
+
+// logSlowCall is used when Config.SlowCallThreshold is set without Config.OnSlowCall.
+func logSlowCall(t CallTiming) {
+	log.Printf("gosparkpost: slow call: %s", t)
+}
+
+// callTimer collects the timestamps of a single call. Trace hooks may be called
+// from other goroutines (for example while dialing), hence the lock.
+type callTimer struct {
+	mu                     sync.Mutex
+	start                  time.Time
+	dnsStart, connectStart time.Time
+	tlsStart               time.Time
+	timing                 CallTiming
+}
+
+func newCallTimer(ctx context.Context) (context.Context, *callTimer) {
+	ct := &callTimer{start: time.Now()}
+	since := func(t time.Time) time.Duration {
+		if t.IsZero() {
+			return 0
+		}
+		return time.Since(t)
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			ct.mu.Lock()
+			ct.dnsStart = time.Now()
+			ct.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			ct.mu.Lock()
+			ct.timing.DNS = since(ct.dnsStart)
+			ct.mu.Unlock()
+		},
+		ConnectStart: func(network, addr string) {
+			ct.mu.Lock()
+			ct.connectStart = time.Now()
+			ct.mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			ct.mu.Lock()
+			ct.timing.Connect = since(ct.connectStart)
+			ct.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			ct.mu.Lock()
+			ct.tlsStart = time.Now()
+			ct.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			ct.mu.Lock()
+			ct.timing.TLS = since(ct.tlsStart)
+			ct.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			ct.mu.Lock()
+			ct.timing.Reused = info.Reused
+			ct.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			ct.mu.Lock()
+			ct.timing.TTFB = since(ct.start)
+			ct.mu.Unlock()
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), ct
+}
+
+func (ct *callTimer) finish(method, url string, status int, err error) CallTiming {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	t := ct.timing
+	t.Method, t.URL, t.Status, t.Err = method, url, status, err
+	t.Total = time.Since(ct.start)
+	return t
+}
+
+// DefaultLatencyBuckets are the upper bounds used by a LatencyHistogram with no Buckets set.
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// LatencyHistogram counts API calls by how long they took.
+// Set Client.Latency to record every call made with a Client.
+type LatencyHistogram struct {
+	// Buckets are the (ascending) upper bounds of each bucket; calls slower than
+	// the last bound are counted separately. Defaults to DefaultLatencyBuckets.
+	Buckets []time.Duration
+
+	mu     sync.Mutex
+	counts []int
+	sum    time.Duration
+	n      int
+}
+
+// LatencyBucket is the number of calls which took no longer than UpperBound,
+// and longer than the previous bucket's UpperBound. The final bucket's UpperBound is zero.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int
+}
+
+// Observe records a single call.
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Buckets == nil {
+		h.Buckets = DefaultLatencyBuckets
+	}
+	if h.counts == nil {
+		h.counts = make([]int, len(h.Buckets)+1)
+	}
+	i := 0
+	for i < len(h.Buckets) && d > h.Buckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.sum += d
+	h.n++
+}
+
+// Counts returns the number of calls in each bucket, ending with those slower than every bound.
+func (h *LatencyHistogram) Counts() []LatencyBucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := h.Buckets
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	out := make([]LatencyBucket, len(buckets)+1)
+	for i := range out {
+		if i < len(buckets) {
+			out[i].UpperBound = buckets[i]
+		}
+		if h.counts != nil {
+			out[i].Count = h.counts[i]
+		}
+	}
+	return out
+}
+
+// Mean returns the average duration of the calls observed, and how many there were.
+func (h *LatencyHistogram) Mean() (time.Duration, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.n == 0 {
+		return 0, 0
+	}
+	return h.sum / time.Duration(h.n), h.n
+}
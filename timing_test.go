@@ -0,0 +1,50 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestSlowCalls(t *testing.T) {
+	var slow []sp.CallTiming
+	cfg := &sp.Config{
+		ApiKey:            "testkey",
+		SlowCallThreshold: 50 * time.Millisecond,
+		OnSlowCall:        func(ct sp.CallTiming) { slow = append(slow, ct) },
+	}
+	client, done := newTestClient(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") != "" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{}}`))
+	})
+	defer done()
+	client.Latency = &sp.LatencyHistogram{Buckets: []time.Duration{50 * time.Millisecond}}
+
+	for _, q := range []string{"", "?slow=1", ""} {
+		res, err := client.HttpGet(cfg.BaseUrl + "/api/v1/account" + q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.ReadBody()
+	}
+
+	if len(slow) != 1 {
+		t.Fatalf("expected one slow call, got %v", slow)
+	}
+	if slow[0].Status != 200 || slow[0].Total < 100*time.Millisecond || slow[0].TTFB < 100*time.Millisecond || !slow[0].Reused {
+		t.Errorf("unexpected timing for slow call: %s", slow[0])
+	}
+
+	counts := client.Latency.Counts()
+	if len(counts) != 2 || counts[0].Count != 2 || counts[1].Count != 1 {
+		t.Errorf("expected 2 fast calls and 1 slow one, got %+v", counts)
+	}
+	if _, n := client.Latency.Mean(); n != 3 {
+		t.Errorf("expected 3 calls, got %d", n)
+	}
+}
@@ -0,0 +1,101 @@
+package gosparkpost
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	certifi "github.com/certifi/gocertifi"
+)
+
+// TLSPolicy controls the TLS connections made to SparkPost, over both the REST API
+// (see Config.TLS) and SMTP (see Client.SMTPTLSConfig).
+type TLSPolicy struct {
+	// MinVersion defaults to TLS 1.2.
+	MinVersion uint16
+	// CipherSuites, if set, limits the suites offered for TLS 1.2 connections.
+	CipherSuites []uint16
+	// CurvePreferences, if set, limits the elliptic curves used for key exchange.
+	CurvePreferences []tls.CurveID
+	// RootCAs verify the server's certificate. They default to the Mozilla cert pool.
+	RootCAs *x509.CertPool
+	// Certificates are presented when the server asks for a client certificate (mutual TLS).
+	// Load them with tls.LoadX509KeyPair.
+	Certificates []tls.Certificate
+}
+
+// TLSPolicyModern requires TLS 1.2 or later with forward secret AEAD cipher suites.
+func TLSPolicyModern() *TLSPolicy {
+	return &TLSPolicy{
+		MinVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		},
+	}
+}
+
+// TLSPolicyFIPS limits connections to the TLS 1.2 cipher suites and curves approved by
+// FIPS 140-2 (AES-GCM with ECDHE over P-256 or P-384). It only restricts what's negotiated;
+// a FIPS validated crypto module is still needed for compliance.
+func TLSPolicyFIPS() *TLSPolicy {
+	return &TLSPolicy{
+		MinVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		},
+		CurvePreferences: []tls.CurveID{tls.CurveP384, tls.CurveP256},
+	}
+}
+
+// Config returns a tls.Config applying the policy to connections to serverName.
+// A nil policy only sets the Mozilla cert pool, leaving everything else to crypto/tls.
+func (p *TLSPolicy) Config(serverName string) (*tls.Config, error) {
+	minVersion := uint16(0)
+	if p == nil {
+		p = &TLSPolicy{}
+	} else if minVersion = p.MinVersion; minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	cfg := &tls.Config{
+		ServerName:       serverName,
+		MinVersion:       minVersion,
+		CipherSuites:     p.CipherSuites,
+		CurvePreferences: p.CurvePreferences,
+		RootCAs:          p.RootCAs,
+		Certificates:     p.Certificates,
+	}
+	if minVersion < tls.VersionTLS12 && len(cfg.CipherSuites) > 0 {
+		return nil, fmt.Errorf("TLSPolicy CipherSuites require a MinVersion of TLS 1.2")
+	}
+	if cfg.RootCAs == nil {
+		// Ran into an issue where USERTrust was not recognized on OSX, so use the Mozilla cert pool.
+		pool, err := certifi.CACerts()
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// SMTPTLSConfig returns a tls.Config for sending through SparkPost's SMTP relay
+// (smtp.sparkpostmail.com, or host if set), applying Config.TLS. Pass it to smtp.Client.StartTLS
+// so SMTP connections follow the same policy as API calls.
+func (c *Client) SMTPTLSConfig(host string) (*tls.Config, error) {
+	if host == "" {
+		host = "smtp.sparkpostmail.com"
+	}
+	var policy *TLSPolicy
+	if c.Config != nil {
+		policy = c.Config.TLS
+	}
+	return policy.Config(host)
+}
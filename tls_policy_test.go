@@ -0,0 +1,61 @@
+package gosparkpost_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTLSPolicy(t *testing.T) {
+	var peerCerts int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peerCerts = len(r.TLS.PeerCertificates)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{}}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	policy := sp.TLSPolicyFIPS()
+	policy.RootCAs = pool
+	policy.Certificates = server.TLS.Certificates
+
+	client := &sp.Client{}
+	if err := client.Init(&sp.Config{BaseUrl: server.URL, ApiKey: "testkey", TLS: policy}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.HttpGet(server.URL + "/api/v1/account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := res.HTTP.TLS; state == nil || state.Version < tls.VersionTLS12 {
+		t.Errorf("unexpected TLS state %+v", state)
+	}
+	if peerCerts != 1 {
+		t.Errorf("expected a client certificate, got %d", peerCerts)
+	}
+
+	smtp, err := client.SMTPTLSConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smtp.ServerName != "smtp.sparkpostmail.com" || smtp.MinVersion != tls.VersionTLS12 ||
+		len(smtp.CipherSuites) != len(policy.CipherSuites) || smtp.RootCAs != pool {
+		t.Errorf("SMTP config doesn't follow the policy: %+v", smtp)
+	}
+
+	own := &sp.Client{Client: http.DefaultClient}
+	if err = own.Init(&sp.Config{ApiKey: "testkey", TLS: policy}); err == nil {
+		t.Error("expected an error applying TLS to a caller provided http.Client")
+	}
+	if _, err = (&sp.TLSPolicy{MinVersion: tls.VersionTLS10, CipherSuites: policy.CipherSuites}).Config(""); err == nil {
+		t.Error("expected an error for cipher suites below TLS 1.2")
+	}
+}
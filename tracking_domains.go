@@ -0,0 +1,351 @@
+package gosparkpost
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// https://developers.sparkpost.com/api/tracking-domains/
+var trackingDomainsPathFormat = "/api/v%d/tracking-domains"
+
+// TrackingDomain is a domain used to rewrite links, so open and click tracking uses
+// a domain recipients recognize. Its CNAME must point at SparkPost.
+type TrackingDomain struct {
+	Domain string `json:"domain,omitempty"`
+	// Port is set by SparkPost: 443 if Secure, 80 otherwise.
+	Port   int  `json:"port,omitempty"`
+	Secure bool `json:"secure"`
+	// Default makes this the tracking domain for SendingDomains which don't name one.
+	Default bool `json:"default"`
+	// SubaccountID, if non-zero, creates the TrackingDomain for that subaccount.
+	// It's reported by TrackingDomains, and ignored on update.
+	SubaccountID int `json:"subaccount_id,omitempty"`
+
+	// Status is set by SparkPost, and ignored on create and update.
+	Status *TrackingDomainStatus `json:"status,omitempty"`
+}
+
+// TrackingDomainStatus reports whether a TrackingDomain's CNAME record has been verified.
+type TrackingDomainStatus struct {
+	Verified         bool   `json:"verified"`
+	CNAMEStatus      string `json:"cname_status,omitempty"`
+	ComplianceStatus string `json:"compliance_status,omitempty"`
+}
+
+// TrackingDomainsParams filters the results of TrackingDomains.
+type TrackingDomainsParams struct {
+	// Default, if set, returns only the default domain (true) or the others (false).
+	Default *bool
+	// Subaccounts limits results to the TrackingDomains of these subaccounts.
+	Subaccounts []int
+}
+
+func (d *TrackingDomain) String() string {
+	s := d.Domain
+	if d.Secure {
+		s = "https://" + s
+	} else {
+		s = "http://" + s
+	}
+	if d.Status != nil && !d.Status.Verified {
+		s += " (unverified)"
+	}
+	return s
+}
+
+// subaccountHeader returns the header which makes a request on behalf of a subaccount.
+func subaccountHeader(id int) map[string]string {
+	if id == 0 {
+		return nil
+	}
+	return map[string]string{SubaccountHeader: strconv.Itoa(id)}
+}
+
+// TrackingDomainCreate creates the provided TrackingDomain, for its subaccount if SubaccountID is set.
+func (c *Client) TrackingDomainCreate(d *TrackingDomain) (res *Response, err error) {
+	if d == nil {
+		err = fmt.Errorf("Create called with nil TrackingDomain")
+		return
+	} else if d.Domain == "" {
+		err = fmt.Errorf("TrackingDomain requires a non-empty Domain")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(map[string]interface{}{
+		"domain":  d.Domain,
+		"secure":  d.Secure,
+		"default": d.Default,
+	})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(trackingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.DoRequestWithHeaders("POST", url, jsonBytes, subaccountHeader(d.SubaccountID))
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("TrackingDomain", "create")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// TrackingDomains returns the TrackingDomains matching params, which may be nil.
+func (c *Client) TrackingDomains(params *TrackingDomainsParams) ([]TrackingDomain, *Response, error) {
+	q := QueryBuilder{}
+	if params != nil {
+		if params.Default != nil {
+			q.Set("default", strconv.FormatBool(*params.Default))
+		}
+		q.Ints("subaccounts", params.Subaccounts...)
+	}
+	path := fmt.Sprintf(trackingDomainsPathFormat, c.Config.ApiVersion)
+	res, err := c.HttpGet(q.URL(c.Config.BaseUrl + path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+		dlist := map[string][]TrackingDomain{}
+		if err = json.Unmarshal(body, &dlist); err != nil {
+			return nil, res, err
+		} else if list, ok := dlist["results"]; ok {
+			return list, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to TrackingDomain list")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("TrackingDomain", "list")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// TrackingDomain retrieves the TrackingDomain with the specified name.
+func (c *Client) TrackingDomain(domain string) (*TrackingDomain, *Response, error) {
+	if domain == "" {
+		return nil, nil, fmt.Errorf("Retrieve called with blank domain")
+	}
+
+	path := fmt.Sprintf(trackingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, domain)
+	res, err := c.HttpGet(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*TrackingDomain{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if d, ok := tmp["results"]; ok && d != nil {
+			if d.Domain == "" {
+				d.Domain = domain
+			}
+			return d, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to TrackingDomain retrieve")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("TrackingDomain", "retrieve")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
+
+// TrackingDomainUpdate sets Secure and Default on the TrackingDomain with the Domain of the one provided.
+func (c *Client) TrackingDomainUpdate(d *TrackingDomain) (res *Response, err error) {
+	if d == nil {
+		err = fmt.Errorf("Update called with nil TrackingDomain")
+		return
+	} else if d.Domain == "" {
+		err = fmt.Errorf("Update called with blank domain")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(map[string]interface{}{
+		"secure":  d.Secure,
+		"default": d.Default,
+	})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(trackingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, d.Domain)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("TrackingDomain", "update")
+		if err != nil {
+			return
+		}
+
+		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	}
+
+	return
+}
+
+// TrackingDomainDelete removes the TrackingDomain with the specified name.
+func (c *Client) TrackingDomainDelete(domain string) (res *Response, err error) {
+	if domain == "" {
+		err = fmt.Errorf("Delete called with blank domain")
+		return
+	}
+
+	path := fmt.Sprintf(trackingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, domain)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	// success is a 204, with no body
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("TrackingDomain", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
+
+// TrackingDomainVerify checks the CNAME record of the TrackingDomain with the specified name,
+// returning its updated status.
+func (c *Client) TrackingDomainVerify(domain string) (*TrackingDomainStatus, *Response, error) {
+	if domain == "" {
+		return nil, nil, fmt.Errorf("Verify called with blank domain")
+	}
+
+	path := fmt.Sprintf(trackingDomainsPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s/%s/verify", c.Config.BaseUrl, path, domain)
+	res, err := c.HttpPost(url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return nil, res, err
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var body []byte
+		body, err = res.ReadBody()
+		if err != nil {
+			return nil, res, err
+		}
+
+		tmp := map[string]*TrackingDomainStatus{}
+		if err = json.Unmarshal(body, &tmp); err != nil {
+			return nil, res, err
+		} else if status, ok := tmp["results"]; ok && status != nil {
+			return status, res, nil
+		}
+		return nil, res, fmt.Errorf("Unexpected response to TrackingDomain verify")
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return nil, res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("TrackingDomain", "verify")
+		if err != nil {
+			return nil, res, err
+		}
+	}
+	return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+}
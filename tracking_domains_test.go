@@ -0,0 +1,96 @@
+package gosparkpost_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTrackingDomains(t *testing.T) {
+	var calls []string
+	var subaccount string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/tracking-domains":
+			subaccount = r.Header.Get("X-MSYS-SUBACCOUNT")
+			w.Write([]byte(`{"results":{"domain":"click.example.com"}}`))
+		case "GET /api/v1/tracking-domains":
+			w.Write([]byte(`{"results":[{"domain":"click.example.com","port":443,"secure":true,"default":true,"subaccount_id":5,
+				"status":{"verified":false,"cname_status":"pending","compliance_status":"pending"}}]}`))
+		case "GET /api/v1/tracking-domains/click.example.com":
+			w.Write([]byte(`{"results":{"port":80,"secure":false,"default":false,"status":{"verified":true,"cname_status":"valid"}}}`))
+		case "POST /api/v1/tracking-domains/click.example.com/verify":
+			w.Write([]byte(`{"results":{"verified":true,"cname_status":"valid","compliance_status":"valid"}}`))
+		case "DELETE /api/v1/tracking-domains/click.example.com":
+			w.WriteHeader(204)
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, err := client.TrackingDomainCreate(&sp.TrackingDomain{
+		Domain: "click.example.com", Secure: true, Default: true, SubaccountID: 5,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if subaccount != "5" {
+		t.Errorf("expected the subaccount header, got %q", subaccount)
+	}
+
+	isDefault := true
+	list, _, err := client.TrackingDomains(&sp.TrackingDomainsParams{Default: &isDefault, Subaccounts: []int{5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Port != 443 || list[0].SubaccountID != 5 || list[0].Status.CNAMEStatus != "pending" {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	d, _, err := client.TrackingDomain("click.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Domain != "click.example.com" || d.Port != 80 || !d.Status.Verified {
+		t.Errorf("unexpected domain %+v", d)
+	}
+
+	d.Secure = true
+	if _, err = client.TrackingDomainUpdate(d); err != nil {
+		t.Fatal(err)
+	}
+
+	status, _, err := client.TrackingDomainVerify("click.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Verified || status.ComplianceStatus != "valid" {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if _, err = client.TrackingDomainDelete("click.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`POST /api/v1/tracking-domains {"default":true,"domain":"click.example.com","secure":true}`,
+		`GET /api/v1/tracking-domains?default=true&subaccounts=5 `,
+		`GET /api/v1/tracking-domains/click.example.com `,
+		`PUT /api/v1/tracking-domains/click.example.com {"default":false,"secure":true}`,
+		`POST /api/v1/tracking-domains/click.example.com/verify `,
+		`DELETE /api/v1/tracking-domains/click.example.com `,
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected %d calls, got %v", len(expected), calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("call %d: expected %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package gosparkpost
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Outcomes reported by WaitForTransmission.
+const (
+	// TransmissionSucceeded means every message was generated (and, if requested, injected).
+	TransmissionSucceeded = "succeeded"
+	// TransmissionFailed means the share of recipients which failed generation, or were invalid,
+	// exceeded WaitOptions.FailureThreshold.
+	TransmissionFailed = "failed"
+	// TransmissionCanceled means the Transmission was canceled before it completed.
+	TransmissionCanceled = "canceled"
+)
+
+// WaitOptions controls how WaitForTransmission polls.
+type WaitOptions struct {
+	// Interval between polls. Defaults to 30 seconds.
+	Interval time.Duration
+	// FailureThreshold is the fraction (0-1) of recipients which may fail generation or be
+	// invalid before waiting stops with TransmissionFailed. Zero means there's no threshold.
+	FailureThreshold float64
+	// Injections, if set, keeps waiting after generation completes until an injection
+	// event has been seen for every generated message.
+	Injections bool
+}
+
+// TransmissionStatus is the progress of a Transmission, as reported by WaitForTransmission.
+type TransmissionStatus struct {
+	ID    string
+	State string
+	// Outcome is one of the Transmission* outcomes once waiting has finished, and empty otherwise.
+	Outcome string
+
+	Total            int
+	Generated        int
+	FailedGeneration int
+	Invalid          int
+	// Injected is only counted when WaitOptions.Injections is set.
+	Injected int
+}
+
+func derefInt(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// WaitForTransmission polls a Transmission until it has been fully generated (and optionally
+// injected), was canceled, or too many recipients failed, returning its final status.
+// An error is only returned if polling fails, or ctx is done first.
+func (c *Client) WaitForTransmission(ctx context.Context, id string, opts *WaitOptions) (*TransmissionStatus, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	for {
+		status, err := c.transmissionStatus(id, opts)
+		if err != nil || status.Outcome != "" {
+			return status, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Client) transmissionStatus(id string, opts *WaitOptions) (*TransmissionStatus, error) {
+	tr, _, err := c.Transmission(id)
+	if err != nil {
+		return nil, err
+	}
+	status := &TransmissionStatus{
+		ID:               id,
+		State:            tr.State,
+		Total:            derefInt(tr.TotalRecipients),
+		Generated:        derefInt(tr.NumGenerated),
+		FailedGeneration: derefInt(tr.NumFailedGeneration),
+		Invalid:          derefInt(tr.NumInvalidRecipients),
+	}
+
+	if opts.FailureThreshold > 0 && status.Total > 0 {
+		failed := float64(status.FailedGeneration+status.Invalid) / float64(status.Total)
+		if failed > opts.FailureThreshold {
+			status.Outcome = TransmissionFailed
+			return status, nil
+		}
+	}
+
+	switch strings.ToLower(status.State) {
+	case "canceled":
+		status.Outcome = TransmissionCanceled
+		return status, nil
+	case "success":
+	default:
+		// still submitted or generating
+		return status, nil
+	}
+
+	if opts.Injections {
+		page, err := c.MessageEvents(map[string]string{
+			"events":           "injection",
+			"transmission_ids": id,
+			"per_page":         "1",
+		})
+		if err != nil {
+			return status, err
+		}
+		status.Injected = page.TotalCount
+		if status.Injected < status.Generated {
+			return status, nil
+		}
+	}
+
+	status.Outcome = TransmissionSucceeded
+	return status, nil
+}
@@ -0,0 +1,62 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestWaitForTransmission(t *testing.T) {
+	polls, injected := 0, 0
+	failed := 0
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/transmissions/1":
+			polls++
+			state, generated := "Generating", 5
+			if polls > 1 {
+				state, generated = "Success", 10
+			}
+			fmt.Fprintf(w, `{"results":{"transmission":{"id":"1","state":%q,"total_recipients":10,"num_generated":%d,"num_failed_generation":%d,"num_invalid_recipients":0}}}`,
+				state, generated, failed)
+		case "/api/v1/message-events":
+			if r.URL.Query().Get("transmission_ids") != "1" {
+				t.Errorf("expected events for transmission 1, got %s", r.URL.RawQuery)
+			}
+			injected += 4
+			fmt.Fprintf(w, `{"results":[],"total_count":%d}`, injected)
+		}
+	})
+	defer done()
+
+	opts := &sp.WaitOptions{Interval: time.Millisecond, Injections: true}
+	status, err := client.WaitForTransmission(context.Background(), "1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Outcome != sp.TransmissionSucceeded || status.Generated != 10 || status.Injected != 12 || polls != 4 {
+		t.Errorf("unexpected status after %d polls: %+v", polls, status)
+	}
+
+	polls, failed = 0, 3
+	opts = &sp.WaitOptions{Interval: time.Millisecond, FailureThreshold: 0.2}
+	if status, err = client.WaitForTransmission(context.Background(), "1", opts); err != nil {
+		t.Fatal(err)
+	}
+	if status.Outcome != sp.TransmissionFailed || polls != 1 {
+		t.Errorf("expected failure after one poll, got %+v", status)
+	}
+
+	polls, failed = 0, 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts = &sp.WaitOptions{Interval: time.Hour}
+	if status, err = client.WaitForTransmission(ctx, "1", opts); err != context.Canceled || status.Outcome != "" {
+		t.Errorf("expected cancellation, got %+v, %v", status, err)
+	}
+}
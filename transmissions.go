@@ -1,9 +1,9 @@
 package gosparkpost
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
 	"reflect"
 	"strings"
 	"time"
@@ -31,13 +31,46 @@ type Transmission struct {
 	NumInvalidRecipients *int `json:"num_invalid_recipients,omitempty"`
 }
 
+// RFC3339 is a time in the format SparkPost expects for TxOptions.StartTime.
+// Use Transmission.ScheduleAt to set it from a time.Time.
 type RFC3339 time.Time
 
+// startTimeFormat is RFC 3339 with a numeric offset, as SparkPost doesn't document "Z".
+const startTimeFormat = "2006-01-02T15:04:05-07:00"
+
+// MaxScheduleAhead is how far in the future SparkPost allows a Transmission to be scheduled.
+const MaxScheduleAhead = 32 * 24 * time.Hour
+
 func (r *RFC3339) MarshalJSON() ([]byte, error) {
 	if r == nil {
 		return json.Marshal(nil)
 	}
-	return json.Marshal(time.Time(*r).Format(time.RFC3339))
+	return json.Marshal(time.Time(*r).Format(startTimeFormat))
+}
+
+func (r *RFC3339) UnmarshalJSON(data []byte) error {
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	*r = RFC3339(t)
+	return nil
+}
+
+// ScheduleAt delays generation of the Transmission's messages until at.
+// A zero time clears any schedule, so the Transmission is sent straight away.
+func (t *Transmission) ScheduleAt(at time.Time) {
+	if at.IsZero() {
+		if t.Options != nil {
+			t.Options.StartTime = nil
+		}
+		return
+	}
+	if t.Options == nil {
+		t.Options = &TxOptions{}
+	}
+	start := RFC3339(at)
+	t.Options.StartTime = &start
 }
 
 // Options specifies settings to apply to this Transmission.
@@ -49,6 +82,43 @@ type TxOptions struct {
 	Sandbox         string   `json:"sandbox,omitempty"`
 	SkipSuppression string   `json:"skip_suppression,omitempty"`
 	InlineCSS       bool     `json:"inline_css,omitempty"`
+	// PerformSubstitutions defaults to true. RawContent turns it off.
+	PerformSubstitutions *bool `json:"perform_substitutions,omitempty"`
+
+	// NumRcptErrors, if set, asks for up to this many of the errors for rejected recipients
+	// to be returned, see Response.SendResults. It's sent as a query parameter, not in the body.
+	NumRcptErrors int `json:"-"`
+}
+
+// numRcptErrors returns the value of the num_rcpt_errors query parameter for t, if any.
+func (t *Transmission) numRcptErrors() int {
+	if t == nil || t.Options == nil {
+		return 0
+	}
+	return t.Options.NumRcptErrors
+}
+
+// SendResults is the outcome of sending a Transmission, as returned by Response.SendResults.
+type SendResults struct {
+	ID                      string `json:"id"`
+	TotalAcceptedRecipients int    `json:"total_accepted_recipients"`
+	TotalRejectedRecipients int    `json:"total_rejected_recipients"`
+	// RecipientErrors holds a sample of the reasons recipients were rejected, of at most
+	// TxOptions.NumRcptErrors. It's empty unless that was set.
+	RecipientErrors []Error `json:"rcpt_to_errors"`
+}
+
+// SendResults decodes the results of a successful Send.
+func (r *Response) SendResults() (*SendResults, error) {
+	var wrapper struct {
+		Results *SendResults `json:"results"`
+	}
+	if err := json.Unmarshal(r.Body, &wrapper); err != nil {
+		return nil, err
+	} else if wrapper.Results == nil {
+		return nil, fmt.Errorf("Response has no results")
+	}
+	return wrapper.Results, nil
 }
 
 // ParseRecipients asserts that Transmission.Recipients is valid.
@@ -147,6 +217,10 @@ func ParseContent(content interface{}) (err error) {
 		te := &Template{Name: "tmp", Content: rVal}
 		return te.Validate()
 
+	case RawContent:
+		te := &Template{Name: "tmp", Content: Content(rVal)}
+		return te.Validate()
+
 	default:
 		return fmt.Errorf("Unsupported Transmission.Content type [%s]", reflect.TypeOf(rVal))
 	}
@@ -169,10 +243,18 @@ func (t *Transmission) Validate() error {
 	}
 
 	// enforce max lengths
-	if len(t.CampaignID) > 64 {
+	if len(t.CampaignID) > MaxCampaignIDLength {
 		return fmt.Errorf("Campaign id may not be longer than 64 bytes")
 	} else if len(t.Description) > 1024 {
 		return fmt.Errorf("Transmission description may not be longer than 1024 bytes")
+	} else if t.numRcptErrors() < 0 {
+		return fmt.Errorf("NumRcptErrors may not be negative")
+	}
+
+	if t.Options != nil && t.Options.StartTime != nil {
+		if latest := time.Now().Add(MaxScheduleAhead); time.Time(*t.Options.StartTime).After(latest) {
+			return fmt.Errorf("StartTime may be at most %d days in the future", MaxScheduleAhead/(24*time.Hour))
+		}
 	}
 
 	// validate members from other packages
@@ -190,6 +272,9 @@ func (t *Transmission) Validate() error {
 		return err
 	}
 
+	if raw, ok := t.Content.(RawContent); ok {
+		return t.validateRaw(raw)
+	}
 	return nil
 }
 
@@ -201,19 +286,58 @@ func (c *Client) Send(t *Transmission) (id string, res *Response, err error) {
 		err = fmt.Errorf("Create called with nil Transmission")
 		return
 	}
+	return c.sendTransmission(t, false)
+}
 
+// sendTransmission validates t, runs it through the send pipeline and posts it.
+func (c *Client) sendTransmission(t *Transmission, proof bool) (id string, res *Response, err error) {
 	err = t.Validate()
 	if err != nil {
 		return
 	}
 
+	if c.Config.EscapeSubstitutionData {
+		// Recipients are escaped by the pipeline, after any have been added
+		tx := *t
+		if tx.SubstitutionData, err = EscapeSubstitutionData(t.SubstitutionData); err != nil {
+			return
+		}
+		t = &tx
+	}
+
+	o, err := c.sendPipeline(t, proof)
+	if err != nil {
+		return
+	}
+	t = o.t
+
 	jsonBytes, err := json.Marshal(t)
 	if err != nil {
+		err = o.finish("", err)
+		return
+	}
+
+	messages := 1
+	if recips, _ := inlineRecipients(t.Recipients); len(recips) > 0 {
+		messages = len(recips)
+	}
+	id, res, err = c.postTransmission(jsonBytes, t.numRcptErrors(), messages)
+	err = o.finish(id, err)
+	return
+}
+
+// postTransmission sends an encoded Transmission of the provided number of messages,
+// returning the new Transmission's id.
+func (c *Client) postTransmission(jsonBytes []byte, numRcptErrors, messages int) (id string, res *Response, err error) {
+	if err = checkSize("Transmission", len(jsonBytes), MaxTransmissionBytes); err != nil {
+		return
+	}
+	if err = c.Limiter.Wait(context.Background(), messages); err != nil {
 		return
 	}
 
 	path := fmt.Sprintf(transmissionsPathFormat, c.Config.ApiVersion)
-	u := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	u := QueryBuilder{}.Int("num_rcpt_errors", numRcptErrors).URL(c.Config.BaseUrl + path)
 	res, err = c.HttpPost(u, jsonBytes)
 	if err != nil {
 		return
@@ -229,11 +353,11 @@ func (c *Client) Send(t *Transmission) (id string, res *Response, err error) {
 	}
 
 	if res.HTTP.StatusCode == 200 {
-		var ok bool
-		id, ok = res.Results["id"].(string)
-		if !ok {
+		id, _ = res.Results["id"].(string)
+		if id == "" {
 			err = fmt.Errorf("Unexpected response to Transmission creation")
 		}
+		return
 
 	} else if len(res.Errors) > 0 {
 		// handle common errors
@@ -241,10 +365,9 @@ func (c *Client) Send(t *Transmission) (id string, res *Response, err error) {
 		if err != nil {
 			return
 		}
-
-		err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 	}
 
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 	return
 }
 
@@ -301,8 +424,27 @@ func (c *Client) Transmission(id string) (*Transmission, *Response, error) {
 	return nil, res, err
 }
 
+// InTransitError is returned when a Transmission can't be deleted because it's already being
+// generated (a 409 response). Nothing was deleted, so the call is safe to retry, though it only
+// succeeds if the Transmission is still scheduled by then.
+type InTransitError struct {
+	// ID is the id of the Transmission, or CampaignID the campaign, which was in transit.
+	ID         string
+	CampaignID string
+	Err        Error
+}
+
+func (e *InTransitError) Error() string {
+	what := "Transmission [" + e.ID + "]"
+	if e.ID == "" {
+		what = "Campaign [" + e.CampaignID + "]"
+	}
+	return fmt.Sprintf("%s is in transit: %s", what, e.Err.Message)
+}
+
 // Delete attempts to remove the Transmission with the specified id.
-// Only Transmissions which are scheduled for future generation may be deleted.
+// Only Transmissions which are scheduled for future generation may be deleted;
+// an *InTransitError is returned if generation has started.
 func (c *Client) TransmissionDelete(id string) (*Response, error) {
 	if id == "" {
 		return nil, fmt.Errorf("Delete called with blank id")
@@ -313,11 +455,33 @@ func (c *Client) TransmissionDelete(id string) (*Response, error) {
 
 	path := fmt.Sprintf(transmissionsPathFormat, c.Config.ApiVersion)
 	u := fmt.Sprintf("%s%s/%s", c.Config.BaseUrl, path, id)
+	return c.transmissionDelete(u, &InTransitError{ID: id})
+}
+
+// TransmissionDeleteCampaign cancels every scheduled Transmission with the specified campaign id.
+// An *InTransitError is returned if any of them has started generation.
+func (c *Client) TransmissionDeleteCampaign(campaignID string) (*Response, error) {
+	if campaignID == "" {
+		return nil, fmt.Errorf("Delete called with blank campaign id")
+	}
+
+	path := fmt.Sprintf(transmissionsPathFormat, c.Config.ApiVersion)
+	u := QueryBuilder{}.Set("campaign_id", campaignID).URL(c.Config.BaseUrl + path)
+	return c.transmissionDelete(u, &InTransitError{CampaignID: campaignID})
+}
+
+// transmissionDelete makes a delete request, returning inTransit for a 409.
+func (c *Client) transmissionDelete(u string, inTransit *InTransitError) (*Response, error) {
 	res, err := c.HttpDelete(u)
 	if err != nil {
 		return nil, err
 	}
 
+	if res.HTTP.StatusCode == 204 {
+		_, err = res.ReadBody()
+		return res, err
+	}
+
 	if err = res.AssertJson(); err != nil {
 		return res, err
 	}
@@ -336,6 +500,10 @@ func (c *Client) TransmissionDelete(id string) (*Response, error) {
 			return res, err
 		}
 
+		if res.HTTP.StatusCode == 409 {
+			inTransit.Err = res.Errors[0]
+			return res, inTransit
+		}
 		return res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 	}
 
@@ -345,57 +513,98 @@ func (c *Client) TransmissionDelete(id string) (*Response, error) {
 // List returns Transmission summary information for matching Transmissions.
 // To skip filtering by campaign or template id, use a nil param.
 func (c *Client) Transmissions(campaignID, templateID *string) ([]Transmission, *Response, error) {
-	// If a query parameter is present and empty, that searches for blank IDs, as opposed
-	// to when it is omitted entirely, which returns everything.
-	qp := make([]string, 0, 2)
-	if campaignID != nil {
-		qp = append(qp, fmt.Sprintf("campaign_id=%s", url.QueryEscape(*campaignID)))
+	var list []Transmission
+	res, err := c.transmissionList(&TransmissionListOptions{CampaignID: campaignID, TemplateID: templateID}, &list)
+	return list, res, err
+}
+
+// TransmissionListOptions filters TransmissionList. A nil field doesn't filter; a pointer
+// to an empty string matches Transmissions where that field is blank.
+type TransmissionListOptions struct {
+	CampaignID *string
+	TemplateID *string
+}
+
+// TransmissionSummary is a Transmission as described by TransmissionList.
+type TransmissionSummary struct {
+	ID          string `json:"id"`
+	State       string `json:"state"`
+	CampaignID  string `json:"campaign_id"`
+	Description string `json:"description"`
+	// TemplateID is set if the Transmission uses a stored template.
+	TemplateID string `json:"-"`
+}
+
+func (t *TransmissionSummary) UnmarshalJSON(data []byte) error {
+	type summary TransmissionSummary
+	var wrapper struct {
+		*summary
+		Content struct {
+			TemplateID string `json:"template_id"`
+		} `json:"content"`
 	}
-	if templateID != nil {
-		qp = append(qp, fmt.Sprintf("template_id=%s", url.QueryEscape(*templateID)))
+	wrapper.summary = (*summary)(t)
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
 	}
+	t.TemplateID = wrapper.Content.TemplateID
+	return nil
+}
+
+// TransmissionList returns a summary of each Transmission matching opts, which may be nil,
+// so queued and scheduled sends can be inspected.
+func (c *Client) TransmissionList(opts *TransmissionListOptions) ([]TransmissionSummary, *Response, error) {
+	var list []TransmissionSummary
+	res, err := c.transmissionList(opts, &list)
+	return list, res, err
+}
 
-	qstr := ""
-	if len(qp) > 0 {
-		qstr = strings.Join(qp, "&")
+// transmissionList decodes the matching Transmissions into list.
+func (c *Client) transmissionList(opts *TransmissionListOptions, list interface{}) (*Response, error) {
+	if opts == nil {
+		opts = &TransmissionListOptions{}
 	}
+	// If a query parameter is present and empty, that searches for blank IDs, as opposed
+	// to when it is omitted entirely, which returns everything.
 	path := fmt.Sprintf(transmissionsPathFormat, c.Config.ApiVersion)
-	u := fmt.Sprintf("%s%s?%s", c.Config.BaseUrl, path, qstr)
+	u := QueryBuilder{}.
+		SetPtr("campaign_id", opts.CampaignID).
+		SetPtr("template_id", opts.TemplateID).
+		URL(c.Config.BaseUrl + path)
 
 	res, err := c.HttpGet(u)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	if err = res.AssertJson(); err != nil {
-		return nil, res, err
+		return res, err
 	}
 
 	if res.HTTP.StatusCode == 200 {
 		var body []byte
 		body, err = res.ReadBody()
 		if err != nil {
-			return nil, res, err
+			return res, err
 		}
-		tlist := map[string][]Transmission{}
+		tlist := map[string]json.RawMessage{}
 		if err = json.Unmarshal(body, &tlist); err != nil {
-			return nil, res, err
-		} else if list, ok := tlist["results"]; ok {
-			return list, res, nil
+			return res, err
+		} else if results, ok := tlist["results"]; ok {
+			return res, json.Unmarshal(results, list)
 		}
-		return nil, res, fmt.Errorf("Unexpected response to Transmission list")
+		return res, fmt.Errorf("Unexpected response to Transmission list")
+	}
 
-	} else {
-		err = res.ParseResponse()
+	err = res.ParseResponse()
+	if err != nil {
+		return res, err
+	}
+	if len(res.Errors) > 0 {
+		err = res.PrettyError("Transmission", "list")
 		if err != nil {
-			return nil, res, err
-		}
-		if len(res.Errors) > 0 {
-			err = res.PrettyError("Transmission", "list")
-			if err != nil {
-				return nil, res, err
-			}
+			return res, err
 		}
-		return nil, res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 	}
+	return res, fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
 }
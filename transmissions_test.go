@@ -1,7 +1,12 @@
 package gosparkpost_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	sp "github.com/SparkPost/gosparkpost"
 	"github.com/SparkPost/gosparkpost/test"
@@ -108,3 +113,169 @@ func TestTransmissions(t *testing.T) {
 	t.Errorf("Delete returned HTTP %s\n%s\n", res.HTTP.Status, res.Body)
 
 }
+
+func TestSendRecipientErrors(t *testing.T) {
+	var query string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":{"id":"11668787484950529","total_accepted_recipients":1,"total_rejected_recipients":2,
+			"rcpt_to_errors":[{"message":"Invalid recipient address","code":"1100","description":"Recipient address 'bad' is malformed"}]}}`))
+	})
+	defer done()
+
+	tx := &sp.Transmission{
+		Recipients: []string{"good@example.com", "bad", "worse"},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hi"},
+		Options:    &sp.TxOptions{NumRcptErrors: 1},
+	}
+	id, res, err := client.Send(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "num_rcpt_errors=1" {
+		t.Errorf("unexpected query %q", query)
+	}
+
+	results, err := res.SendResults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.ID != id || results.TotalAcceptedRecipients != 1 || results.TotalRejectedRecipients != 2 {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if len(results.RecipientErrors) != 1 || results.RecipientErrors[0].Code != "1100" {
+		t.Errorf("unexpected recipient errors %+v", results.RecipientErrors)
+	}
+
+	tx.Options.NumRcptErrors = 0
+	if _, _, err = client.Send(tx); err != nil {
+		t.Fatal(err)
+	}
+	if query != "" {
+		t.Errorf("unexpected query %q", query)
+	}
+
+	tx.Options.NumRcptErrors = -1
+	if _, _, err = client.Send(tx); err == nil {
+		t.Error("expected an error for a negative NumRcptErrors")
+	}
+}
+
+func TestTransmissionList(t *testing.T) {
+	var query string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[
+			{"id":"11","state":"submitted","campaign_id":"spring","description":"","content":{"template_id":"welcome"}},
+			{"id":"12","state":"Generating","campaign_id":"spring","description":"Batch 2","content":{}}
+		]}`))
+	})
+	defer done()
+
+	blank := ""
+	list, _, err := client.TransmissionList(&sp.TransmissionListOptions{CampaignID: &blank})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "campaign_id=" {
+		t.Errorf("expected a blank campaign filter, got %q", query)
+	}
+	expected := []sp.TransmissionSummary{
+		{ID: "11", State: "submitted", CampaignID: "spring", TemplateID: "welcome"},
+		{ID: "12", State: "Generating", CampaignID: "spring", Description: "Batch 2"},
+	}
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("expected %+v, got %+v", expected, list)
+	}
+
+	if _, _, err = client.TransmissionList(nil); err != nil {
+		t.Fatal(err)
+	}
+	if query != "" {
+		t.Errorf("expected no filters, got %q", query)
+	}
+}
+
+func TestTransmissionDelete(t *testing.T) {
+	var calls []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.RequestURI()
+		calls = append(calls, call)
+		switch call {
+		case "DELETE /api/v1/transmissions/11":
+			w.WriteHeader(204)
+		case "DELETE /api/v1/transmissions/12", "DELETE /api/v1/transmissions?campaign_id=spring":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(409)
+			w.Write([]byte(`{"errors":[{"message":"Transmission is in transit","code":"2003"}]}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	if _, err := client.TransmissionDelete("11"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.TransmissionDelete("12")
+	inTransit, ok := err.(*sp.InTransitError)
+	if !ok || inTransit.ID != "12" || inTransit.Err.Code != "2003" {
+		t.Fatalf("expected an *InTransitError, got %#v", err)
+	}
+
+	_, err = client.TransmissionDeleteCampaign("spring")
+	if inTransit, ok = err.(*sp.InTransitError); !ok || inTransit.CampaignID != "spring" {
+		t.Fatalf("expected an *InTransitError, got %#v", err)
+	}
+	if err.Error() != "Campaign [spring] is in transit: Transmission is in transit" {
+		t.Errorf("unexpected message %q", err)
+	}
+
+	if _, err = client.TransmissionDeleteCampaign("autumn"); err != nil {
+		t.Fatal(err)
+	}
+	if calls[len(calls)-1] != "DELETE /api/v1/transmissions?campaign_id=autumn" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
+
+func TestScheduleAt(t *testing.T) {
+	tx := &sp.Transmission{
+		Recipients: []string{"a@example.com"},
+		Content:    sp.Content{From: "test@example.com", Subject: "Hi", Text: "Hello"},
+	}
+	at := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+	tx.ScheduleAt(at)
+	if err := tx.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	jsonBytes, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `"start_time":"` + at.Format("2006-01-02T15:04:05") + `+00:00"`
+	if !strings.Contains(string(jsonBytes), expected) {
+		t.Errorf("expected %s in %s", expected, jsonBytes)
+	}
+
+	var decoded sp.Transmission
+	if err = json.Unmarshal(jsonBytes, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(*decoded.Options.StartTime).Equal(at) {
+		t.Errorf("expected %s, got %s", at, time.Time(*decoded.Options.StartTime))
+	}
+
+	tx.ScheduleAt(time.Now().Add(40 * 24 * time.Hour))
+	if err = tx.Validate(); err == nil {
+		t.Error("expected an error scheduling beyond MaxScheduleAhead")
+	}
+	tx.ScheduleAt(time.Time{})
+	if tx.Options.StartTime != nil {
+		t.Error("expected the schedule to be cleared")
+	}
+}
@@ -0,0 +1,196 @@
+package gosparkpost
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+// UnsubscribeFunc is called once for each unsubscribe.
+type UnsubscribeFunc func(email, campaignID string, at time.Time) error
+
+// UnsubscribeSync passes list_unsubscribe and link_unsubscribe events to an application callback.
+// Events may be pushed to it by a SparkPost webhook (it's an http.Handler), or it can poll
+// for them once started. Either way, each unsubscribe is delivered once, retrying on errors.
+type UnsubscribeSync struct {
+	Client        *Client
+	OnUnsubscribe UnsubscribeFunc
+	// Interval between polls. Defaults to one minute.
+	Interval time.Duration
+	// Attempts is how many times OnUnsubscribe is tried per event. Defaults to 3.
+	Attempts int
+	// Dedupe is how long delivered events are remembered. Defaults to 24 hours.
+	Dedupe time.Duration
+
+	runner
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+var _ Component = &UnsubscribeSync{}
+
+// Start polls the Message Events API in the background until Stop is called.
+func (u *UnsubscribeSync) Start(ctx context.Context) error {
+	interval := u.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return u.start(ctx, func(ctx context.Context) {
+		since := time.Now().Add(-interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := u.poll(ctx, since); err != nil {
+					u.fail(err)
+					// leave since alone, so the next poll tries again
+					continue
+				}
+				since = now
+			}
+		}
+	})
+}
+
+// Stop stops polling, waiting for an in-progress poll to complete.
+func (u *UnsubscribeSync) Stop(ctx context.Context) error {
+	return u.stop(ctx)
+}
+
+// Health reports whether polling is running, and the last error encountered.
+func (u *UnsubscribeSync) Health() Health {
+	return u.report()
+}
+
+func (u *UnsubscribeSync) poll(ctx context.Context, since time.Time) error {
+	page, err := u.Client.MessageEvents(map[string]string{
+		"events": "list_unsubscribe,link_unsubscribe",
+		// the API accepts minute precision; duplicates caused by the overlap are skipped
+		"from": since.UTC().Format(QueryTimeFormat),
+	})
+	for err == nil {
+		if err = u.HandleContext(ctx, page.Events); err != nil {
+			return err
+		}
+		page, err = page.Next()
+	}
+	if err == ErrEmptyPage {
+		return nil
+	}
+	return err
+}
+
+// Handle delivers any unsubscribes among the provided events, returning the last error
+// from OnUnsubscribe. Failed events aren't remembered, so they're retried if seen again.
+func (u *UnsubscribeSync) Handle(evs events.Events) error {
+	return u.HandleContext(context.Background(), evs)
+}
+
+// HandleContext is Handle, giving up on retries when ctx is done.
+func (u *UnsubscribeSync) HandleContext(ctx context.Context, evs events.Events) (err error) {
+	for _, ev := range evs {
+		var email, campaign string
+		var ts events.Timestamp
+		switch e := ev.(type) {
+		case *events.ListUnsubscribe:
+			email, campaign, ts = e.Recipient, e.CampaignID, e.Timestamp
+		case *events.LinkUnsubscribe:
+			email, campaign, ts = e.Recipient, e.CampaignID, e.Timestamp
+		default:
+			continue
+		}
+		at := time.Time(ts)
+
+		key := email + "\x00" + campaign + "\x00" + at.String()
+		if u.delivered(key) {
+			continue
+		}
+		if derr := u.deliver(ctx, email, campaign, at); derr != nil {
+			err = derr
+			continue
+		}
+		u.remember(key)
+	}
+	return err
+}
+
+// ServeHTTP accepts SparkPost webhook batches. A 500 is returned if any unsubscribe
+// couldn't be delivered, so SparkPost sends the batch again later.
+func (u *UnsubscribeSync) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var evs events.Events
+	if err = json.Unmarshal(body, &evs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = u.HandleContext(r.Context(), evs); err != nil {
+		u.fail(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (u *UnsubscribeSync) deliver(ctx context.Context, email, campaign string, at time.Time) (err error) {
+	attempts := u.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			timer := time.NewTimer(time.Duration(i) * time.Second)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err = u.OnUnsubscribe(email, campaign, at); err == nil {
+			return
+		}
+	}
+	return
+}
+
+func (u *UnsubscribeSync) delivered(key string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	_, ok := u.seen[key]
+	return ok
+}
+
+func (u *UnsubscribeSync) remember(key string) {
+	window := u.Dedupe
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.seen == nil {
+		u.seen = map[string]time.Time{}
+	}
+	now := time.Now()
+	for k, at := range u.seen {
+		if now.Sub(at) > window {
+			delete(u.seen, k)
+		}
+	}
+	u.seen[key] = now
+}
@@ -0,0 +1,64 @@
+package gosparkpost_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+	"github.com/SparkPost/gosparkpost/events"
+)
+
+func TestUnsubscribeSyncWebhook(t *testing.T) {
+	var got []string
+	unsub := &sp.UnsubscribeSync{
+		OnUnsubscribe: func(email, campaign string, at time.Time) error {
+			got = append(got, email+" "+campaign)
+			return nil
+		},
+	}
+
+	payload := `[
+		{"msys":{"unsubscribe_event":{"type":"list_unsubscribe","rcpt_to":"a@example.com","campaign_id":"news","timestamp":"1460989507"}}},
+		{"msys":{"unsubscribe_event":{"type":"link_unsubscribe","rcpt_to":"b@example.com","campaign_id":"news","timestamp":"1460989508"}}},
+		{"msys":{"track_event":{"type":"open","rcpt_to":"c@example.com","timestamp":"1460989509"}}}
+	]`
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		unsub.ServeHTTP(w, httptest.NewRequest("POST", "/webhook", strings.NewReader(payload)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("pass %d: expected 200, got %d", i, w.Code)
+		}
+	}
+
+	if strings.Join(got, ",") != "a@example.com news,b@example.com news" {
+		t.Errorf("expected each unsubscribe exactly once, got %v", got)
+	}
+}
+
+func TestUnsubscribeSyncCancel(t *testing.T) {
+	attempts := 0
+	unsub := &sp.UnsubscribeSync{
+		Attempts: 5,
+		OnUnsubscribe: func(email, campaign string, at time.Time) error {
+			attempts++
+			return fmt.Errorf("unavailable")
+		},
+	}
+	evs := events.Events{&events.ListUnsubscribe{Recipient: "a@example.com"}}
+
+	// retries stop waiting as soon as the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := unsub.HandleContext(ctx, evs); err != context.DeadlineExceeded {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond || attempts != 1 {
+		t.Errorf("expected one attempt, returning promptly, got %d after %s", attempts, elapsed)
+	}
+}
@@ -0,0 +1,79 @@
+package gosparkpost
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrConflict is returned when an object was changed remotely since the caller last saw it.
+var ErrConflict = errors.New("remote object was modified concurrently")
+
+// UpsertOptions controls how TemplateUpsert behaves when the Template already exists.
+type UpsertOptions struct {
+	// ExpectedLastUpdate, when non-zero, makes the update fail with ErrConflict
+	// unless the existing Template's last_update_time matches it.
+	ExpectedLastUpdate time.Time
+}
+
+// TemplateUpsert updates the existing Template with the same ID (or, if ID is blank,
+// the same Name) as the provided one, and creates it if there's no such Template.
+// This makes deploy scripts idempotent. The ID of the created or updated Template is returned.
+func (c *Client) TemplateUpsert(t *Template, opts *UpsertOptions) (id string, res *Response, err error) {
+	if t == nil {
+		err = fmt.Errorf("Upsert called with nil Template")
+		return
+	}
+
+	list, res, err := c.Templates()
+	if err != nil {
+		return
+	}
+
+	var found *Template
+	for idx := range list {
+		if (t.ID != "" && list[idx].ID == t.ID) || (t.ID == "" && list[idx].Name == t.Name) {
+			found = &list[idx]
+			break
+		}
+	}
+
+	if found == nil {
+		return c.TemplateCreate(t)
+	}
+
+	if opts != nil && !opts.ExpectedLastUpdate.IsZero() &&
+		!opts.ExpectedLastUpdate.Equal(found.LastUpdate) {
+		err = ErrConflict
+		return
+	}
+
+	t.ID = found.ID
+	res, err = c.TemplateUpdate(t)
+	return t.ID, res, err
+}
+
+// WebhookUpsert updates the existing Webhook with the same ID (or, if ID is blank,
+// the same Name) as the provided one, and creates it if there's no such Webhook.
+// The ID of the created or updated Webhook is returned.
+func (c *Client) WebhookUpsert(w *WebhookItem) (id string, res *Response, err error) {
+	if w == nil {
+		err = fmt.Errorf("Upsert called with nil Webhook")
+		return
+	}
+
+	list, err := c.ListWebhooks(nil)
+	if err != nil {
+		return
+	}
+
+	for _, hook := range list.Results {
+		if (w.ID != "" && hook.ID == w.ID) || (w.ID == "" && hook.Name == w.Name) {
+			w.ID = hook.ID
+			res, err = c.WebhookUpdate(w)
+			return w.ID, res, err
+		}
+	}
+
+	return c.WebhookCreate(w)
+}
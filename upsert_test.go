@@ -0,0 +1,117 @@
+package gosparkpost_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestTemplateUpsert(t *testing.T) {
+	var methods []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"results":[{"id":"welcome","name":"Welcome","last_update_time":"2017-01-02T03:04:05+00:00"}]}`))
+		case "POST":
+			w.Write([]byte(`{"results":{"id":"created"}}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	newTemplate := func(name string) *sp.Template {
+		return &sp.Template{Name: name, Content: sp.Content{
+			Subject: "hi", Text: "hi", From: "test@example.com",
+		}}
+	}
+
+	id, _, err := client.TemplateUpsert(newTemplate("Welcome"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "welcome" || methods[len(methods)-1] != "PUT /api/v1/templates/welcome" {
+		t.Errorf("expected update of existing template, got %s via %v", id, methods)
+	}
+
+	id, _, err = client.TemplateUpsert(newTemplate("Goodbye"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "created" || methods[len(methods)-1] != "POST /api/v1/templates" {
+		t.Errorf("expected new template, got %s via %v", id, methods)
+	}
+
+	stale := &sp.UpsertOptions{ExpectedLastUpdate: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if _, _, err = client.TemplateUpsert(newTemplate("Welcome"), stale); err != sp.ErrConflict {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestTemplateUpdateIfUnchanged(t *testing.T) {
+	updated := false
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "PUT" {
+			updated = true
+			w.Write([]byte(`{"results":{}}`))
+			return
+		}
+		w.Write([]byte(`{"results":{"id":"welcome","last_update_time":"2017-01-02T03:04:05+00:00",
+			"content":{"subject":"hi","text":"hi","from":{"email":"test@example.com"}}}}`))
+	})
+	defer done()
+
+	tmpl, _, err := client.Template("welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.LastUpdate.IsZero() || tmpl.Content.Subject != "hi" {
+		t.Fatalf("unexpected template %+v", tmpl)
+	}
+
+	if _, err = client.TemplateUpdateIfUnchanged(tmpl); err != nil || !updated {
+		t.Fatalf("expected update to go through, got updated=%t err=%v", updated, err)
+	}
+
+	updated = false
+	tmpl.LastUpdate = tmpl.LastUpdate.Add(-time.Hour)
+	if _, err = client.TemplateUpdateIfUnchanged(tmpl); err != sp.ErrConflict || updated {
+		t.Fatalf("expected ErrConflict and no update, got updated=%t err=%v", updated, err)
+	}
+}
+
+func TestWebhookUpsert(t *testing.T) {
+	var requests []string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"results":[{"id":"1","name":"bounces","target":"https://example.com/old"}]}`))
+		case "POST":
+			w.Write([]byte(`{"results":{"id":"2"}}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	// matched by name, so updated
+	id, _, err := client.WebhookUpsert(&sp.WebhookItem{Name: "bounces", Target: "https://example.com/new"})
+	if err != nil || id != "1" {
+		t.Fatalf("unexpected id %q, %v", id, err)
+	}
+	// no match, so created
+	if id, _, err = client.WebhookUpsert(&sp.WebhookItem{Name: "opens", Target: "https://example.com/opens"}); err != nil || id != "2" {
+		t.Fatalf("unexpected id %q, %v", id, err)
+	}
+	if strings.Join(requests, ",") != "GET /api/v1/webhooks,PUT /api/v1/webhooks/1,GET /api/v1/webhooks,POST /api/v1/webhooks" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
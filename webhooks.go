@@ -3,8 +3,7 @@ package gosparkpost
 import (
 	"encoding/json"
 	"fmt"
-
-	URL "net/url"
+	"strings"
 )
 
 // https://www.sparkpost.com/api#/reference/message-events
@@ -70,20 +69,29 @@ type WebhookStatusWrapper struct {
 	//{"errors":[{"param":"from","message":"From must be before to","value":"2014-07-20T09:00"},{"param":"to","message":"To must be in the format YYYY-MM-DDTHH:mm","value":"now"}]}
 }
 
-func buildUrl(c *Client, url string, parameters map[string]string) string {
-
-	if parameters == nil || len(parameters) == 0 {
-		url = fmt.Sprintf("%s%s", c.Config.BaseUrl, url)
-	} else {
-		params := URL.Values{}
-		for k, v := range parameters {
-			params.Add(k, v)
-		}
+func (w *WebhookItem) String() string {
+	if w == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s %q -> %s [%s]", w.ID, w.Name, w.Target, strings.Join(w.Events, ","))
+}
 
-		url = fmt.Sprintf("%s%s?%s", c.Config.BaseUrl, url, params.Encode())
+// Equal reports whether two webhooks have the same ID and settings, ignoring the
+// order of Events. Delivery status fields and Links are ignored.
+func (w *WebhookItem) Equal(o *WebhookItem) bool {
+	if w == nil || o == nil {
+		return w == o
+	}
+	a, b := w.writable(), o.writable()
+	if w.ID != o.ID || !sameStrings(a.Events, b.Events) {
+		return false
 	}
+	a.Events, b.Events = nil, nil
+	return jsonEqual(a, b)
+}
 
-	return url
+func buildUrl(c *Client, url string, parameters map[string]string) string {
+	return QueryBuilder{}.Params(parameters).URL(c.Config.BaseUrl + url)
 }
 
 // https://developers.sparkpost.com/api/#/reference/webhooks/batch-status/retrieve-status-information
@@ -185,3 +193,153 @@ func doRequest(c *Client, finalUrl string) ([]byte, error) {
 
 	return bodyBytes, err
 }
+
+// writable returns a copy of the WebhookItem without the fields the API
+// sets itself, which it doesn't accept on create or update.
+func (w *WebhookItem) writable() WebhookItem {
+	tmp := *w
+	tmp.ID = ""
+	tmp.LastSuccessful = ""
+	tmp.LastFailure = ""
+	tmp.Links = nil
+	return tmp
+}
+
+// WebhookCreate accepts a populated WebhookItem and creates it using the configured endpoint.
+// https://developers.sparkpost.com/api/#/reference/webhooks/create-and-list/create-a-webhook
+func (c *Client) WebhookCreate(w *WebhookItem) (id string, res *Response, err error) {
+	if w == nil {
+		err = fmt.Errorf("Create called with nil Webhook")
+		return
+	} else if w.Name == "" || w.Target == "" {
+		err = fmt.Errorf("Webhook requires a non-empty Name and Target")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(w.writable())
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(webhookListPathFormat, c.Config.ApiVersion)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPost(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		var ok bool
+		id, ok = res.Results["id"].(string)
+		if !ok {
+			err = fmt.Errorf("Unexpected response to Webhook creation")
+		}
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("Webhook", "create")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	return
+}
+
+// WebhookUpdate replaces the settings of the Webhook with the ID of the provided WebhookItem.
+// https://developers.sparkpost.com/api/#/reference/webhooks/retrieve-update-and-delete/update-a-webhook
+func (c *Client) WebhookUpdate(w *WebhookItem) (res *Response, err error) {
+	if w == nil {
+		err = fmt.Errorf("Update called with nil Webhook")
+		return
+	} else if w.ID == "" {
+		err = fmt.Errorf("Update called with blank id")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(w.writable())
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf(webhookQueryPathFormat, c.Config.ApiVersion, w.ID)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpPut(url, jsonBytes)
+	if err != nil {
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if res.HTTP.StatusCode == 200 {
+		return
+
+	} else if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("Webhook", "update")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+	return
+}
+
+// WebhookDelete removes the Webhook with the provided ID.
+// https://developers.sparkpost.com/api/#/reference/webhooks/retrieve-update-and-delete/delete-a-webhook
+func (c *Client) WebhookDelete(id string) (res *Response, err error) {
+	if id == "" {
+		err = fmt.Errorf("Delete called with blank id")
+		return
+	}
+
+	path := fmt.Sprintf(webhookQueryPathFormat, c.Config.ApiVersion, id)
+	url := fmt.Sprintf("%s%s", c.Config.BaseUrl, path)
+	res, err = c.HttpDelete(url)
+	if err != nil {
+		return
+	}
+
+	// success is a 204, with no body
+	if res.HTTP.StatusCode == 204 || res.HTTP.StatusCode == 200 {
+		_, err = res.ReadBody()
+		return
+	}
+
+	if err = res.AssertJson(); err != nil {
+		return
+	}
+
+	err = res.ParseResponse()
+	if err != nil {
+		return
+	}
+
+	if len(res.Errors) > 0 {
+		// handle common errors
+		err = res.PrettyError("Webhook", "delete")
+		if err != nil {
+			return
+		}
+	}
+	err = fmt.Errorf("%d: %s", res.HTTP.StatusCode, string(res.Body))
+
+	return
+}
@@ -0,0 +1,87 @@
+package gosparkpost_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	sp "github.com/SparkPost/gosparkpost"
+)
+
+func TestWebhookDelete(t *testing.T) {
+	var deleted string
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":[{"message":"resource not found"}]}`))
+			return
+		}
+		deleted = r.URL.Path
+		w.WriteHeader(204)
+	})
+	defer done()
+
+	if _, err := client.WebhookDelete(""); err == nil {
+		t.Error("expected an error for a blank id")
+	}
+
+	if _, err := client.WebhookDelete("12affc24-f183-11e3-9234-3c15c2c818c2"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != "/api/v1/webhooks/12affc24-f183-11e3-9234-3c15c2c818c2" {
+		t.Errorf("unexpected path %q", deleted)
+	}
+
+	if _, err := client.WebhookDelete("missing"); err == nil {
+		t.Error("expected an error for an unknown webhook")
+	}
+}
+
+func TestWebhookCreateUpdate(t *testing.T) {
+	var bodies []map[string]interface{}
+	client, done := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/broken"):
+			w.WriteHeader(500)
+			w.Write([]byte(`{}`))
+		case r.Method == "POST":
+			w.Write([]byte(`{"results":{"id":"12affc24"}}`))
+		default:
+			w.Write([]byte(`{"results":{}}`))
+		}
+	})
+	defer done()
+
+	hook := &sp.WebhookItem{ID: "ignored", Name: "bounces", Target: "https://example.com/hook", Events: []string{"bounce"}}
+	if _, _, err := client.WebhookCreate(&sp.WebhookItem{Name: "bounces"}); err == nil {
+		t.Error("expected an error without a Target")
+	}
+	id, _, err := client.WebhookCreate(hook)
+	if err != nil || id != "12affc24" {
+		t.Fatalf("unexpected id %q, %v", id, err)
+	}
+	if bodies[0]["id"] != nil || bodies[0]["target"] != "https://example.com/hook" {
+		t.Errorf("unexpected create body %v", bodies[0])
+	}
+
+	hook.ID = id
+	if _, err = client.WebhookUpdate(hook); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.WebhookUpdate(&sp.WebhookItem{Name: "bounces"}); err == nil {
+		t.Error("expected an error for a blank id")
+	}
+	// a failure without an errors body is still an error
+	if _, err = client.WebhookUpdate(&sp.WebhookItem{ID: "broken"}); err == nil {
+		t.Error("expected an error for a 500")
+	}
+}